@@ -0,0 +1,690 @@
+package gguf
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ArchConfig is the hyperparameters of a model, decoded from the
+// metadata by Metadata.ArchConfig(). Use a type switch to get the
+// config of a specific architecture, like *LlamaConfig.
+type ArchConfig interface {
+	// Arch returns the name of the architecture, as found in
+	// general.architecture.
+	Arch() string
+
+	// decode reads the config from d.
+	decode(d *archDecoder)
+}
+
+// archConfigs is the registry of supported architectures. Related
+// architectures share a config type.
+var archConfigs = map[string]func() ArchConfig{
+	"llama":      func() ArchConfig { return &LlamaConfig{} },
+	"qwen2":      func() ArchConfig { return &Qwen2Config{} },
+	"qwen2moe":   func() ArchConfig { return &Qwen2Config{} },
+	"qwen3":      func() ArchConfig { return &Qwen2Config{} },
+	"gemma":      func() ArchConfig { return &GemmaConfig{} },
+	"gemma2":     func() ArchConfig { return &GemmaConfig{} },
+	"phi2":       func() ArchConfig { return &Phi2Config{} },
+	"phi3":       func() ArchConfig { return &Phi3Config{} },
+	"falcon":     func() ArchConfig { return &FalconConfig{} },
+	"mpt":        func() ArchConfig { return &MPTConfig{} },
+	"gpt2":       func() ArchConfig { return &GPT2Config{} },
+	"bert":       func() ArchConfig { return &BertConfig{} },
+	"nomic-bert": func() ArchConfig { return &BertConfig{} },
+	"starcoder":  func() ArchConfig { return &StarCoderConfig{} },
+	"starcoder2": func() ArchConfig { return &StarCoder2Config{} },
+	"mamba":      func() ArchConfig { return &MambaConfig{} },
+}
+
+// Architectures returns the names of all architectures supported by
+// Metadata.ArchConfig(), sorted.
+func Architectures() []string {
+	names := make([]string, 0, len(archConfigs))
+	for name := range archConfigs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// MissingKeysError is returned by Metadata.ArchConfig() and
+// Metadata.Unmarshal() if required keys are missing.
+type MissingKeysError struct {
+	// Architecture is the architecture being decoded, if known.
+	Architecture string
+
+	// Keys is the full names of all missing keys.
+	Keys []string
+}
+
+// Error implements error.
+func (e *MissingKeysError) Error() string {
+	msg := "missing required metadata: " + strings.Join(e.Keys, ", ")
+
+	if e.Architecture == "" {
+		return msg
+	}
+
+	return e.Architecture + ": " + msg
+}
+
+// ArchConfig decodes the hyperparameters of the model, using the
+// architecture from general.architecture. All missing required keys
+// are reported in a *MissingKeysError.
+func (m Metadata) ArchConfig() (ArchConfig, error) {
+	arch, err := m.String("general.architecture")
+	if err != nil {
+		return nil, err
+	}
+
+	newConfig, found := archConfigs[arch]
+	if !found {
+		return nil, fmt.Errorf("unsupported architecture: %q", arch)
+	}
+
+	c, d := m.decodeArch(arch, newConfig)
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if len(d.missing) > 0 {
+		return nil, &MissingKeysError{Architecture: arch, Keys: d.missing}
+	}
+
+	return c, nil
+}
+
+// decodeArch decodes the hyperparameters of arch. The returned decoder
+// holds the missing required keys and the first invalid value, which
+// are collected independently of each other.
+func (m Metadata) decodeArch(arch string, newConfig func() ArchConfig) (ArchConfig, *archDecoder) {
+	c := newConfig()
+	d := &archDecoder{m: m, arch: arch}
+
+	c.decode(d)
+
+	return c, d
+}
+
+// ArchBase is the hyperparameters common to all architectures.
+type ArchBase struct {
+	// Architecture is the value of general.architecture.
+	Architecture string
+
+	// ContextLength is the context length the model was trained with.
+	ContextLength int
+
+	// EmbeddingLength is the size of the embeddings.
+	EmbeddingLength int
+
+	// BlockCount is the number of layers.
+	BlockCount int
+
+	// VocabSize is the number of tokens in the vocabulary. It's
+	// taken from {arch}.vocab_size if present, or the number of
+	// tokens in tokenizer.ggml.tokens.
+	VocabSize int
+}
+
+// Arch implements ArchConfig.
+func (c *ArchBase) Arch() string {
+	return c.Architecture
+}
+
+func (c *ArchBase) decode(d *archDecoder) {
+	c.Architecture = d.arch
+	c.ContextLength = d.int("context_length", true)
+	c.EmbeddingLength = d.int("embedding_length", true)
+	c.BlockCount = d.int("block_count", true)
+	c.VocabSize = d.int("vocab_size", false)
+
+	// Per-layer values are allocated from the block count, so it's
+	// checked before it's used.
+	if c.BlockCount > maxBlockCount {
+		d.fail(fmt.Errorf("metadata value %q is %d, more than the maximum of %d layers", d.arch+".block_count", c.BlockCount, maxBlockCount))
+	} else {
+		d.blocks = c.BlockCount
+	}
+
+	if c.VocabSize == 0 {
+		if tokens, ok := d.m["tokenizer.ggml.tokens"].([]string); ok {
+			c.VocabSize = len(tokens)
+		}
+	}
+}
+
+// TransformerConfig is the hyperparameters common to transformer
+// architectures. Values that can differ between layers are stored
+// with one element per layer, also if the file stores a single value.
+type TransformerConfig struct {
+	ArchBase
+
+	// FeedForwardLength is the size of the feed forward layer of
+	// each layer.
+	FeedForwardLength []int
+
+	// HeadCount is the number of attention heads of each layer.
+	HeadCount []int
+
+	// HeadCountKV is the number of key/value heads of each layer. It
+	// equals HeadCount if not present.
+	HeadCountKV []int
+
+	// KeyLength and ValueLength are the size of each key and value
+	// head. They default to EmbeddingLength divided by the number of
+	// heads of the first layer.
+	KeyLength   int
+	ValueLength int
+}
+
+func (c *TransformerConfig) decode(d *archDecoder) {
+	c.ArchBase.decode(d)
+
+	c.FeedForwardLength = d.perLayer("feed_forward_length", true)
+	c.HeadCount = d.perLayer("attention.head_count", true)
+	c.HeadCountKV = d.perLayer("attention.head_count_kv", false)
+	c.KeyLength = d.int("attention.key_length", false)
+	c.ValueLength = d.int("attention.value_length", false)
+
+	if c.HeadCountKV == nil {
+		c.HeadCountKV = c.HeadCount
+	}
+
+	if len(c.HeadCount) > 0 && c.HeadCount[0] > 0 {
+		if c.KeyLength == 0 {
+			c.KeyLength = c.EmbeddingLength / c.HeadCount[0]
+		}
+
+		if c.ValueLength == 0 {
+			c.ValueLength = c.EmbeddingLength / c.HeadCount[0]
+		}
+	}
+}
+
+// RopeConfig is the rotary position embedding parameters.
+type RopeConfig struct {
+	// DimensionCount is the number of rotated dimensions. It defaults
+	// to the key length.
+	DimensionCount int
+
+	// FreqBase is the base frequency. It defaults to 10000.
+	FreqBase float64
+
+	// ScalingType is the type of scaling, like "linear" or "yarn".
+	// It's empty if no scaling is used.
+	ScalingType string
+
+	// ScalingFactor is the scaling factor.
+	ScalingFactor float64
+
+	// ScalingOriginalContextLength is the original context length of
+	// the model before scaling.
+	ScalingOriginalContextLength int
+}
+
+// decode reads the rope parameters. keyLength is the default
+// dimension count.
+func (c *RopeConfig) decode(d *archDecoder, keyLength int) {
+	c.DimensionCount = d.int("rope.dimension_count", false)
+	c.FreqBase = d.float("rope.freq_base", false)
+	c.ScalingType = d.string("rope.scaling.type", false)
+	c.ScalingFactor = d.float("rope.scaling.factor", false)
+	c.ScalingOriginalContextLength = d.int("rope.scaling.original_context_length", false)
+
+	if c.DimensionCount == 0 {
+		c.DimensionCount = keyLength
+	}
+
+	if c.FreqBase == 0 {
+		c.FreqBase = 10000
+	}
+}
+
+// ExpertConfig is the parameters of mixture of experts models. All
+// values are zero for dense models.
+type ExpertConfig struct {
+	// ExpertCount is the number of experts.
+	ExpertCount int
+
+	// ExpertUsedCount is the number of experts used for each token.
+	ExpertUsedCount int
+}
+
+func (c *ExpertConfig) decode(d *archDecoder) {
+	c.ExpertCount = d.int("expert_count", false)
+	c.ExpertUsedCount = d.int("expert_used_count", false)
+}
+
+// LlamaConfig is the hyperparameters of the llama architecture.
+type LlamaConfig struct {
+	TransformerConfig
+	ExpertConfig
+
+	Rope RopeConfig
+
+	// LayerNormRMSEpsilon is the epsilon of the RMS norms.
+	LayerNormRMSEpsilon float64
+}
+
+func (c *LlamaConfig) decode(d *archDecoder) {
+	c.TransformerConfig.decode(d)
+	c.ExpertConfig.decode(d)
+	c.Rope.decode(d, c.KeyLength)
+
+	c.LayerNormRMSEpsilon = d.float("attention.layer_norm_rms_epsilon", true)
+}
+
+// Qwen2Config is the hyperparameters of the qwen2, qwen2moe and qwen3
+// architectures.
+type Qwen2Config struct {
+	LlamaConfig
+
+	// ExpertFeedForwardLength is the feed forward size of each
+	// expert, for qwen2moe.
+	ExpertFeedForwardLength int
+
+	// ExpertSharedFeedForwardLength is the feed forward size of the
+	// shared expert, for qwen2moe.
+	ExpertSharedFeedForwardLength int
+}
+
+func (c *Qwen2Config) decode(d *archDecoder) {
+	c.LlamaConfig.decode(d)
+
+	c.ExpertFeedForwardLength = d.int("expert_feed_forward_length", false)
+	c.ExpertSharedFeedForwardLength = d.int("expert_shared_feed_forward_length", false)
+}
+
+// GemmaConfig is the hyperparameters of the gemma and gemma2
+// architectures.
+type GemmaConfig struct {
+	TransformerConfig
+
+	Rope RopeConfig
+
+	// LayerNormRMSEpsilon is the epsilon of the RMS norms.
+	LayerNormRMSEpsilon float64
+
+	// SlidingWindow is the size of the sliding attention window,
+	// for gemma2.
+	SlidingWindow int
+
+	// AttnLogitSoftcapping and FinalLogitSoftcapping are the soft
+	// capping of the attention and output logits, for gemma2.
+	AttnLogitSoftcapping  float64
+	FinalLogitSoftcapping float64
+}
+
+func (c *GemmaConfig) decode(d *archDecoder) {
+	c.TransformerConfig.decode(d)
+	c.Rope.decode(d, c.KeyLength)
+
+	c.LayerNormRMSEpsilon = d.float("attention.layer_norm_rms_epsilon", true)
+	c.SlidingWindow = d.int("attention.sliding_window", false)
+	c.AttnLogitSoftcapping = d.float("attn_logit_softcapping", false)
+	c.FinalLogitSoftcapping = d.float("final_logit_softcapping", false)
+}
+
+// Phi2Config is the hyperparameters of the phi2 architecture.
+type Phi2Config struct {
+	TransformerConfig
+
+	Rope RopeConfig
+
+	// LayerNormEpsilon is the epsilon of the layer norms.
+	LayerNormEpsilon float64
+}
+
+func (c *Phi2Config) decode(d *archDecoder) {
+	c.TransformerConfig.decode(d)
+	c.Rope.decode(d, c.KeyLength)
+
+	c.LayerNormEpsilon = d.float("attention.layer_norm_epsilon", true)
+}
+
+// Phi3Config is the hyperparameters of the phi3 architecture.
+type Phi3Config struct {
+	TransformerConfig
+
+	Rope RopeConfig
+
+	// LayerNormRMSEpsilon is the epsilon of the RMS norms.
+	LayerNormRMSEpsilon float64
+
+	// SlidingWindow is the size of the sliding attention window, if
+	// any.
+	SlidingWindow int
+}
+
+func (c *Phi3Config) decode(d *archDecoder) {
+	c.TransformerConfig.decode(d)
+	c.Rope.decode(d, c.KeyLength)
+
+	c.LayerNormRMSEpsilon = d.float("attention.layer_norm_rms_epsilon", true)
+	c.SlidingWindow = d.int("attention.sliding_window", false)
+}
+
+// FalconConfig is the hyperparameters of the falcon architecture.
+type FalconConfig struct {
+	TransformerConfig
+
+	Rope RopeConfig
+
+	// LayerNormEpsilon is the epsilon of the layer norms.
+	LayerNormEpsilon float64
+}
+
+func (c *FalconConfig) decode(d *archDecoder) {
+	c.TransformerConfig.decode(d)
+	c.Rope.decode(d, c.KeyLength)
+
+	c.LayerNormEpsilon = d.float("attention.layer_norm_epsilon", true)
+}
+
+// MPTConfig is the hyperparameters of the mpt architecture.
+type MPTConfig struct {
+	TransformerConfig
+
+	// LayerNormEpsilon is the epsilon of the layer norms.
+	LayerNormEpsilon float64
+
+	// MaxAlibiBias is the maximum ALiBi bias.
+	MaxAlibiBias float64
+
+	// ClampKQV clamps the KQV values to ±ClampKQV, if not zero.
+	ClampKQV float64
+}
+
+func (c *MPTConfig) decode(d *archDecoder) {
+	c.TransformerConfig.decode(d)
+
+	c.LayerNormEpsilon = d.float("attention.layer_norm_epsilon", true)
+	c.MaxAlibiBias = d.float("attention.max_alibi_bias", false)
+	c.ClampKQV = d.float("attention.clamp_kqv", false)
+}
+
+// GPT2Config is the hyperparameters of the gpt2 architecture.
+type GPT2Config struct {
+	TransformerConfig
+
+	// LayerNormEpsilon is the epsilon of the layer norms.
+	LayerNormEpsilon float64
+}
+
+func (c *GPT2Config) decode(d *archDecoder) {
+	c.TransformerConfig.decode(d)
+
+	c.LayerNormEpsilon = d.float("attention.layer_norm_epsilon", true)
+}
+
+// BertConfig is the hyperparameters of the bert and nomic-bert
+// architectures.
+type BertConfig struct {
+	TransformerConfig
+
+	Rope RopeConfig
+
+	// LayerNormEpsilon is the epsilon of the layer norms.
+	LayerNormEpsilon float64
+
+	// Causal is true if the attention is causal. Embedding models
+	// usually are not.
+	Causal bool
+
+	// PoolingType is the llama.cpp pooling type of the embeddings.
+	PoolingType int
+}
+
+func (c *BertConfig) decode(d *archDecoder) {
+	c.TransformerConfig.decode(d)
+	c.Rope.decode(d, c.KeyLength)
+
+	c.LayerNormEpsilon = d.float("attention.layer_norm_epsilon", true)
+	c.Causal = d.bool("attention.causal", false)
+	c.PoolingType = d.int("pooling_type", false)
+}
+
+// StarCoderConfig is the hyperparameters of the starcoder
+// architecture.
+type StarCoderConfig struct {
+	TransformerConfig
+
+	// LayerNormEpsilon is the epsilon of the layer norms.
+	LayerNormEpsilon float64
+}
+
+func (c *StarCoderConfig) decode(d *archDecoder) {
+	c.TransformerConfig.decode(d)
+
+	c.LayerNormEpsilon = d.float("attention.layer_norm_epsilon", true)
+}
+
+// StarCoder2Config is the hyperparameters of the starcoder2
+// architecture.
+type StarCoder2Config struct {
+	TransformerConfig
+
+	Rope RopeConfig
+
+	// LayerNormEpsilon is the epsilon of the layer norms.
+	LayerNormEpsilon float64
+}
+
+func (c *StarCoder2Config) decode(d *archDecoder) {
+	c.TransformerConfig.decode(d)
+	c.Rope.decode(d, c.KeyLength)
+
+	c.LayerNormEpsilon = d.float("attention.layer_norm_epsilon", true)
+}
+
+// MambaConfig is the hyperparameters of the mamba architecture.
+type MambaConfig struct {
+	ArchBase
+
+	// SSMConvKernel is the size of the convolution kernel.
+	SSMConvKernel int
+
+	// SSMInnerSize is the size of the inner state.
+	SSMInnerSize int
+
+	// SSMStateSize is the size of the recurrent state.
+	SSMStateSize int
+
+	// SSMTimeStepRank is the rank of the time step projection.
+	SSMTimeStepRank int
+
+	// LayerNormRMSEpsilon is the epsilon of the RMS norms.
+	LayerNormRMSEpsilon float64
+}
+
+func (c *MambaConfig) decode(d *archDecoder) {
+	c.ArchBase.decode(d)
+
+	c.SSMConvKernel = d.int("ssm.conv_kernel", true)
+	c.SSMInnerSize = d.int("ssm.inner_size", true)
+	c.SSMStateSize = d.int("ssm.state_size", true)
+	c.SSMTimeStepRank = d.int("ssm.time_step_rank", true)
+	c.LayerNormRMSEpsilon = d.float("attention.layer_norm_rms_epsilon", true)
+}
+
+// maxBlockCount is the largest {arch}.block_count accepted. It's far
+// above the number of layers of any real model.
+const maxBlockCount = 1 << 16
+
+// archDecoder reads architecture specific values from the metadata.
+// Missing required keys are collected, so all of them can be reported
+// at once. Only the first invalid value is kept.
+type archDecoder struct {
+	m    Metadata
+	arch string
+
+	// blocks is the number of layers, used for per-layer values.
+	blocks int
+
+	missing []string
+	err     error
+}
+
+// lookup returns the value of {arch}.suffix, and records it as missing
+// if required.
+func (d *archDecoder) lookup(suffix string, required bool) (string, interface{}, bool) {
+	key := d.arch + "." + suffix
+
+	v, found := d.m[key]
+	if !found && required {
+		d.missing = append(d.missing, key)
+	}
+
+	return key, v, found
+}
+
+// fail records err, unless an error is already recorded.
+func (d *archDecoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// toInt converts an integer value to an int, if it's not negative and
+// fits.
+func toInt(v reflect.Value) (int, bool) {
+	switch v.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt {
+			return 0, false
+		}
+
+		return int(v.Uint()), true
+
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() < 0 || v.Int() > math.MaxInt {
+			return 0, false
+		}
+
+		return int(v.Int()), true
+
+	default:
+		return 0, false
+	}
+}
+
+func (d *archDecoder) int(suffix string, required bool) int {
+	key, v, found := d.lookup(suffix, required)
+	if !found {
+		return 0
+	}
+
+	i, ok := toInt(reflect.ValueOf(v))
+	if !ok {
+		d.fail(fmt.Errorf("metadata value %q is not a non-negative integer: %v (%T)", key, v, v))
+	}
+
+	return i
+}
+
+func (d *archDecoder) float(suffix string, required bool) float64 {
+	key, v, found := d.lookup(suffix, required)
+	if !found {
+		return 0
+	}
+
+	switch f := v.(type) {
+	case float32:
+		return float64(f)
+
+	case float64:
+		return f
+	}
+
+	i, ok := toInt(reflect.ValueOf(v))
+	if !ok {
+		d.fail(fmt.Errorf("metadata value %q is not a number: %v (%T)", key, v, v))
+	}
+
+	return float64(i)
+}
+
+func (d *archDecoder) string(suffix string, required bool) string {
+	key, v, found := d.lookup(suffix, required)
+	if !found {
+		return ""
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		d.fail(fmt.Errorf("metadata value %q is not a string, type is %T", key, v))
+	}
+
+	return s
+}
+
+func (d *archDecoder) bool(suffix string, required bool) bool {
+	key, v, found := d.lookup(suffix, required)
+	if !found {
+		return false
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		d.fail(fmt.Errorf("metadata value %q is not a bool, type is %T", key, v))
+	}
+
+	return b
+}
+
+// perLayer reads a value that can be stored either as a single value
+// for all layers, or as an array with one value per layer. The result
+// always has one element per layer.
+func (d *archDecoder) perLayer(suffix string, required bool) []int {
+	key, v, found := d.lookup(suffix, required)
+	if !found {
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Slice {
+		i, ok := toInt(rv)
+		if !ok {
+			d.fail(fmt.Errorf("metadata value %q is not a non-negative integer: %v (%T)", key, v, v))
+
+			return nil
+		}
+
+		values := make([]int, d.blocks)
+		for l := range values {
+			values[l] = i
+		}
+
+		return values
+	}
+
+	if rv.Len() != d.blocks {
+		d.fail(fmt.Errorf("metadata value %q has %d values, expected one for each of the %d layers", key, rv.Len(), d.blocks))
+
+		return nil
+	}
+
+	values := make([]int, rv.Len())
+
+	for l := range values {
+		i, ok := toInt(rv.Index(l))
+		if !ok {
+			d.fail(fmt.Errorf("metadata value %q has invalid value for layer %d: %v", key, l, rv.Index(l)))
+
+			return nil
+		}
+
+		values[l] = i
+	}
+
+	return values
+}
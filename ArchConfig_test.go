@@ -0,0 +1,53 @@
+package gguf
+
+import (
+	"testing"
+)
+
+// llamaMetadata returns the metadata of a small llama model with the
+// given block count.
+func llamaMetadata(blockCount interface{}) Metadata {
+	return Metadata{
+		"general.architecture":                   "llama",
+		"llama.context_length":                   uint32(4096),
+		"llama.embedding_length":                 uint32(64),
+		"llama.block_count":                      blockCount,
+		"llama.feed_forward_length":              uint32(128),
+		"llama.attention.head_count":             uint32(4),
+		"llama.attention.layer_norm_rms_epsilon": float32(1e-5),
+	}
+}
+
+func TestArchConfigBlockCount(t *testing.T) {
+	c, err := llamaMetadata(uint32(2)).ArchConfig()
+	if err != nil {
+		t.Fatalf("valid block count: %s", err)
+	}
+
+	if got := len(c.(*LlamaConfig).HeadCount); got != 2 {
+		t.Errorf("valid block count: %d head counts, expected 2", got)
+	}
+
+	invalid := []interface{}{
+		int32(-1),
+		int64(-1 << 40),
+		uint32(1<<32 - 1),
+		uint64(1 << 40),
+		uint64(1<<64 - 1),
+	}
+
+	for _, blockCount := range invalid {
+		_, err := llamaMetadata(blockCount).ArchConfig()
+		if err == nil {
+			t.Errorf("block count %v: expected an error", blockCount)
+		}
+	}
+
+	m := llamaMetadata(uint32(2))
+	m["llama.attention.head_count"] = make([]uint32, 1<<20)
+
+	_, err = m.ArchConfig()
+	if err == nil {
+		t.Errorf("block count 2 with %d head counts: expected an error", 1<<20)
+	}
+}
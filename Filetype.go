@@ -1,5 +1,10 @@
 package gguf
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Filetype is the type of the majority of the tensors in the file.
 type Filetype uint32
 
@@ -23,8 +28,65 @@ const (
 	MostlyQ5_KS       Filetype = 16
 	MostlyQ5_KM       Filetype = 17
 	MostlyQ6_K        Filetype = 18
+	MostlyIQ2_XXS     Filetype = 19
+	MostlyIQ2_XS      Filetype = 20
+	MostlyQ2_KS       Filetype = 21
+	MostlyIQ3_XS      Filetype = 22
+	MostlyIQ3_XXS     Filetype = 23
+	MostlyIQ1_S       Filetype = 24
+	MostlyIQ4_NL      Filetype = 25
+	MostlyIQ3_S       Filetype = 26
+	MostlyIQ3_M       Filetype = 27
+	MostlyIQ2_S       Filetype = 28
+	MostlyIQ2_M       Filetype = 29
+	MostlyIQ4_XS      Filetype = 30
+	MostlyIQ1_M       Filetype = 31
+	MostlyBF16        Filetype = 32
+	MostlyQ4_0_4_4    Filetype = 33 // support removed from llama.cpp/ggml
+	MostlyQ4_0_4_8    Filetype = 34 // support removed from llama.cpp/ggml
+	MostlyQ4_0_8_8    Filetype = 35 // support removed from llama.cpp/ggml
+	MostlyTQ1_0       Filetype = 36
+	MostlyTQ2_0       Filetype = 37
 )
 
+// filetypeGGML maps a file type to the type of the majority of the
+// weights in such a file.
+var filetypeGGML = map[Filetype]GGML{
+	AllF32:            GgmlFloat32,
+	MostlyF16:         GgmlFloat16,
+	MostlyQ4_0:        GgmlQ4_0,
+	MostlyQ4_1:        GgmlQ4_1,
+	MostlyQ4_1SomeF16: GgmlQ4_1,
+	MostlyQ8_0:        GgmlQ8_0,
+	MostlyQ5_0:        GgmlQ5_0,
+	MostlyQ5_1:        GgmlQ5_1,
+	MostlyQ2_K:        GgmlQ2_K,
+	MostlyQ3_KS:       GgmlQ3_K,
+	MostlyQ3_KM:       GgmlQ3_K,
+	MostlyQ3_KL:       GgmlQ3_K,
+	MostlyQ4_KS:       GgmlQ4_K,
+	MostlyQ4_KM:       GgmlQ4_K,
+	MostlyQ5_KS:       GgmlQ5_K,
+	MostlyQ5_KM:       GgmlQ5_K,
+	MostlyQ6_K:        GgmlQ6_K,
+	MostlyIQ2_XXS:     GgmlIQ2_XXS,
+	MostlyIQ2_XS:      GgmlIQ2_XS,
+	MostlyQ2_KS:       GgmlQ2_K,
+	MostlyIQ3_XS:      GgmlIQ3_S,
+	MostlyIQ3_XXS:     GgmlIQ3_XXS,
+	MostlyIQ1_S:       GgmlIQ1_S,
+	MostlyIQ4_NL:      GgmlIQ4_NL,
+	MostlyIQ3_S:       GgmlIQ3_S,
+	MostlyIQ3_M:       GgmlIQ3_S,
+	MostlyIQ2_S:       GgmlIQ2_XS,
+	MostlyIQ2_M:       GgmlIQ2_S,
+	MostlyIQ4_XS:      GgmlIQ4_XS,
+	MostlyIQ1_M:       GgmlIQ1_M,
+	MostlyBF16:        GgmlBFloat16,
+	MostlyTQ1_0:       GgmlTQ1_0,
+	MostlyTQ2_0:       GgmlTQ2_0,
+}
+
 var ftypeNames = map[Filetype]string{
 	AllF32:            "all F32",
 	MostlyF16:         "mostly F16",
@@ -45,10 +107,30 @@ var ftypeNames = map[Filetype]string{
 	MostlyQ5_KS:       "mostly Q5_K - Small",
 	MostlyQ5_KM:       "mostly Q5_K - Medium",
 	MostlyQ6_K:        "mostly Q6_K",
+	MostlyIQ2_XXS:     "IQ2_XXS - 2.0625 bpw",
+	MostlyIQ2_XS:      "IQ2_XS - 2.3125 bpw",
+	MostlyQ2_KS:       "Q2_K - Small",
+	MostlyIQ3_XS:      "IQ3_XS - 3.3 bpw",
+	MostlyIQ3_XXS:     "IQ3_XXS - 3.0625 bpw",
+	MostlyIQ1_S:       "IQ1_S - 1.5625 bpw",
+	MostlyIQ4_NL:      "IQ4_NL - 4.5 bpw",
+	MostlyIQ3_S:       "IQ3_S - 3.4375 bpw",
+	MostlyIQ3_M:       "IQ3_S mix - 3.66 bpw",
+	MostlyIQ2_S:       "IQ2_S - 2.5 bpw",
+	MostlyIQ2_M:       "IQ2_M - 2.7 bpw",
+	MostlyIQ4_XS:      "IQ4_XS - 4.25 bpw",
+	MostlyIQ1_M:       "IQ1_M - 1.75 bpw",
+	MostlyBF16:        "BF16",
+	MostlyQ4_0_4_4:    "Q4_0_4_4",
+	MostlyQ4_0_4_8:    "Q4_0_4_8",
+	MostlyQ4_0_8_8:    "Q4_0_8_8",
+	MostlyTQ1_0:       "TQ1_0 - 1.69 bpw ternary",
+	MostlyTQ2_0:       "TQ2_0 - 2.06 bpw ternary",
 }
 
 // String return a string representation of the Filetype. All strings are
-// matched to those used in llama.cpp.
+// matched to those used in llama.cpp. Newer versions of llama.cpp dropped
+// the "mostly" prefix, which is only kept for the older types.
 func (f Filetype) String() string {
 	name, found := ftypeNames[f]
 	if found {
@@ -57,3 +139,163 @@ func (f Filetype) String() string {
 
 	return "UNKNOWN"
 }
+
+// isOutputTensor returns true for the token embeddings and the output
+// tensor, which llama.cpp quantizes differently from the other weights.
+func isOutputTensor(name string) bool {
+	return name == "token_embd.weight" || name == "output.weight"
+}
+
+// InferFiletype derives the llama.cpp file type from the types of the
+// weight tensors, for files without general.file_type or to check it.
+// The most common type, by number of values, among the weights decides
+// the base type. For the k-quants and i-quants the variant is derived
+// from the types llama.cpp uses for the more important tensors, like
+// Q6_K for some attn_v and ffn_down tensors in Q4_K_M but not in Q4_K_S.
+// This is a heuristic, and files quantized with custom tensor types
+// may be reported as a different variant.
+func (r *Reader) InferFiletype() (Filetype, error) {
+	values := make(map[GGML]float64)
+	others := make(map[GGML]bool)
+
+	for i := range r.Tensors {
+		t := &r.Tensors[i]
+
+		// Norms and biases are kept as floats, only matrices are
+		// quantized.
+		if len(t.Dimensions) < 2 || isOutputTensor(t.Name) {
+			continue
+		}
+
+		n := 1.0
+		for _, d := range t.Dimensions {
+			n *= float64(d)
+		}
+
+		values[t.Type] += n
+	}
+
+	if len(values) == 0 {
+		return 0, fmt.Errorf("no weight tensors to infer the file type from")
+	}
+
+	base, most := GGML(0), -1.0
+
+	for g, n := range values {
+		if n > most || (n == most && g < base) {
+			base, most = g, n
+		}
+	}
+
+	for g := range values {
+		if g != base {
+			others[g] = true
+		}
+	}
+
+	switch base {
+	case GgmlFloat32:
+		return AllF32, nil
+
+	case GgmlQ2_K:
+		// Q2_K uses Q3_K for ffn_down. Q2_K_S only uses Q4_K, for
+		// attn_v and the first layers of ffn_down.
+		if others[GgmlQ3_K] {
+			return MostlyQ2_K, nil
+		}
+
+		return MostlyQ2_KS, nil
+
+	case GgmlQ3_K:
+		// Mixture of experts models use Q5_K for attn_output also in
+		// Q3_K_S and Q3_K_M, so only ffn_down is considered.
+		switch {
+		case r.allTensorsOfType("ffn_down", GgmlQ5_K):
+			return MostlyQ3_KL, nil
+
+		case r.tensorsOfType("ffn_down", GgmlQ4_K) || r.tensorsOfType("ffn_down", GgmlQ5_K):
+			return MostlyQ3_KM, nil
+
+		default:
+			return MostlyQ3_KS, nil
+		}
+
+	case GgmlQ4_K:
+		if others[GgmlQ6_K] {
+			return MostlyQ4_KM, nil
+		}
+
+		return MostlyQ4_KS, nil
+
+	case GgmlQ5_K:
+		if others[GgmlQ6_K] {
+			return MostlyQ5_KM, nil
+		}
+
+		return MostlyQ5_KS, nil
+
+	case GgmlIQ2_XS:
+		if others[GgmlIQ3_S] {
+			return MostlyIQ2_S, nil
+		}
+
+		return MostlyIQ2_XS, nil
+
+	case GgmlIQ3_S:
+		switch {
+		case others[GgmlQ4_K]:
+			return MostlyIQ3_M, nil
+
+		case others[GgmlIQ3_XXS]:
+			return MostlyIQ3_XS, nil
+
+		default:
+			return MostlyIQ3_S, nil
+		}
+	}
+
+	for _, ftype := range []Filetype{
+		MostlyF16, MostlyBF16, MostlyQ4_0, MostlyQ4_1, MostlyQ5_0, MostlyQ5_1, MostlyQ8_0, MostlyQ6_K,
+		MostlyIQ2_XXS, MostlyIQ3_XXS, MostlyIQ1_S, MostlyIQ1_M, MostlyIQ2_M, MostlyIQ4_NL, MostlyIQ4_XS,
+		MostlyTQ1_0, MostlyTQ2_0,
+	} {
+		if filetypeGGML[ftype] == base {
+			return ftype, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no file type has mostly %s weights", base)
+}
+
+// tensorsOfType returns true if any tensor with a name containing part
+// is of type typ. Names are matched like llama.cpp does, so "ffn_down"
+// also matches the ffn_down_exps tensors of mixture of experts models.
+func (r *Reader) tensorsOfType(part string, typ GGML) bool {
+	for i := range r.Tensors {
+		if strings.Contains(r.Tensors[i].Name, part) && r.Tensors[i].Type == typ {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allTensorsOfType returns true if there are tensors with a name
+// containing part, and all of them are of type typ.
+func (r *Reader) allTensorsOfType(part string, typ GGML) bool {
+	found := false
+
+	for i := range r.Tensors {
+		if !strings.Contains(r.Tensors[i].Name, part) {
+			continue
+		}
+
+		if r.Tensors[i].Type != typ {
+			return false
+		}
+
+		found = true
+	}
+
+	return found
+}
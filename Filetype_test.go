@@ -0,0 +1,167 @@
+package gguf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// kQuantType returns the type llama_tensor_get_type() in llama.cpp's
+// llama-quant.cpp picks for a tensor of layer i of a llama model with
+// n layers, for the K-quant file types. Rules specific to Falcon, 70B
+// models and quantization with an imatrix are left out.
+func kQuantType(ftype Filetype, name string, i, n, gqa, experts int) GGML {
+	useMoreBits := i < n/8 || i >= 7*n/8 || (i-n/8)%3 == 2
+	typ := filetypeGGML[ftype]
+
+	switch {
+	case name == "output.weight":
+		typ = GgmlQ6_K
+
+	case strings.Contains(name, "attn_v.weight"):
+		switch {
+		case ftype == MostlyQ2_K && gqa >= 4:
+			typ = GgmlQ4_K
+		case ftype == MostlyQ2_K:
+			typ = GgmlQ3_K
+		case ftype == MostlyQ2_KS && gqa >= 4:
+			typ = GgmlQ4_K
+		case ftype == MostlyQ3_KM && i < 2:
+			typ = GgmlQ5_K
+		case ftype == MostlyQ3_KM:
+			typ = GgmlQ4_K
+		case ftype == MostlyQ3_KL:
+			typ = GgmlQ5_K
+		case (ftype == MostlyQ4_KM || ftype == MostlyQ5_KM) && useMoreBits:
+			typ = GgmlQ6_K
+		case ftype == MostlyQ4_KS && i < 4:
+			typ = GgmlQ5_K
+		}
+
+		if experts == 8 {
+			typ = GgmlQ8_0
+		}
+
+	case strings.Contains(name, "attn_k.weight"):
+		if experts == 8 {
+			typ = GgmlQ8_0
+		}
+
+	case strings.Contains(name, "ffn_down"):
+		switch {
+		case ftype == MostlyQ2_K:
+			typ = GgmlQ3_K
+		case ftype == MostlyQ2_KS && i < n/8:
+			typ = GgmlQ4_K
+		case ftype == MostlyQ3_KM && i < n/16:
+			typ = GgmlQ5_K
+		case ftype == MostlyQ3_KM:
+			typ = GgmlQ4_K
+		case ftype == MostlyQ3_KL:
+			typ = GgmlQ5_K
+		case (ftype == MostlyQ4_KM || ftype == MostlyQ5_KM) && useMoreBits:
+			typ = GgmlQ6_K
+		case ftype == MostlyQ4_KS && i < n/8:
+			typ = GgmlQ5_K
+		}
+
+	case strings.Contains(name, "attn_output.weight"):
+		if experts == 8 {
+			switch ftype {
+			case MostlyQ2_K, MostlyQ3_KS, MostlyQ3_KM, MostlyQ4_KS, MostlyQ4_KM:
+				typ = GgmlQ5_K
+			}
+		} else {
+			switch ftype {
+			case MostlyQ2_K:
+				typ = GgmlQ3_K
+			case MostlyQ3_KM:
+				typ = GgmlQ4_K
+			case MostlyQ3_KL:
+				typ = GgmlQ5_K
+			}
+		}
+	}
+
+	return typ
+}
+
+// llamaTensors returns the tensors of a llama model quantized as ftype.
+func llamaTensors(ftype Filetype, layers, gqa, experts int) []TensorInfo {
+	const embd, ff = 4096, 11008
+
+	tensors := []TensorInfo{
+		{Name: "token_embd.weight", Dimensions: []uint64{embd, 32000}, Type: filetypeGGML[ftype]},
+		{Name: "output_norm.weight", Dimensions: []uint64{embd}, Type: GgmlFloat32},
+		{Name: "output.weight", Dimensions: []uint64{embd, 32000}, Type: GgmlQ6_K},
+	}
+
+	for i := 0; i < layers; i++ {
+		add := func(name string, dimensions ...uint64) {
+			name = fmt.Sprintf("blk.%d.%s", i, name)
+
+			typ := GgmlFloat32
+			if len(dimensions) > 1 && !strings.Contains(name, "ffn_gate_inp") {
+				typ = kQuantType(ftype, name, i, layers, gqa, experts)
+			}
+
+			tensors = append(tensors, TensorInfo{Name: name, Dimensions: dimensions, Type: typ})
+		}
+
+		add("attn_norm.weight", embd)
+		add("attn_q.weight", embd, embd)
+		add("attn_k.weight", embd, uint64(embd/gqa))
+		add("attn_v.weight", embd, uint64(embd/gqa))
+		add("attn_output.weight", embd, embd)
+		add("ffn_norm.weight", embd)
+
+		if experts > 1 {
+			add("ffn_gate_inp.weight", embd, uint64(experts))
+			add("ffn_gate_exps.weight", embd, ff, uint64(experts))
+			add("ffn_up_exps.weight", embd, ff, uint64(experts))
+			add("ffn_down_exps.weight", ff, embd, uint64(experts))
+		} else {
+			add("ffn_gate.weight", embd, ff)
+			add("ffn_up.weight", embd, ff)
+			add("ffn_down.weight", ff, embd)
+		}
+	}
+
+	return tensors
+}
+
+func TestInferFiletypeKQuants(t *testing.T) {
+	ftypes := []Filetype{
+		MostlyQ2_K, MostlyQ2_KS,
+		MostlyQ3_KS, MostlyQ3_KM, MostlyQ3_KL,
+		MostlyQ4_KS, MostlyQ4_KM,
+		MostlyQ5_KS, MostlyQ5_KM,
+		MostlyQ6_K,
+	}
+
+	models := []struct {
+		name                 string
+		layers, gqa, experts int
+	}{
+		{"dense", 32, 1, 0},
+		{"dense gqa", 32, 8, 0},
+		{"small", 8, 4, 0},
+		{"moe", 32, 4, 8},
+	}
+
+	for _, ftype := range ftypes {
+		for _, m := range models {
+			r := &Reader{Tensors: llamaTensors(ftype, m.layers, m.gqa, m.experts)}
+
+			got, err := r.InferFiletype()
+			if err != nil {
+				t.Errorf("%s, %s: %s", ftype, m.name, err)
+				continue
+			}
+
+			if got != ftype {
+				t.Errorf("%s, %s: inferred %s", ftype, m.name, got)
+			}
+		}
+	}
+}
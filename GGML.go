@@ -2,29 +2,45 @@ package gguf
 
 import (
 	"fmt"
+	"strings"
 )
 
-// GGML is used to represent the encoding of tensor data.
+// GGML is used to represent the encoding of tensor data. The values
+// match the ggml_type enum in ggml.
 type GGML int
 
 const (
-	GgmlFloat32 GGML = 0
-	GgmlFloat16 GGML = 1
-	GgmlQ4_0    GGML = 2
-	GgmlQ4_1    GGML = 3
-	GgmlQ5_0    GGML = 6
-	GgmlQ5_1    GGML = 7
-	GgmlQ8_0    GGML = 8
-	GgmlQ8_1    GGML = 9
-	GgmlQ2_K    GGML = 10
-	GgmlQ3_K    GGML = 11
-	GgmlQ4_K    GGML = 12
-	GgmlQ5_K    GGML = 13
-	GgmlQ6_K    GGML = 14
-	GgmlQ8_K    GGML = 15
-	GgmlInt8    GGML = 16
-	GgmlInt16   GGML = 17
-	GgmlInt32   GGML = 18
+	GgmlFloat32  GGML = 0
+	GgmlFloat16  GGML = 1
+	GgmlQ4_0     GGML = 2
+	GgmlQ4_1     GGML = 3
+	GgmlQ5_0     GGML = 6
+	GgmlQ5_1     GGML = 7
+	GgmlQ8_0     GGML = 8
+	GgmlQ8_1     GGML = 9
+	GgmlQ2_K     GGML = 10
+	GgmlQ3_K     GGML = 11
+	GgmlQ4_K     GGML = 12
+	GgmlQ5_K     GGML = 13
+	GgmlQ6_K     GGML = 14
+	GgmlQ8_K     GGML = 15
+	GgmlIQ2_XXS  GGML = 16
+	GgmlIQ2_XS   GGML = 17
+	GgmlIQ3_XXS  GGML = 18
+	GgmlIQ1_S    GGML = 19
+	GgmlIQ4_NL   GGML = 20
+	GgmlIQ3_S    GGML = 21
+	GgmlIQ2_S    GGML = 22
+	GgmlIQ4_XS   GGML = 23
+	GgmlInt8     GGML = 24
+	GgmlInt16    GGML = 25
+	GgmlInt32    GGML = 26
+	GgmlInt64    GGML = 27
+	GgmlFloat64  GGML = 28
+	GgmlIQ1_M    GGML = 29
+	GgmlBFloat16 GGML = 30
+	GgmlTQ1_0    GGML = 34
+	GgmlTQ2_0    GGML = 35
 )
 
 // String returns the string representation of the encoding.
@@ -59,13 +75,65 @@ func (g GGML) String() string {
 		return "q6_k"
 	case GgmlQ8_K:
 		return "q8_k"
+	case GgmlIQ2_XXS:
+		return "iq2_xxs"
+	case GgmlIQ2_XS:
+		return "iq2_xs"
+	case GgmlIQ3_XXS:
+		return "iq3_xxs"
+	case GgmlIQ1_S:
+		return "iq1_s"
+	case GgmlIQ4_NL:
+		return "iq4_nl"
+	case GgmlIQ3_S:
+		return "iq3_s"
+	case GgmlIQ2_S:
+		return "iq2_s"
+	case GgmlIQ4_XS:
+		return "iq4_xs"
 	case GgmlInt8:
 		return "int8"
 	case GgmlInt16:
 		return "int16"
 	case GgmlInt32:
 		return "int32"
+	case GgmlInt64:
+		return "int64"
+	case GgmlFloat64:
+		return "float64"
+	case GgmlIQ1_M:
+		return "iq1_m"
+	case GgmlBFloat16:
+		return "bfloat16"
+	case GgmlTQ1_0:
+		return "tq1_0"
+	case GgmlTQ2_0:
+		return "tq2_0"
 	default:
 		return fmt.Sprintf("GGML(%d)", g)
 	}
 }
+
+// ParseGGML returns the GGML encoding with the given name, as returned
+// by String(). The match is case-insensitive, and the common short
+// names f32, f16 and bf16 are accepted as well.
+func ParseGGML(name string) (GGML, error) {
+	name = strings.ToLower(name)
+
+	switch name {
+	case "f32":
+		return GgmlFloat32, nil
+	case "f16":
+		return GgmlFloat16, nil
+	case "bf16":
+		return GgmlBFloat16, nil
+	}
+
+	for g := range sizes {
+		if g.String() == name {
+			return g, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown GGML type: %q", name)
+}
@@ -0,0 +1,154 @@
+package gguf
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Header is everything in a GGUF file but the tensor data. It can be
+// encoded to JSON for review and diffing. Every metadata value keeps
+// its GGUF type, so a header decoded from JSON produces the same
+// binary header as the file it was taken from.
+type Header struct {
+	// Version is the GGUF version.
+	Version int `json:"version"`
+
+	// ByteOrder is either "little" or "big".
+	ByteOrder string `json:"byte_order"`
+
+	// Alignment is the alignment of the tensor data. It's derived
+	// from general.alignment, and must match it when importing.
+	Alignment int64 `json:"alignment"`
+
+	// Metadata is the metadata in file order.
+	Metadata []MetaEntry `json:"metadata"`
+
+	// Tensors is the tensor table in file order.
+	Tensors []HeaderTensor `json:"tensors"`
+}
+
+// HeaderTensor is a tensor in a Header.
+type HeaderTensor struct {
+	// Name is the name exactly as found in the file.
+	Name string `json:"name"`
+
+	Type       GGML     `json:"type"`
+	Dimensions []uint64 `json:"dimensions"`
+
+	// Offset is the offset of the data relative to the start of the
+	// tensor data.
+	Offset uint64 `json:"offset"`
+
+	// Size is the size of the data in bytes, or zero if unknown.
+	Size int64 `json:"size"`
+}
+
+// byteOrderNames maps byte orders to the names used in Header.
+var byteOrderNames = map[binary.ByteOrder]string{
+	binary.LittleEndian: "little",
+	binary.BigEndian:    "big",
+}
+
+// Header returns the header of the file.
+func (r *Reader) Header() *Header {
+	h := &Header{
+		Version:   r.Version,
+		ByteOrder: byteOrderNames[r.ByteOrder],
+		Alignment: r.alignment,
+		Metadata:  r.Entries,
+		Tensors:   make([]HeaderTensor, len(r.Tensors)),
+	}
+
+	for i := range r.Tensors {
+		t := &r.Tensors[i]
+
+		// The size is informational, unknown sizes are left as zero.
+		size, _ := t.SizeErr()
+
+		h.Tensors[i] = HeaderTensor{
+			Name:       t.rawName,
+			Type:       t.Type,
+			Dimensions: t.Dimensions,
+			Offset:     t.Offset,
+			Size:       size,
+		}
+	}
+
+	return h
+}
+
+// NewWriterFromHeader returns a writer that reproduces the header h.
+// Tensors keep their offsets. The tensor data must be set by
+// SetTensorData() before calling WriteTo(), but WriteHeaderTo() can be
+// used without it.
+func NewWriterFromHeader(h *Header) (*Writer, error) {
+	var byteOrder binary.ByteOrder
+
+	for o, name := range byteOrderNames {
+		if name == h.ByteOrder {
+			byteOrder = o
+		}
+	}
+
+	if byteOrder == nil {
+		return nil, fmt.Errorf("invalid byte order: %q", h.ByteOrder)
+	}
+
+	w := NewWriter(byteOrder)
+	w.Version = h.Version
+
+	for _, e := range h.Metadata {
+		var err error
+
+		if e.Type == Array {
+			e.Value, err = normalizeArray(e.ArrayType, e.Value)
+		} else {
+			e.Value, err = normalizeScalar(e.Type, e.Value)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("metadata value %q: %w", e.Key, err)
+		}
+
+		err = w.addEntry(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	alignment, err := w.alignment()
+	if err != nil {
+		return nil, err
+	}
+
+	if h.Alignment != 0 && h.Alignment != alignment {
+		return nil, fmt.Errorf("alignment %d does not match general.alignment %d", h.Alignment, alignment)
+	}
+
+	for _, t := range h.Tensors {
+		wt := writerTensor{
+			TensorInfo: TensorInfo{
+				Name:       t.Name,
+				Dimensions: t.Dimensions,
+				Type:       t.Type,
+				Offset:     t.Offset,
+			},
+			keepOffset: true,
+		}
+
+		wt.size, wt.err = wt.SizeErr()
+
+		switch {
+		case wt.err != nil && t.Size > 0:
+			// Unknown types rely on the size from the header.
+			wt.size, wt.err = t.Size, nil
+
+		case wt.err == nil && t.Size != 0 && t.Size != wt.size:
+			return nil, fmt.Errorf("tensor %q: size %d does not match the computed size %d", t.Name, t.Size, wt.size)
+		}
+
+		w.tensors = append(w.tensors, wt)
+	}
+
+	return w, nil
+}
@@ -0,0 +1,329 @@
+package gguf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Imatrix is an importance matrix as produced by llama-imatrix. It
+// holds the mean squared activation of every column of the weight
+// tensors, used to weigh quantization with QuantizeWeighted().
+type Imatrix struct {
+	// Datasets is the name of the dataset(s) used to compute the
+	// matrix.
+	Datasets []string
+
+	// ChunkCount is the number of chunks processed, if known.
+	ChunkCount int
+
+	// ChunkSize is the number of tokens in each chunk, if known.
+	ChunkSize int
+
+	// Importance is the importance vector of each weight tensor,
+	// keyed by the tensor name as found in Reader.Tensors. For
+	// tensors with multiple matrices, like the experts of a MoE
+	// model, the vectors of all matrices are concatenated.
+	Importance map[string][]float32
+}
+
+// imatrix suffixes of tensors in GGUF imatrix files.
+const (
+	imatrixSumSuffix   = ".in_sum2"
+	imatrixCountSuffix = ".counts"
+)
+
+// OpenImatrixFile reads an importance matrix file, either in the
+// legacy binary format or the GGUF format.
+func OpenImatrixFile(filename string) (*Imatrix, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return OpenImatrix(f)
+}
+
+// OpenImatrix reads an importance matrix from r, either in the legacy
+// binary format or the GGUF format. r must be positioned at the start
+// of the file.
+func OpenImatrix(r io.ReadSeeker) (*Imatrix, error) {
+	var buf [4]byte
+
+	_, err := io.ReadFull(r, buf[:])
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.Seek(-4, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.Equal(buf[:], []byte(magic)) {
+		g, err := Open(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return ImatrixFromGGUF(g)
+	}
+
+	return readLegacyImatrix(r)
+}
+
+// ImatrixFromGGUF extracts the importance matrix from an imatrix file
+// in GGUF format.
+func ImatrixFromGGUF(g *Reader) (*Imatrix, error) {
+	if typ, err := g.Metadata.String("general.type"); err == nil && typ != "imatrix" {
+		return nil, fmt.Errorf("not an imatrix file, type is %q", typ)
+	}
+
+	m := &Imatrix{
+		Importance: make(map[string][]float32),
+	}
+
+	m.Datasets, _ = MetaValue[[]string](g.Metadata, "imatrix.datasets")
+	m.ChunkCount, _ = g.Metadata.Int("imatrix.chunk_count")
+	m.ChunkSize, _ = g.Metadata.Int("imatrix.chunk_size")
+
+	for i := range g.Tensors {
+		sums := &g.Tensors[i]
+
+		name, found := strings.CutSuffix(sums.Name, imatrixSumSuffix)
+		if !found {
+			continue
+		}
+
+		counts, err := g.TensorInfo(name + imatrixCountSuffix)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := imatrixEntry(sums, counts)
+		if err != nil {
+			return nil, fmt.Errorf("imatrix entry %q: %w", name, err)
+		}
+
+		m.Importance[name] = e
+	}
+
+	return m, nil
+}
+
+// imatrixEntry computes the importance vector from the sums of squared
+// activations and the number of activations of every matrix.
+func imatrixEntry(sums *TensorInfo, counts *TensorInfo) ([]float32, error) {
+	if len(sums.Dimensions) == 0 || sums.Dimensions[0] == 0 {
+		return nil, fmt.Errorf("invalid dimensions: %v", sums.Dimensions)
+	}
+
+	s, err := sums.Dequantize()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := counts.Dequantize()
+	if err != nil {
+		return nil, err
+	}
+
+	rowLength := int(sums.Dimensions[0])
+
+	if len(s) != len(c)*rowLength {
+		return nil, fmt.Errorf("%d sums does not match %d counts", len(s), len(c))
+	}
+
+	for j, count := range c {
+		row := s[j*rowLength : (j+1)*rowLength]
+
+		for i := range row {
+			if count > 0 {
+				row[i] /= count
+			} else {
+				// The matrix never got any input during
+				// calibration, treat all columns alike.
+				row[i] = 1
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// readLegacyImatrix reads an importance matrix in the legacy binary
+// format written by llama-imatrix.
+func readLegacyImatrix(r io.ReadSeeker) (*Imatrix, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	lr := &io.LimitedReader{R: r, N: size}
+
+	readInt := func() (int, error) {
+		v, err := read[int32](lr, binary.LittleEndian)
+		if err != nil {
+			return 0, err
+		}
+
+		if v < 0 {
+			return 0, fmt.Errorf("invalid imatrix file, negative value %d", v)
+		}
+
+		return int(v), nil
+	}
+
+	readString := func() (string, error) {
+		length, err := readInt()
+		if err != nil {
+			return "", err
+		}
+
+		if int64(length) > lr.N {
+			return "", fmt.Errorf("invalid imatrix file, string length %d exceeds file size", length)
+		}
+
+		b := make([]byte, length)
+
+		_, err = io.ReadFull(lr, b)
+
+		return string(b), err
+	}
+
+	entries, err := readInt()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Imatrix{
+		Importance: make(map[string][]float32),
+	}
+
+	for i := 0; i < entries; i++ {
+		name, err := readString()
+		if err != nil {
+			return nil, err
+		}
+
+		calls, err := readInt()
+		if err != nil {
+			return nil, err
+		}
+
+		values, err := readInt()
+		if err != nil {
+			return nil, err
+		}
+
+		if int64(values)*4 > lr.N {
+			return nil, fmt.Errorf("imatrix entry %q: %d values exceeds file size", name, values)
+		}
+
+		e := make([]float32, values)
+
+		err = binary.Read(lr, binary.LittleEndian, e)
+		if err != nil {
+			return nil, err
+		}
+
+		// The legacy format stores sums over all calls.
+		if calls > 0 {
+			for j := range e {
+				e[j] /= float32(calls)
+			}
+		}
+
+		m.Importance[name] = e
+	}
+
+	// Newer versions append the number of chunks and the dataset
+	// name. Older files simply end here.
+	chunks, err := readInt()
+	if err == io.EOF {
+		return m, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	dataset, err := readString()
+	if err != nil {
+		return nil, err
+	}
+
+	m.ChunkCount = chunks
+	m.Datasets = []string{dataset}
+
+	return m, nil
+}
+
+// importanceLength returns the expected length of the importance
+// vector of t, the row length times the number of matrices.
+func importanceLength(t *TensorInfo) uint64 {
+	if len(t.Dimensions) == 0 {
+		return 0
+	}
+
+	length := t.Dimensions[0]
+
+	if len(t.Dimensions) >= 3 {
+		length *= t.Dimensions[2]
+	}
+
+	return length
+}
+
+// For returns the importance vector for the tensor t. An error is
+// returned if there is no vector for t, or if its length doesn't match
+// the row length of t.
+func (m *Imatrix) For(t *TensorInfo) ([]float32, error) {
+	e, found := m.Importance[t.Name]
+	if !found {
+		return nil, fmt.Errorf("no importance data for tensor %q", t.Name)
+	}
+
+	expected := importanceLength(t)
+	if uint64(len(e)) != expected {
+		return nil, fmt.Errorf("importance data for tensor %q has %d values, expected %d", t.Name, len(e), expected)
+	}
+
+	return e, nil
+}
+
+// Validate checks that the length of every importance vector matches
+// the row length of the tensor with the same name in g.
+func (m *Imatrix) Validate(g *Reader) error {
+	names := make([]string, 0, len(m.Importance))
+	for name := range m.Importance {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		t, err := g.TensorInfo(name)
+		if err != nil {
+			return err
+		}
+
+		_, err = m.For(t)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,313 @@
+package gguf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// legacyImatrixEntry is an entry of a legacy imatrix file.
+type legacyImatrixEntry struct {
+	name   string
+	calls  int32
+	values []float32
+}
+
+// legacyImatrix returns a legacy imatrix file. The chunk count and
+// dataset name are only appended if dataset is not empty.
+func legacyImatrix(entries []legacyImatrixEntry, chunks int32, dataset string) []byte {
+	var b bytes.Buffer
+
+	le := binary.LittleEndian
+
+	_ = binary.Write(&b, le, int32(len(entries)))
+
+	for _, e := range entries {
+		_ = binary.Write(&b, le, int32(len(e.name)))
+		b.WriteString(e.name)
+		_ = binary.Write(&b, le, e.calls)
+		_ = binary.Write(&b, le, int32(len(e.values)))
+		_ = binary.Write(&b, le, e.values)
+	}
+
+	if dataset != "" {
+		_ = binary.Write(&b, le, chunks)
+		_ = binary.Write(&b, le, int32(len(dataset)))
+		b.WriteString(dataset)
+	}
+
+	return b.Bytes()
+}
+
+// float32Bytes returns the little endian encoding of values.
+func float32Bytes(values ...float32) []byte {
+	b := make([]byte, 4*len(values))
+
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(v))
+	}
+
+	return b
+}
+
+// ggufImatrixWriter returns a writer for an imatrix file in GGUF format
+// with an entry for a tensor of two matrices with a row length of 4.
+// The second matrix never got any input.
+func ggufImatrixWriter(t *testing.T) *Writer {
+	t.Helper()
+
+	w := NewWriter(binary.LittleEndian)
+
+	err := w.AddMetadata("general.type", String, "imatrix")
+	if err == nil {
+		err = w.AddArray("imatrix.datasets", String, []string{"wiki.train.raw"})
+	}
+
+	if err == nil {
+		err = w.AddMetadata("imatrix.chunk_count", Uint32, uint32(100))
+	}
+
+	if err == nil {
+		err = w.AddMetadata("imatrix.chunk_size", Uint32, uint32(512))
+	}
+
+	if err == nil {
+		sums := float32Bytes(2, 4, 6, 8, 5, 5, 5, 5)
+		err = w.AddTensor("blk.0.ffn_down_exps.weight.in_sum2", []uint64{4, 2}, GgmlFloat32, bytes.NewReader(sums))
+	}
+
+	if err == nil {
+		err = w.AddTensor("blk.0.ffn_down_exps.weight.counts", []uint64{1, 2}, GgmlFloat32, bytes.NewReader(float32Bytes(2, 0)))
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return w
+}
+
+// imatrixModel returns a model with the tensors of the imatrix test
+// files.
+func imatrixModel(t *testing.T, expertRowLength uint64) *Reader {
+	t.Helper()
+
+	w := NewWriter(binary.LittleEndian)
+
+	err := w.AddMetadata("general.architecture", String, "llama")
+	if err == nil {
+		err = w.AddTensor("blk.0.attn_q.weight", []uint64{3, 2}, GgmlFloat32, bytes.NewReader(make([]byte, 24)))
+	}
+
+	if err == nil {
+		err = w.AddTensor("blk.0.ffn_down_exps.weight", []uint64{expertRowLength, 1, 2}, GgmlFloat32, bytes.NewReader(make([]byte, 8*expertRowLength)))
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, _ := encode(t, w)
+
+	return r
+}
+
+func TestOpenImatrixLegacy(t *testing.T) {
+	data := legacyImatrix([]legacyImatrixEntry{
+		{"blk.0.attn_q.weight", 4, []float32{4, 8, 2}},
+		{"blk.0.attn_k.weight", 0, []float32{1, 2, 3}},
+	}, 100, "wiki.train.raw")
+
+	m, err := OpenImatrix(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Imatrix{
+		Datasets:   []string{"wiki.train.raw"},
+		ChunkCount: 100,
+		Importance: map[string][]float32{
+			// Sums are divided by the number of calls.
+			"blk.0.attn_q.weight": {1, 2, 0.5},
+
+			// Zero calls leave the values as is.
+			"blk.0.attn_k.weight": {1, 2, 3},
+		},
+	}
+
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("expected %+v, got %+v", expected, m)
+	}
+
+	// Older files end after the entries.
+	m, err = OpenImatrix(bytes.NewReader(legacyImatrix([]legacyImatrixEntry{{"a", 1, []float32{1}}}, 0, "")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.ChunkCount != 0 || m.Datasets != nil || len(m.Importance) != 1 {
+		t.Errorf("unexpected imatrix without trailer: %+v", m)
+	}
+}
+
+func TestOpenImatrixGGUF(t *testing.T) {
+	_, data := encode(t, ggufImatrixWriter(t))
+
+	m, err := OpenImatrix(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Imatrix{
+		Datasets:   []string{"wiki.train.raw"},
+		ChunkCount: 100,
+		ChunkSize:  512,
+		Importance: map[string][]float32{
+			// The sums of every matrix are divided by its count,
+			// matrices without input get all ones.
+			"blk.0.ffn_down_exps.weight": {1, 2, 3, 4, 1, 1, 1, 1},
+		},
+	}
+
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("expected %+v, got %+v", expected, m)
+	}
+}
+
+func TestImatrixFor(t *testing.T) {
+	_, data := encode(t, ggufImatrixWriter(t))
+
+	m, err := OpenImatrix(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.Importance["blk.0.attn_q.weight"] = []float32{1, 2, 3}
+
+	model := imatrixModel(t, 4)
+
+	err = m.Validate(model)
+	if err != nil {
+		t.Fatalf("Validate: %s", err)
+	}
+
+	e, err := m.For(&model.Tensors[1])
+	if err != nil {
+		t.Fatalf("For: %s", err)
+	}
+
+	if len(e) != 8 {
+		t.Errorf("expected 8 values, got %d", len(e))
+	}
+
+	// The rows of the model are longer than the vectors.
+	model = imatrixModel(t, 8)
+
+	err = m.Validate(model)
+	if err == nil || !strings.Contains(err.Error(), "has 8 values, expected 16") {
+		t.Errorf("Validate: expected a length error, got %v", err)
+	}
+
+	_, err = m.For(&model.Tensors[1])
+	if err == nil {
+		t.Error("For: expected a length error")
+	}
+
+	// Vectors for tensors not in the model are rejected.
+	m.Importance["blk.1.attn_q.weight"] = []float32{1, 2, 3}
+
+	err = m.Validate(imatrixModel(t, 4))
+	if err == nil {
+		t.Error("Validate: expected an error for a missing tensor")
+	}
+
+	delete(m.Importance, "blk.0.attn_q.weight")
+
+	_, err = m.For(&imatrixModel(t, 4).Tensors[0])
+	if err == nil || !strings.Contains(err.Error(), "no importance data") {
+		t.Errorf("For: expected a missing data error, got %v", err)
+	}
+}
+
+func TestOpenImatrixCorrupt(t *testing.T) {
+	valid := legacyImatrix([]legacyImatrixEntry{{"blk.0.attn_q.weight", 1, []float32{1, 2}}}, 10, "data")
+
+	le := binary.LittleEndian
+
+	// An entry named "a" with a value count far beyond the file size.
+	hugeValues := le.AppendUint32(nil, 1)
+	hugeValues = le.AppendUint32(hugeValues, 1)
+	hugeValues = append(hugeValues, 'a')
+	hugeValues = le.AppendUint32(hugeValues, 1)
+	hugeValues = le.AppendUint32(hugeValues, 1<<30)
+
+	corrupt := map[string][]byte{
+		"empty":                nil,
+		"truncated count":      valid[:2],
+		"truncated name":       valid[:10],
+		"truncated values":     valid[:len(valid)-16],
+		"truncated trailer":    valid[:len(valid)-10],
+		"negative entry count": le.AppendUint32(nil, 0xffffffff),
+		"huge name":            le.AppendUint32(le.AppendUint32(nil, 1), 1<<30),
+		"huge values":          hugeValues,
+	}
+
+	for name, data := range corrupt {
+		_, err := OpenImatrix(bytes.NewReader(data))
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	// A GGUF file that is not an imatrix.
+	w := ggufImatrixWriter(t)
+
+	err := w.SetMetadata("general.type", String, "model")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, data := encode(t, w)
+
+	_, err = OpenImatrix(bytes.NewReader(data))
+	if err == nil || !strings.Contains(err.Error(), "not an imatrix file") {
+		t.Errorf("model: expected an error, got %v", err)
+	}
+
+	// Sums without counts.
+	w = NewWriter(binary.LittleEndian)
+
+	err = w.AddTensor("a.in_sum2", []uint64{4}, GgmlFloat32, bytes.NewReader(make([]byte, 16)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, data = encode(t, w)
+
+	_, err = OpenImatrix(bytes.NewReader(data))
+	if err == nil {
+		t.Error("missing counts: expected an error")
+	}
+
+	// Counts for a single matrix, while the sums have two.
+	w = NewWriter(binary.LittleEndian)
+
+	err = w.AddTensor("a.in_sum2", []uint64{4, 2}, GgmlFloat32, bytes.NewReader(make([]byte, 32)))
+	if err == nil {
+		err = w.AddTensor("a.counts", []uint64{1}, GgmlFloat32, bytes.NewReader(float32Bytes(1)))
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, data = encode(t, w)
+
+	_, err = OpenImatrix(bytes.NewReader(data))
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("mismatched counts: expected an error, got %v", err)
+	}
+}
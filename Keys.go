@@ -0,0 +1,457 @@
+package gguf
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// KeySpec describes a well-known metadata key.
+type KeySpec struct {
+	// Key is the name of the key. {arch} is replaced by the
+	// architecture, {n} matches any number and {name} any name.
+	Key string
+
+	// Types is the accepted types of the value. If it includes Array,
+	// ArrayTypes is the accepted types of the elements.
+	Types      []Type
+	ArrayTypes []Type
+
+	// Required is set for keys that must be present in every file.
+	// Keys required by a specific architecture are reported by
+	// Metadata.ArchConfig().
+	Required bool
+
+	Description string
+}
+
+// Shorthands for the types of the keys below.
+var (
+	tString  = []Type{String}
+	tBool    = []Type{Bool}
+	tUint16  = []Type{Uint16}
+	tInt32   = []Type{Int32}
+	tUint32  = []Type{Uint32}
+	tFloat32 = []Type{Float32}
+	tCount   = []Type{Uint32, Uint64}
+	tLayers  = []Type{Uint32, Uint64, Array}
+	tArray   = []Type{Array}
+	tIntElem = []Type{Uint32, Int32, Uint64}
+)
+
+// WellKnownKeys is the registry of standardized metadata keys, from the
+// GGUF specification and llama.cpp.
+var WellKnownKeys = []KeySpec{
+	{Key: "general.architecture", Types: tString, Required: true, Description: "Architecture of the model, used as {arch} in other keys"},
+	{Key: "general.quantization_version", Types: tUint32, Description: "Version of the quantization format"},
+	{Key: "general.alignment", Types: tUint32, Description: "Alignment of the tensor data, a multiple of 8"},
+	{Key: "general.file_type", Types: tUint32, Description: "Type of the majority of the tensors"},
+	{Key: "general.type", Types: tString, Description: "Type of file, like model, adapter or imatrix"},
+	{Key: "general.name", Types: tString, Description: "Name of the model"},
+	{Key: "general.author", Types: tString, Description: "Author of the model"},
+	{Key: "general.version", Types: tString, Description: "Version of the model"},
+	{Key: "general.organization", Types: tString, Description: "Organization behind the model"},
+	{Key: "general.basename", Types: tString, Description: "Base name of the model family"},
+	{Key: "general.finetune", Types: tString, Description: "What the base model was fine-tuned for"},
+	{Key: "general.description", Types: tString, Description: "Free form description of the model"},
+	{Key: "general.quantized_by", Types: tString, Description: "Who quantized the model"},
+	{Key: "general.size_label", Types: tString, Description: "Size class of the model, like 7B"},
+	{Key: "general.license", Types: tString, Description: "SPDX license expression of the model"},
+	{Key: "general.license.name", Types: tString, Description: "Name of a non-SPDX license"},
+	{Key: "general.license.link", Types: tString, Description: "URL of the license"},
+	{Key: "general.url", Types: tString, Description: "URL of the model's homepage"},
+	{Key: "general.doi", Types: tString, Description: "DOI of the model"},
+	{Key: "general.uuid", Types: tString, Description: "UUID of the model"},
+	{Key: "general.repo_url", Types: tString, Description: "URL of the model's repository"},
+	{Key: "general.tags", Types: tArray, ArrayTypes: tString, Description: "Tags for searching"},
+	{Key: "general.languages", Types: tArray, ArrayTypes: tString, Description: "Languages supported by the model"},
+	{Key: "general.datasets", Types: tArray, ArrayTypes: tString, Description: "Datasets the model was trained on"},
+	{Key: "general.source.url", Types: tString, Description: "URL of the source of the conversion"},
+	{Key: "general.source.doi", Types: tString, Description: "DOI of the source of the conversion"},
+	{Key: "general.source.uuid", Types: tString, Description: "UUID of the source of the conversion"},
+	{Key: "general.source.repo_url", Types: tString, Description: "Repository of the source of the conversion"},
+	{Key: "general.source.huggingface.repository", Types: tString, Description: "Hugging Face repository of the source"},
+	{Key: "general.base_model.count", Types: tUint32, Description: "Number of base models of a merge or fine-tune"},
+	{Key: "general.base_model.{n}.name", Types: tString, Description: "Name of a base model"},
+	{Key: "general.base_model.{n}.organization", Types: tString, Description: "Organization of a base model"},
+	{Key: "general.base_model.{n}.version", Types: tString, Description: "Version of a base model"},
+	{Key: "general.base_model.{n}.url", Types: tString, Description: "URL of a base model"},
+	{Key: "general.base_model.{n}.doi", Types: tString, Description: "DOI of a base model"},
+	{Key: "general.base_model.{n}.uuid", Types: tString, Description: "UUID of a base model"},
+	{Key: "general.base_model.{n}.repo_url", Types: tString, Description: "Repository of a base model"},
+
+	{Key: "{arch}.vocab_size", Types: tCount, Description: "Number of tokens in the vocabulary"},
+	{Key: "{arch}.context_length", Types: tCount, Description: "Context length the model was trained with"},
+	{Key: "{arch}.embedding_length", Types: tCount, Description: "Size of the embeddings"},
+	{Key: "{arch}.block_count", Types: tCount, Description: "Number of layers"},
+	{Key: "{arch}.feed_forward_length", Types: tLayers, ArrayTypes: tIntElem, Description: "Size of the feed forward layers, per layer if an array"},
+	{Key: "{arch}.use_parallel_residual", Types: tBool, Description: "Whether parallel residual connections are used"},
+	{Key: "{arch}.tensor_data_layout", Types: tString, Description: "Layout of the tensor data"},
+	{Key: "{arch}.expert_count", Types: tUint32, Description: "Number of experts"},
+	{Key: "{arch}.expert_used_count", Types: tUint32, Description: "Number of experts used for each token"},
+	{Key: "{arch}.expert_feed_forward_length", Types: tUint32, Description: "Size of the feed forward layer of each expert"},
+	{Key: "{arch}.expert_shared_feed_forward_length", Types: tUint32, Description: "Size of the feed forward layer of the shared expert"},
+	{Key: "{arch}.leading_dense_block_count", Types: tUint32, Description: "Number of dense layers before the expert layers"},
+	{Key: "{arch}.pooling_type", Types: tUint32, Description: "Pooling type of embeddings"},
+	{Key: "{arch}.logit_scale", Types: tFloat32, Description: "Scale of the output logits"},
+	{Key: "{arch}.attn_logit_softcapping", Types: tFloat32, Description: "Soft capping of the attention logits"},
+	{Key: "{arch}.final_logit_softcapping", Types: tFloat32, Description: "Soft capping of the output logits"},
+	{Key: "{arch}.decoder_start_token_id", Types: tUint32, Description: "Token starting the decoder of encoder-decoder models"},
+	{Key: "{arch}.attention.head_count", Types: tLayers, ArrayTypes: tIntElem, Description: "Number of attention heads, per layer if an array"},
+	{Key: "{arch}.attention.head_count_kv", Types: tLayers, ArrayTypes: tIntElem, Description: "Number of key/value heads, per layer if an array"},
+	{Key: "{arch}.attention.max_alibi_bias", Types: tFloat32, Description: "Maximum ALiBi bias"},
+	{Key: "{arch}.attention.clamp_kqv", Types: tFloat32, Description: "Clamping of the KQV values"},
+	{Key: "{arch}.attention.key_length", Types: tUint32, Description: "Size of each key head"},
+	{Key: "{arch}.attention.value_length", Types: tUint32, Description: "Size of each value head"},
+	{Key: "{arch}.attention.layer_norm_epsilon", Types: tFloat32, Description: "Epsilon of the layer norms"},
+	{Key: "{arch}.attention.layer_norm_rms_epsilon", Types: tFloat32, Description: "Epsilon of the RMS norms"},
+	{Key: "{arch}.attention.causal", Types: tBool, Description: "Whether the attention is causal"},
+	{Key: "{arch}.attention.sliding_window", Types: tUint32, Description: "Size of the sliding attention window"},
+	{Key: "{arch}.attention.q_lora_rank", Types: tUint32, Description: "Rank of the query low-rank projection"},
+	{Key: "{arch}.attention.kv_lora_rank", Types: tUint32, Description: "Rank of the key/value low-rank projection"},
+	{Key: "{arch}.rope.dimension_count", Types: tUint32, Description: "Number of rotated dimensions"},
+	{Key: "{arch}.rope.freq_base", Types: tFloat32, Description: "Base frequency of the rotary embeddings"},
+	{Key: "{arch}.rope.scale_linear", Types: tFloat32, Description: "Linear rope scaling factor, replaced by rope.scaling.factor"},
+	{Key: "{arch}.rope.scaling.type", Types: tString, Description: "Type of rope scaling, like none, linear or yarn"},
+	{Key: "{arch}.rope.scaling.factor", Types: tFloat32, Description: "Rope scaling factor"},
+	{Key: "{arch}.rope.scaling.attn_factor", Types: tFloat32, Description: "Attention factor of rope scaling"},
+	{Key: "{arch}.rope.scaling.original_context_length", Types: tUint32, Description: "Context length before scaling"},
+	{Key: "{arch}.rope.scaling.finetuned", Types: tBool, Description: "Whether the model was fine-tuned with rope scaling"},
+	{Key: "{arch}.rope.scaling.yarn_log_multiplier", Types: tFloat32, Description: "YaRN log multiplier"},
+	{Key: "{arch}.ssm.conv_kernel", Types: tUint32, Description: "Size of the SSM convolution kernel"},
+	{Key: "{arch}.ssm.inner_size", Types: tUint32, Description: "Size of the SSM inner state"},
+	{Key: "{arch}.ssm.state_size", Types: tUint32, Description: "Size of the SSM recurrent state"},
+	{Key: "{arch}.ssm.time_step_rank", Types: tUint32, Description: "Rank of the SSM time step projection"},
+
+	{Key: "tokenizer.ggml.model", Types: tString, Description: "Tokenizer model, like llama, gpt2 or bert"},
+	{Key: "tokenizer.ggml.pre", Types: tString, Description: "Pre-tokenizer"},
+	{Key: "tokenizer.ggml.tokens", Types: tArray, ArrayTypes: tString, Description: "Tokens of the vocabulary"},
+	{Key: "tokenizer.ggml.scores", Types: tArray, ArrayTypes: tFloat32, Description: "Score of each token"},
+	{Key: "tokenizer.ggml.token_type", Types: tArray, ArrayTypes: tInt32, Description: "Type of each token"},
+	{Key: "tokenizer.ggml.token_type_count", Types: tUint32, Description: "Number of token types"},
+	{Key: "tokenizer.ggml.merges", Types: tArray, ArrayTypes: tString, Description: "BPE merges"},
+	{Key: "tokenizer.ggml.added_tokens", Types: tArray, ArrayTypes: tString, Description: "Tokens added after training"},
+	{Key: "tokenizer.ggml.bos_token_id", Types: tUint32, Description: "Beginning of sequence token"},
+	{Key: "tokenizer.ggml.eos_token_id", Types: tUint32, Description: "End of sequence token"},
+	{Key: "tokenizer.ggml.eot_token_id", Types: tUint32, Description: "End of turn token"},
+	{Key: "tokenizer.ggml.eom_token_id", Types: tUint32, Description: "End of message token"},
+	{Key: "tokenizer.ggml.unknown_token_id", Types: tUint32, Description: "Unknown token"},
+	{Key: "tokenizer.ggml.separator_token_id", Types: tUint32, Description: "Separator token"},
+	{Key: "tokenizer.ggml.padding_token_id", Types: tUint32, Description: "Padding token"},
+	{Key: "tokenizer.ggml.cls_token_id", Types: tUint32, Description: "Classification token"},
+	{Key: "tokenizer.ggml.mask_token_id", Types: tUint32, Description: "Mask token"},
+	{Key: "tokenizer.ggml.fim_pre_token_id", Types: tUint32, Description: "Fill-in-the-middle prefix token"},
+	{Key: "tokenizer.ggml.fim_suf_token_id", Types: tUint32, Description: "Fill-in-the-middle suffix token"},
+	{Key: "tokenizer.ggml.fim_mid_token_id", Types: tUint32, Description: "Fill-in-the-middle middle token"},
+	{Key: "tokenizer.ggml.fim_pad_token_id", Types: tUint32, Description: "Fill-in-the-middle padding token"},
+	{Key: "tokenizer.ggml.fim_rep_token_id", Types: tUint32, Description: "Fill-in-the-middle repository token"},
+	{Key: "tokenizer.ggml.fim_sep_token_id", Types: tUint32, Description: "Fill-in-the-middle separator token"},
+	{Key: "tokenizer.ggml.add_bos_token", Types: tBool, Description: "Whether to add the BOS token"},
+	{Key: "tokenizer.ggml.add_eos_token", Types: tBool, Description: "Whether to add the EOS token"},
+	{Key: "tokenizer.ggml.add_space_prefix", Types: tBool, Description: "Whether to add a space before the text"},
+	{Key: "tokenizer.ggml.remove_extra_whitespaces", Types: tBool, Description: "Whether to remove repeated whitespace"},
+	{Key: "tokenizer.ggml.precompiled_charsmap", Types: tArray, ArrayTypes: []Type{Uint8}, Description: "Precompiled normalization map"},
+	{Key: "tokenizer.huggingface.json", Types: tString, Description: "Complete Hugging Face tokenizer.json"},
+	{Key: "tokenizer.rwkv.world", Types: tString, Description: "RWKV world tokenizer"},
+	{Key: "tokenizer.chat_template", Types: tString, Description: "Jinja chat template"},
+	{Key: "tokenizer.chat_template.{name}", Types: tString, Description: "Named Jinja chat template"},
+	{Key: "tokenizer.chat_templates", Types: tArray, ArrayTypes: tString, Description: "Names of the named chat templates"},
+
+	{Key: "split.no", Types: tUint16, Description: "Index of this file in a split model"},
+	{Key: "split.count", Types: tUint16, Description: "Number of files in a split model"},
+	{Key: "split.tensors.count", Types: tInt32, Description: "Number of tensors in all files of a split model"},
+
+	{Key: "quantize.imatrix.file", Types: tString, Description: "Importance matrix used for quantization"},
+	{Key: "quantize.imatrix.dataset", Types: tString, Description: "Dataset of the importance matrix"},
+	{Key: "quantize.imatrix.entries_count", Types: tInt32, Description: "Number of entries in the importance matrix"},
+	{Key: "quantize.imatrix.chunks_count", Types: tInt32, Description: "Number of chunks of the importance matrix"},
+
+	{Key: "imatrix.datasets", Types: tArray, ArrayTypes: tString, Description: "Datasets used to compute the importance matrix"},
+	{Key: "imatrix.chunk_count", Types: tUint32, Description: "Number of chunks processed"},
+	{Key: "imatrix.chunk_size", Types: tUint32, Description: "Number of tokens in each chunk"},
+
+	{Key: "adapter.type", Types: tString, Description: "Type of adapter, like lora"},
+	{Key: "adapter.lora.alpha", Types: tFloat32, Description: "LoRA alpha"},
+}
+
+// wellKnownNamespaces are the namespaces of WellKnownKeys. Unknown keys
+// in these are reported by Check().
+var wellKnownNamespaces = []string{"general.", "tokenizer.", "split.", "quantize.", "adapter."}
+
+// matchKey returns true if key matches the pattern of a KeySpec.
+func matchKey(pattern string, key string, arch string) bool {
+	if arch != "" {
+		pattern = strings.ReplaceAll(pattern, archPlaceholder, arch)
+	}
+
+	p := strings.Split(pattern, ".")
+	k := strings.Split(key, ".")
+
+	if len(p) != len(k) {
+		return false
+	}
+
+	for i := range p {
+		switch p[i] {
+		case "{n}":
+			if k[i] == "" || strings.Trim(k[i], "0123456789") != "" {
+				return false
+			}
+
+		case "{name}":
+			if k[i] == "" {
+				return false
+			}
+
+		default:
+			if p[i] != k[i] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// LookupKey returns the spec of a well-known key. arch is used for
+// keys starting with {arch}.
+func LookupKey(key string, arch string) (KeySpec, bool) {
+	for _, spec := range WellKnownKeys {
+		if matchKey(spec.Key, key, arch) {
+			return spec, true
+		}
+	}
+
+	return KeySpec{}, false
+}
+
+// metaValueType returns the GGUF type of a metadata value, and the type
+// of the elements for arrays.
+func metaValueType(value interface{}) (Type, Type, bool) {
+	if _, ok := value.([]MetaArray); ok {
+		return Array, Array, true
+	}
+
+	v := reflect.ValueOf(value)
+
+	if v.Kind() == reflect.Slice {
+		elem, found := kindTypes[v.Type().Elem().Kind()]
+
+		return Array, elem, found
+	}
+
+	typ, found := kindTypes[v.Kind()]
+
+	return typ, 0, found
+}
+
+// containsType returns true if types contains typ.
+func containsType(types []Type, typ Type) bool {
+	for _, t := range types {
+		if t == typ {
+			return true
+		}
+	}
+
+	return false
+}
+
+// describeType returns a description of the accepted types of spec.
+func (spec KeySpec) describeType() string {
+	names := make([]string, 0, len(spec.Types))
+
+	for _, t := range spec.Types {
+		if t != Array {
+			names = append(names, t.String())
+
+			continue
+		}
+
+		for _, e := range spec.ArrayTypes {
+			names = append(names, "array of "+e.String())
+		}
+	}
+
+	return strings.Join(names, " or ")
+}
+
+// Check checks the metadata against WellKnownKeys. Values of the wrong
+// type and missing required keys, also those required by the
+// architecture, are reported as errors, as are values the architecture
+// hyperparameters cannot be decoded from. Unknown keys in the
+// well-known namespaces, and keys that look like misspelled well-known
+// keys, are reported as warnings. Keys in other namespaces are allowed.
+func (m Metadata) Check() []Finding {
+	var findings []Finding
+
+	arch, _ := m["general.architecture"].(string)
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		spec, found := LookupKey(key, arch)
+		if !found {
+			f, bad := checkUnknownKey(key, arch)
+			if bad {
+				findings = append(findings, f)
+			}
+
+			continue
+		}
+
+		typ, elem, ok := metaValueType(m[key])
+		if !ok || !containsType(spec.Types, typ) || (typ == Array && !containsType(spec.ArrayTypes, elem)) {
+			actual := typ.String()
+			if typ == Array {
+				actual = "array of " + elem.String()
+			}
+
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Key:      key,
+				Message:  fmt.Sprintf("type is %s, expected %s", actual, spec.describeType()),
+			})
+		}
+	}
+
+	for _, spec := range WellKnownKeys {
+		if _, found := m[spec.Key]; spec.Required && !found {
+			findings = append(findings, Finding{Severity: SeverityError, Key: spec.Key, Message: "required key is missing"})
+		}
+	}
+
+	if arch != "" {
+		findings = append(findings, m.checkArchKeys(arch)...)
+	}
+
+	return findings
+}
+
+// checkArchKeys reports the keys required by the architecture that are
+// missing, and the first invalid value of a supported architecture.
+func (m Metadata) checkArchKeys(arch string) []Finding {
+	var findings []Finding
+	var missing []string
+
+	if newConfig, found := archConfigs[arch]; found {
+		_, d := m.decodeArch(arch, newConfig)
+
+		if d.err != nil {
+			findings = append(findings, Finding{Severity: SeverityError, Message: d.err.Error()})
+		}
+
+		missing = d.missing
+	} else {
+		// The common keys are required for all architectures.
+		for _, suffix := range []string{"context_length", "embedding_length", "block_count"} {
+			if _, found := m[arch+"."+suffix]; !found {
+				missing = append(missing, arch+"."+suffix)
+			}
+		}
+	}
+
+	for _, key := range missing {
+		findings = append(findings, Finding{Severity: SeverityError, Key: key, Message: fmt.Sprintf("required by architecture %q, but missing", arch)})
+	}
+
+	return findings
+}
+
+// checkUnknownKey returns a finding for a key not in WellKnownKeys, if
+// it looks like a typo or is in a well-known namespace.
+func checkUnknownKey(key string, arch string) (Finding, bool) {
+	best, bestDistance := "", 0
+
+	for _, spec := range WellKnownKeys {
+		if arch == "" && strings.Contains(spec.Key, archPlaceholder) {
+			continue
+		}
+
+		candidate := strings.ReplaceAll(spec.Key, archPlaceholder, arch)
+		if strings.Contains(candidate, "{") {
+			continue
+		}
+
+		// Short names only allow a single edit, or most of them would
+		// match.
+		maxDistance := 2
+		if len(candidate)-strings.LastIndex(candidate, ".") <= 6 {
+			maxDistance = 1
+		}
+
+		// The distance is at least the difference in length, which
+		// also keeps long keys from being compared at all.
+		if len(key) > len(candidate)+maxDistance || len(candidate) > len(key)+maxDistance {
+			continue
+		}
+
+		d := editDistance(key, candidate)
+		if d > 0 && d <= maxDistance && (best == "" || d < bestDistance) {
+			best, bestDistance = candidate, d
+		}
+	}
+
+	if best != "" {
+		return Finding{Severity: SeverityWarning, Key: key, Message: fmt.Sprintf("unknown key, did you mean %q?", best)}, true
+	}
+
+	namespaces := wellKnownNamespaces
+	if arch != "" {
+		namespaces = append(namespaces[:len(namespaces):len(namespaces)], arch+".")
+	}
+
+	for _, ns := range namespaces {
+		if strings.HasPrefix(key, ns) {
+			return Finding{Severity: SeverityWarning, Key: key, Message: "unknown key"}, true
+		}
+	}
+
+	return Finding{}, false
+}
+
+// editDistance returns the edit distance between a and b, counting
+// insertions, deletions, substitutions and transpositions of adjacent
+// bytes.
+func editDistance(a string, b string) int {
+	d := make([][]int, len(a)+1)
+
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = d[i-1][j-1] + cost
+
+			if d[i-1][j]+1 < d[i][j] {
+				d[i][j] = d[i-1][j] + 1
+			}
+
+			if d[i][j-1]+1 < d[i][j] {
+				d[i][j] = d[i][j-1] + 1
+			}
+
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+
+	return d[len(a)][len(b)]
+}
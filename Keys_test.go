@@ -0,0 +1,167 @@
+package gguf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckArchKeys(t *testing.T) {
+	m := llamaMetadata("two")
+	delete(m, "llama.embedding_length")
+
+	var decodeErr, missing bool
+
+	for _, f := range m.Check() {
+		switch {
+		case f.Key == "llama.embedding_length" && strings.Contains(f.Message, "missing"):
+			missing = true
+
+		case f.Key == "" && strings.Contains(f.Message, "llama.block_count"):
+			decodeErr = true
+		}
+	}
+
+	if !missing {
+		t.Errorf("missing llama.embedding_length not reported")
+	}
+
+	if !decodeErr {
+		t.Errorf("invalid llama.block_count not reported")
+	}
+}
+
+func TestMatchKey(t *testing.T) {
+	cases := []struct {
+		pattern string
+		key     string
+		arch    string
+		match   bool
+	}{
+		{"general.name", "general.name", "", true},
+		{"general.name", "general.names", "", false},
+		{"general.base_model.{n}.name", "general.base_model.0.name", "", true},
+		{"general.base_model.{n}.name", "general.base_model.12.name", "", true},
+		{"general.base_model.{n}.name", "general.base_model.x.name", "", false},
+		{"general.base_model.{n}.name", "general.base_model.1x.name", "", false},
+		{"general.base_model.{n}.name", "general.base_model..name", "", false},
+		{"general.base_model.{n}.name", "general.base_model.0.name.x", "", false},
+		{"tokenizer.chat_template.{name}", "tokenizer.chat_template.tool_use", "", true},
+		{"tokenizer.chat_template.{name}", "tokenizer.chat_template.", "", false},
+		{"tokenizer.chat_template.{name}", "tokenizer.chat_template.a.b", "", false},
+		{"{arch}.context_length", "llama.context_length", "llama", true},
+		{"{arch}.context_length", "llama.context_length", "gemma", false},
+		{"{arch}.context_length", "llama.context_length", "", false},
+	}
+
+	for _, c := range cases {
+		if matchKey(c.pattern, c.key, c.arch) != c.match {
+			t.Errorf("%q, %q, arch %q: expected %v", c.pattern, c.key, c.arch, c.match)
+		}
+	}
+
+	spec, found := LookupKey("llama.attention.head_count", "llama")
+	if !found || spec.Key != "{arch}.attention.head_count" {
+		t.Errorf("LookupKey: expected {arch}.attention.head_count, got %q, %v", spec.Key, found)
+	}
+
+	_, found = LookupKey("llama.attention.head_count", "")
+	if found {
+		t.Error("LookupKey: {arch} matched without an architecture")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	findings := llamaMetadata(uint32(2)).Check()
+	if len(findings) != 0 {
+		t.Fatalf("expected no findings for valid metadata, got %v", findings)
+	}
+
+	cases := []struct {
+		key      string
+		value    interface{}
+		expected *Finding
+	}{
+		// Types, also of array elements and of pattern keys.
+		{"general.name", uint32(1), &Finding{SeverityError, "", "general.name", "type is uint32, expected string"}},
+		{"general.tags", []uint32{1}, &Finding{SeverityError, "", "general.tags", "type is array of uint32, expected array of string"}},
+		{"general.tags", []MetaArray{}, &Finding{SeverityError, "", "general.tags", "type is array of array, expected array of string"}},
+		{"general.file_type", int32(1), &Finding{SeverityError, "", "general.file_type", "type is int32, expected uint32"}},
+		{"llama.attention.head_count", []float32{1}, &Finding{SeverityError, "", "llama.attention.head_count", "type is array of float32, expected uint32 or uint64 or array of uint32 or array of int32 or array of uint64"}},
+		{"general.base_model.0.name", uint8(1), &Finding{SeverityError, "", "general.base_model.0.name", "type is uint8, expected string"}},
+		{"tokenizer.chat_template.tool_use", true, &Finding{SeverityError, "", "tokenizer.chat_template.tool_use", "type is bool, expected string"}},
+
+		// Valid values, also for the pattern keys.
+		{"general.tags", []string{"a"}, nil},
+		{"llama.attention.head_count", []int32{4, 4}, nil},
+		{"general.base_model.0.name", "base", nil},
+		{"tokenizer.chat_template.tool_use", "{{ x }}", nil},
+
+		// Keys outside the well-known namespaces are allowed.
+		{"custom.setting", uint8(1), nil},
+
+		// Unknown keys in a well-known namespace or the namespace of
+		// the architecture.
+		{"tokenizer.foo", uint8(1), &Finding{SeverityWarning, "", "tokenizer.foo", "unknown key"}},
+		{"general.base_model.x.name", "a", &Finding{SeverityWarning, "", "general.base_model.x.name", "unknown key"}},
+		{"llama.something_new", uint8(1), &Finding{SeverityWarning, "", "llama.something_new", "unknown key"}},
+
+		// Typos. Short names only allow a single edit.
+		{"general.nmae", "a", &Finding{SeverityWarning, "", "general.nmae", `unknown key, did you mean "general.name"?`}},
+		{"general.nam", "a", &Finding{SeverityWarning, "", "general.nam", `unknown key, did you mean "general.name"?`}},
+		{"general.nm", "a", &Finding{SeverityWarning, "", "general.nm", "unknown key"}},
+		{"llama.context_lenght", uint32(1), &Finding{SeverityWarning, "", "llama.context_lenght", `unknown key, did you mean "llama.context_length"?`}},
+		{"llama.contxt_lenght", uint32(1), &Finding{SeverityWarning, "", "llama.contxt_lenght", `unknown key, did you mean "llama.context_length"?`}},
+		{"llama.cntxt_lenght", uint32(1), &Finding{SeverityWarning, "", "llama.cntxt_lenght", "unknown key"}},
+
+		// Typos are reported outside the well-known namespaces too.
+		{"lama.context_length", uint32(1), &Finding{SeverityWarning, "", "lama.context_length", `unknown key, did you mean "llama.context_length"?`}},
+	}
+
+	for _, c := range cases {
+		m := llamaMetadata(uint32(2))
+		m[c.key] = c.value
+
+		findings := m.Check()
+
+		if c.expected == nil {
+			if len(findings) != 0 {
+				t.Errorf("%s: expected no findings, got %v", c.key, findings)
+			}
+
+			continue
+		}
+
+		found := false
+
+		for _, f := range findings {
+			if f == *c.expected {
+				found = true
+			}
+		}
+
+		if !found {
+			t.Errorf("%s: expected %q, got %v", c.key, *c.expected, findings)
+		}
+	}
+}
+
+func TestCheckRequired(t *testing.T) {
+	findings := Metadata{"general.name": "x"}.Check()
+
+	expected := []Finding{{Severity: SeverityError, Key: "general.architecture", Message: "required key is missing"}}
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("expected %v, got %v", expected, findings)
+	}
+
+	// Architectures without a config still need the common keys.
+	findings = Metadata{"general.architecture": "unknown", "unknown.context_length": uint32(1)}.Check()
+
+	expected = []Finding{
+		{Severity: SeverityError, Key: "unknown.embedding_length", Message: `required by architecture "unknown", but missing`},
+		{Severity: SeverityError, Key: "unknown.block_count", Message: `required by architecture "unknown", but missing`},
+	}
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("expected %v, got %v", expected, findings)
+	}
+}
@@ -0,0 +1,174 @@
+package gguf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Limits bounds what the parser accepts from a GGUF header. All
+// counts and lengths in a GGUF file are read from the file itself, so
+// without limits a small, corrupt or malicious file can cause huge
+// allocations.
+type Limits struct {
+	// MaxStringLength is the maximum length in bytes of a string,
+	// including metadata keys and tensor names.
+	MaxStringLength uint64
+
+	// MaxArrayLength is the maximum number of elements in a metadata
+	// array.
+	MaxArrayLength uint64
+
+	// MaxArrayDepth is the maximum nesting depth of arrays of arrays.
+	MaxArrayDepth uint64
+
+	// MaxMetadataCount is the maximum number of metadata key/value
+	// pairs.
+	MaxMetadataCount uint64
+
+	// MaxTensorCount is the maximum number of tensors.
+	MaxTensorCount uint64
+
+	// MaxDimensions is the maximum number of dimensions of a tensor.
+	MaxDimensions uint64
+
+	// MaxHeaderSize is the maximum size in bytes of the header,
+	// everything before the tensor data.
+	MaxHeaderSize uint64
+}
+
+// DefaultLimits are the limits used if none are given. They are well
+// above what is found in real models. Strings like
+// tokenizer.huggingface.json can be tens of megabytes, so strings get
+// a generous limit; like all counts read from the file, their length
+// is also checked against the remaining file size before allocating.
+var DefaultLimits = Limits{
+	MaxStringLength:  256 << 20,
+	MaxArrayLength:   16 << 20,
+	MaxArrayDepth:    8,
+	MaxMetadataCount: 1 << 16,
+	MaxTensorCount:   1 << 20,
+	MaxDimensions:    8,
+	MaxHeaderSize:    1 << 30,
+}
+
+// withDefaults returns a copy of l with all zero limits set to their
+// default value.
+func (l Limits) withDefaults() Limits {
+	set := func(v *uint64, def uint64) {
+		if *v == 0 {
+			*v = def
+		}
+	}
+
+	set(&l.MaxStringLength, DefaultLimits.MaxStringLength)
+	set(&l.MaxArrayLength, DefaultLimits.MaxArrayLength)
+	set(&l.MaxArrayDepth, DefaultLimits.MaxArrayDepth)
+	set(&l.MaxMetadataCount, DefaultLimits.MaxMetadataCount)
+	set(&l.MaxTensorCount, DefaultLimits.MaxTensorCount)
+	set(&l.MaxDimensions, DefaultLimits.MaxDimensions)
+	set(&l.MaxHeaderSize, DefaultLimits.MaxHeaderSize)
+
+	return l
+}
+
+var (
+	// ErrLimitExceeded is wrapped by all LimitErrors.
+	ErrLimitExceeded = errors.New("limit exceeded")
+
+	// ErrTruncated is returned if the header claims more data than
+	// the file holds.
+	ErrTruncated = errors.New("truncated file")
+)
+
+// LimitError is returned when a file exceeds one of the Limits.
+type LimitError struct {
+	// Limit is the name of the limit exceeded, like
+	// "MaxStringLength".
+	Limit string
+
+	// Value is the value found in the file.
+	Value uint64
+
+	// Max is the limit.
+	Max uint64
+}
+
+// Error implements error.
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %d exceeds %s of %d", ErrLimitExceeded, e.Value, e.Limit, e.Max)
+}
+
+// Unwrap returns ErrLimitExceeded.
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// checkLimit returns a LimitError if value exceeds max.
+func checkLimit(limit string, value uint64, max uint64) error {
+	if value > max {
+		return &LimitError{Limit: limit, Value: value, Max: max}
+	}
+
+	return nil
+}
+
+// Option configures how a file is opened.
+type Option func(*options)
+
+// options is the configuration set by Option.
+type options struct {
+	limits Limits
+
+	// trimStrings trims strings in Metadata and tensor names.
+	trimStrings bool
+}
+
+// newOptions returns the configuration resulting from applying opts.
+func newOptions(opts []Option) options {
+	o := options{
+		limits: DefaultLimits,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	o.limits = o.limits.withDefaults()
+
+	return o
+}
+
+// WithLimits sets the limits used when parsing the header. Zero fields
+// are set to the value from DefaultLimits.
+func WithLimits(limits Limits) Option {
+	return func(o *options) {
+		o.limits = limits
+	}
+}
+
+// headerReader counts the bytes read from r.
+type headerReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read implements io.Reader.
+func (h *headerReader) Read(p []byte) (int, error) {
+	n, err := h.r.Read(p)
+	h.n += int64(n)
+
+	return n, err
+}
+
+// WithTrimmedStrings trims leading and trailing whitespace and NUL
+// characters from metadata keys, string values and tensor names, as
+// earlier versions of this package did. Entries always holds the
+// strings exactly as found in the file. Trimming alters tokens like
+// " the" or "\n", so it should only be used by code depending on the
+// old behavior.
+func WithTrimmedStrings() Option {
+	return func(o *options) {
+		o.trimStrings = true
+	}
+}
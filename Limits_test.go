@@ -0,0 +1,208 @@
+package gguf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// openBytes opens data with opts.
+func openBytes(data []byte, opts ...Option) (*Reader, error) {
+	return OpenReaderAt(bytes.NewReader(data), int64(len(data)), opts...)
+}
+
+// writeBytes returns the file written by w.
+func writeBytes(t *testing.T, w *Writer) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+
+	_, err := w.WriteTo(&b)
+	if err != nil {
+		t.Fatalf("WriteTo: %s", err)
+	}
+
+	return b.Bytes()
+}
+
+func TestLimits(t *testing.T) {
+	nested := []MetaArray{{Type: Array, Value: []MetaArray{{Type: Uint8, Value: []uint8{1}}}}}
+
+	cases := []struct {
+		limits   Limits
+		expected LimitError
+		build    func(w *Writer) error
+	}{
+		{
+			limits:   Limits{MaxStringLength: 4},
+			expected: LimitError{Limit: "MaxStringLength", Value: 5, Max: 4},
+			build: func(w *Writer) error {
+				return w.AddMetadata("a", String, "hello")
+			},
+		},
+		{
+			// Keys are strings too.
+			limits:   Limits{MaxStringLength: 4},
+			expected: LimitError{Limit: "MaxStringLength", Value: 6, Max: 4},
+			build: func(w *Writer) error {
+				return w.AddMetadata("a.long", Uint8, uint8(1))
+			},
+		},
+		{
+			limits:   Limits{MaxArrayLength: 2},
+			expected: LimitError{Limit: "MaxArrayLength", Value: 3, Max: 2},
+			build: func(w *Writer) error {
+				return w.AddArray("a", Uint32, []uint32{1, 2, 3})
+			},
+		},
+		{
+			limits:   Limits{MaxArrayDepth: 2},
+			expected: LimitError{Limit: "MaxArrayDepth", Value: 3, Max: 2},
+			build: func(w *Writer) error {
+				return w.AddArray("a", Array, nested)
+			},
+		},
+		{
+			limits:   Limits{MaxMetadataCount: 1},
+			expected: LimitError{Limit: "MaxMetadataCount", Value: 2, Max: 1},
+			build: func(w *Writer) error {
+				err := w.AddMetadata("a", Uint8, uint8(1))
+				if err != nil {
+					return err
+				}
+
+				return w.AddMetadata("b", Uint8, uint8(1))
+			},
+		},
+		{
+			limits:   Limits{MaxTensorCount: 1},
+			expected: LimitError{Limit: "MaxTensorCount", Value: 2, Max: 1},
+			build: func(w *Writer) error {
+				err := w.AddTensor("a", []uint64{1}, GgmlFloat32, bytes.NewReader(make([]byte, 4)))
+				if err != nil {
+					return err
+				}
+
+				return w.AddTensor("b", []uint64{1}, GgmlFloat32, bytes.NewReader(make([]byte, 4)))
+			},
+		},
+		{
+			limits:   Limits{MaxDimensions: 2},
+			expected: LimitError{Limit: "MaxDimensions", Value: 3, Max: 2},
+			build: func(w *Writer) error {
+				return w.AddTensor("a", []uint64{1, 1, 1}, GgmlFloat32, bytes.NewReader(make([]byte, 4)))
+			},
+		},
+		{
+			// The string ends at offset 24+8+1+4+8+100.
+			limits:   Limits{MaxHeaderSize: 100},
+			expected: LimitError{Limit: "MaxHeaderSize", Value: 145, Max: 100},
+			build: func(w *Writer) error {
+				return w.AddMetadata("a", String, strings.Repeat("x", 100))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		w := NewWriter(binary.LittleEndian)
+
+		err := c.build(w)
+		if err != nil {
+			t.Fatalf("%s: %s", c.expected.Limit, err)
+		}
+
+		data := writeBytes(t, w)
+
+		// The file is fine with the default limits.
+		_, err = openBytes(data)
+		if err != nil {
+			t.Fatalf("%s: default limits: %s", c.expected.Limit, err)
+		}
+
+		_, err = openBytes(data, WithLimits(c.limits))
+
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) || !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("%s: expected a *LimitError, got %v", c.expected.Limit, err)
+
+			continue
+		}
+
+		if *limitErr != c.expected {
+			t.Errorf("%s: expected %+v, got %+v", c.expected.Limit, c.expected, *limitErr)
+		}
+	}
+}
+
+func TestLimitsTruncated(t *testing.T) {
+	le := binary.LittleEndian
+
+	// header returns a v3 header with the given counts.
+	header := func(tensors, entries uint64) []byte {
+		b := []byte(magic)
+		b = le.AppendUint32(b, 3)
+		b = le.AppendUint64(b, tensors)
+
+		return le.AppendUint64(b, entries)
+	}
+
+	// key appends the key "a" and the type typ to b.
+	key := func(b []byte, typ Type) []byte {
+		b = le.AppendUint64(b, 1)
+		b = append(b, 'a')
+
+		return le.AppendUint32(b, uint32(typ))
+	}
+
+	stringValue := le.AppendUint64(key(header(0, 1), String), 1<<20)
+
+	array := le.AppendUint32(key(header(0, 1), Array), uint32(Uint64))
+	array = le.AppendUint64(array, 1<<20)
+
+	stringArray := le.AppendUint32(key(header(0, 1), Array), uint32(String))
+	stringArray = le.AppendUint64(stringArray, 1<<20)
+
+	cases := map[string][]byte{
+		"string":         stringValue,
+		"key":            le.AppendUint64(header(0, 1), 1<<20),
+		"array":          array,
+		"string array":   stringArray,
+		"metadata count": header(0, 1<<16),
+		"tensor count":   header(1<<20, 0),
+		"tensor name":    le.AppendUint64(header(1, 0), 1<<20),
+	}
+
+	for name, data := range cases {
+		// Pad, so the counts are the only problem.
+		data = append(data, make([]byte, 64)...)
+
+		_, err := openBytes(data)
+		if !errors.Is(err, ErrTruncated) {
+			t.Errorf("%s: expected ErrTruncated, got %v", name, err)
+		}
+	}
+}
+
+func TestLimitsLargeString(t *testing.T) {
+	// Chat templates and tokenizer.huggingface.json can be large.
+	json := strings.Repeat("x", 20<<20)
+
+	w := NewWriter(binary.LittleEndian)
+
+	err := w.AddMetadata("tokenizer.huggingface.json", String, json)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := openBytes(writeBytes(t, w))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, _ := r.Metadata.String("tokenizer.huggingface.json")
+	if len(s) != len(json) {
+		t.Errorf("expected %d bytes, got %d", len(json), len(s))
+	}
+}
@@ -0,0 +1,137 @@
+package gguf
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MetaEntry is a single metadata key/value pair with an explicit GGUF
+// type.
+type MetaEntry struct {
+	// Key is the name of the metadata value.
+	Key string
+
+	// Type is the GGUF type of the value.
+	Type Type
+
+	// ArrayType is the type of the elements if Type is Array.
+	ArrayType Type
+
+	// Value is the value itself. Scalars are stored as their
+	// corresponding Go type, arrays as a slice of the Go type. Arrays
+	// of arrays are stored as []MetaArray.
+	Value interface{}
+}
+
+// MetaArray is an element of an array of arrays. As every nested array
+// can have its own element type, the type is kept for each of them.
+type MetaArray struct {
+	// Type is the type of the elements in the array.
+	Type Type
+
+	// Value is a slice of the Go type corresponding to Type, or
+	// []MetaArray if Type is Array.
+	Value interface{}
+}
+
+// Len returns the number of elements in the array.
+func (a MetaArray) Len() int {
+	v := reflect.ValueOf(a.Value)
+	if v.Kind() != reflect.Slice {
+		return 0
+	}
+
+	return v.Len()
+}
+
+// MetaArrayValues returns the elements of a as a []T. If the elements
+// are not of type T, an error is returned.
+func MetaArrayValues[T any](a MetaArray) ([]T, error) {
+	v, ok := a.Value.([]T)
+	if !ok {
+		var zero T
+
+		return nil, fmt.Errorf("array is not of type %T, element type is %s", zero, a.Type)
+	}
+
+	return v, nil
+}
+
+// goTypes maps scalar GGUF types to their Go types.
+var goTypes = map[Type]reflect.Type{
+	Uint8:   reflect.TypeOf(uint8(0)),
+	Int8:    reflect.TypeOf(int8(0)),
+	Uint16:  reflect.TypeOf(uint16(0)),
+	Int16:   reflect.TypeOf(int16(0)),
+	Uint32:  reflect.TypeOf(uint32(0)),
+	Int32:   reflect.TypeOf(int32(0)),
+	Float32: reflect.TypeOf(float32(0)),
+	Bool:    reflect.TypeOf(false),
+	String:  reflect.TypeOf(""),
+	Uint64:  reflect.TypeOf(uint64(0)),
+	Int64:   reflect.TypeOf(int64(0)),
+	Float64: reflect.TypeOf(float64(0)),
+}
+
+// normalizeScalar converts value to the Go type used for typ. Only
+// values of the same kind are accepted, a uint64 will never be
+// silently converted to a uint32.
+func normalizeScalar(typ Type, value interface{}) (interface{}, error) {
+	goType, found := goTypes[typ]
+	if !found {
+		return nil, fmt.Errorf("invalid scalar type: %s", typ)
+	}
+
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || v.Kind() != goType.Kind() {
+		return nil, fmt.Errorf("value of type %T cannot be stored as %s", value, typ)
+	}
+
+	return v.Convert(goType).Interface(), nil
+}
+
+// normalizeArray converts value to a slice of the Go type used for
+// elemType. Arrays of arrays must be given as []MetaArray.
+func normalizeArray(elemType Type, value interface{}) (interface{}, error) {
+	if elemType == Array {
+		nested, ok := value.([]MetaArray)
+		if !ok {
+			return nil, fmt.Errorf("array of arrays must be []MetaArray, got %T", value)
+		}
+
+		a := make([]MetaArray, len(nested))
+
+		for i, n := range nested {
+			v, err := normalizeArray(n.Type, n.Value)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+
+			a[i] = MetaArray{Type: n.Type, Value: v}
+		}
+
+		return a, nil
+	}
+
+	goType, found := goTypes[elemType]
+	if !found {
+		return nil, fmt.Errorf("unsupported array type: %s", elemType)
+	}
+
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) {
+		return nil, fmt.Errorf("value of type %T is not an array", value)
+	}
+
+	if v.Type().Elem().Kind() != goType.Kind() {
+		return nil, fmt.Errorf("array of type %T cannot be stored as array of %s", value, elemType)
+	}
+
+	a := reflect.MakeSlice(reflect.SliceOf(goType), v.Len(), v.Len())
+
+	for i := 0; i < v.Len(); i++ {
+		a.Index(i).Set(v.Index(i).Convert(goType))
+	}
+
+	return a.Interface(), nil
+}
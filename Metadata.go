@@ -1,13 +1,54 @@
 package gguf
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 )
 
 // Metadata is a container for metadata in a GGUF file. Values are
 // mapped to their corresponding Go types.
 type Metadata map[string]interface{}
 
+var (
+	// ErrNotFound is wrapped by MetaValueErrors for missing keys.
+	ErrNotFound = errors.New("not found")
+
+	// ErrWrongType is wrapped by MetaValueErrors for values of a type
+	// that cannot be converted to the requested type.
+	ErrWrongType = errors.New("wrong type")
+
+	// ErrOutOfRange is wrapped by MetaValueErrors for numbers that
+	// cannot be represented exactly in the requested type.
+	ErrOutOfRange = errors.New("out of range")
+)
+
+// MetaValueError is returned by the Metadata accessors.
+type MetaValueError struct {
+	// Key is the name of the metadata value.
+	Key string
+
+	// Err is ErrNotFound, ErrWrongType or ErrOutOfRange.
+	Err error
+
+	// Reason describes the problem, if there is more to tell.
+	Reason string
+}
+
+// Error implements error.
+func (e *MetaValueError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("metadata value %q: %s", e.Key, e.Err)
+	}
+
+	return fmt.Sprintf("metadata value %q: %s: %s", e.Key, e.Err, e.Reason)
+}
+
+// Unwrap returns Err.
+func (e *MetaValueError) Unwrap() error {
+	return e.Err
+}
+
 // Int returns the value of the metadata with the given name as an
 // int. If the value cannot be represented as an int, an error is
 // returned.
@@ -15,6 +56,30 @@ func (m Metadata) Int(name string) (int, error) {
 	return MetaValueNumber[int](m, name)
 }
 
+// Int64 returns the value of the metadata with the given name as an
+// int64.
+func (m Metadata) Int64(name string) (int64, error) {
+	return MetaValueNumber[int64](m, name)
+}
+
+// Uint64 returns the value of the metadata with the given name as a
+// uint64. Negative values are out of range.
+func (m Metadata) Uint64(name string) (uint64, error) {
+	return MetaValueNumber[uint64](m, name)
+}
+
+// Float64 returns the value of the metadata with the given name as a
+// float64. Integers must be represented exactly.
+func (m Metadata) Float64(name string) (float64, error) {
+	return MetaValueNumber[float64](m, name)
+}
+
+// Bool returns the value of the metadata with the given name as a
+// bool.
+func (m Metadata) Bool(name string) (bool, error) {
+	return MetaValue[bool](m, name)
+}
+
 // Any returns the value of the metadata with the given name as an
 // interface{}.
 func (m Metadata) Any(name string) (interface{}, error) {
@@ -27,68 +92,168 @@ func (m Metadata) String(name string) (string, error) {
 	return MetaValue[string](m, name)
 }
 
+// Strings returns the value of the metadata with the given name as a
+// slice of strings. If the value is not an array of strings, an error
+// is returned.
+func (m Metadata) Strings(name string) ([]string, error) {
+	return MetaValue[[]string](m, name)
+}
+
+// Arrays returns the value of the metadata with the given name as an
+// array of arrays. If the value is not an array of arrays, an error is
+// returned.
+func (m Metadata) Arrays(name string) ([]MetaArray, error) {
+	return MetaValue[[]MetaArray](m, name)
+}
+
+// Ints returns the value of the metadata with the given name, an array
+// of numbers, as a slice of ints.
+func (m Metadata) Ints(name string) ([]int, error) {
+	return MetaValueNumbers[int](m, name)
+}
+
+// Int64s returns the value of the metadata with the given name, an
+// array of numbers, as a slice of int64s.
+func (m Metadata) Int64s(name string) ([]int64, error) {
+	return MetaValueNumbers[int64](m, name)
+}
+
+// Uint64s returns the value of the metadata with the given name, an
+// array of numbers, as a slice of uint64s.
+func (m Metadata) Uint64s(name string) ([]uint64, error) {
+	return MetaValueNumbers[uint64](m, name)
+}
+
+// Float64s returns the value of the metadata with the given name, an
+// array of numbers, as a slice of float64s.
+func (m Metadata) Float64s(name string) ([]float64, error) {
+	return MetaValueNumbers[float64](m, name)
+}
+
+// IntOr is like Int, but returns def if the value is not found.
+func (m Metadata) IntOr(name string, def int) (int, error) {
+	return metaValueOr(m, name, def, m.Int)
+}
+
+// Int64Or is like Int64, but returns def if the value is not found.
+func (m Metadata) Int64Or(name string, def int64) (int64, error) {
+	return metaValueOr(m, name, def, m.Int64)
+}
+
+// Uint64Or is like Uint64, but returns def if the value is not found.
+func (m Metadata) Uint64Or(name string, def uint64) (uint64, error) {
+	return metaValueOr(m, name, def, m.Uint64)
+}
+
+// Float64Or is like Float64, but returns def if the value is not found.
+func (m Metadata) Float64Or(name string, def float64) (float64, error) {
+	return metaValueOr(m, name, def, m.Float64)
+}
+
+// BoolOr is like Bool, but returns def if the value is not found.
+func (m Metadata) BoolOr(name string, def bool) (bool, error) {
+	return metaValueOr(m, name, def, m.Bool)
+}
+
+// StringOr is like String, but returns def if the value is not found.
+func (m Metadata) StringOr(name string, def string) (string, error) {
+	return metaValueOr(m, name, def, m.String)
+}
+
+// metaValueOr returns def if name is not found, and the result of get
+// otherwise. Values of the wrong type are still an error.
+func metaValueOr[T any](metadata Metadata, name string, def T, get func(string) (T, error)) (T, error) {
+	if _, found := metadata[name]; !found {
+		return def, nil
+	}
+
+	return get(name)
+}
+
 // MetaValue returns the value of the metadata with the given name as
 // type T. If the value is not a T, an error is returned.
 func MetaValue[T any](metadata Metadata, name string) (T, error) {
 	var zero T
 	v, found := metadata[name]
 	if !found {
-		return zero, fmt.Errorf("metadata value %q not found", name)
+		return zero, &MetaValueError{Key: name, Err: ErrNotFound}
 	}
 
 	if _, ok := v.(T); !ok {
-		return zero, fmt.Errorf("metadata value %q is not of type %T, type is %T", name, zero, v)
+		return zero, &MetaValueError{Key: name, Err: ErrWrongType, Reason: fmt.Sprintf("type is %T, not %T", v, zero)}
 	}
 
 	return v.(T), nil
 }
 
+// Number is the set of types accepted by MetaValueNumber and
+// MetaValueNumbers.
+type Number interface {
+	~int | ~uint8 | ~int8 | ~uint16 | ~int16 | ~uint32 | ~int32 | ~uint64 | ~int64 | ~float32 | ~float64
+}
+
 // MetaValueNumber returns the value of the metadata with the given
 // name as a number. If the value is not a number, an error is
-// returned. The number will be cast to the type T. This can be
-// useful if you don't really care about the exact type of the
-// number.
-func MetaValueNumber[T ~int | ~uint8 | ~int8 | ~uint16 | ~int16 | ~uint32 | ~int32 | ~uint64 | ~int64 | ~float32 | ~float64](metadata Metadata, name string) (T, error) {
+// returned. The number will be converted to the type T if it can be
+// represented exactly, so negative values are never returned as
+// unsigned integers, and floats with a fraction never as integers.
+// This can be useful if you don't really care about the exact type of
+// the number.
+func MetaValueNumber[T Number](metadata Metadata, name string) (T, error) {
+	var n T
+
 	v, found := metadata[name]
 	if !found {
-		return 0, fmt.Errorf("metadata value %q not found", name)
+		return n, &MetaValueError{Key: name, Err: ErrNotFound}
 	}
 
-	switch vv := v.(type) {
-	case int:
-		return T(vv), nil
-
-	case uint8:
-		return T(vv), nil
-
-	case int8:
-		return T(vv), nil
+	if !isNumber(reflect.ValueOf(v)) {
+		return n, &MetaValueError{Key: name, Err: ErrWrongType, Reason: fmt.Sprintf("type is %T, not a number", v)}
+	}
 
-	case uint16:
-		return T(vv), nil
+	err := assignValue(reflect.ValueOf(&n).Elem(), v)
+	if err != nil {
+		return n, &MetaValueError{Key: name, Err: ErrOutOfRange, Reason: err.Error()}
+	}
 
-	case int16:
-		return T(vv), nil
+	return n, nil
+}
 
-	case uint32:
-		return T(vv), nil
+// MetaValueNumbers returns the value of the metadata with the given
+// name, an array of numbers of any type, as a slice of T. Every
+// element is converted like MetaValueNumber does.
+func MetaValueNumbers[T Number](metadata Metadata, name string) ([]T, error) {
+	v, found := metadata[name]
+	if !found {
+		return nil, &MetaValueError{Key: name, Err: ErrNotFound}
+	}
 
-	case int32:
-		return T(vv), nil
+	sv := reflect.ValueOf(v)
+	if sv.Kind() != reflect.Slice || !isNumber(reflect.Zero(sv.Type().Elem())) {
+		return nil, &MetaValueError{Key: name, Err: ErrWrongType, Reason: fmt.Sprintf("type is %T, not an array of numbers", v)}
+	}
 
-	case uint64:
-		return T(vv), nil
+	numbers := make([]T, sv.Len())
 
-	case int64:
-		return T(vv), nil
+	for i := range numbers {
+		err := assignValue(reflect.ValueOf(&numbers[i]).Elem(), sv.Index(i).Interface())
+		if err != nil {
+			return nil, &MetaValueError{Key: name, Err: ErrOutOfRange, Reason: fmt.Sprintf("element %d: %s", i, err)}
+		}
+	}
 
-	case float32:
-		return T(vv), nil
+	return numbers, nil
+}
 
-	case float64:
-		return T(vv), nil
+// isNumber returns true if v is an integer or a float.
+func isNumber(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
 
 	default:
-		return 0, fmt.Errorf("metadata value %q is not a number, type is %T", name, v)
+		return false
 	}
 }
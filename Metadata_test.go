@@ -0,0 +1,190 @@
+package gguf
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// testMetadata holds a value of most types.
+var testMetadata = Metadata{
+	"u8":         uint8(200),
+	"i32":        int32(-5),
+	"u32":        uint32(4096),
+	"u64":        uint64(math.MaxUint64),
+	"u64.small":  uint64(1 << 40),
+	"i64":        int64(-1),
+	"f32":        float32(0.5),
+	"f64":        float64(1e6),
+	"bool":       true,
+	"string":     "llama",
+	"strings":    []string{"a", "b"},
+	"u32s":       []uint32{1, 2},
+	"i32s":       []int32{1, -2},
+	"f32s":       []float32{1, 2.5},
+	"arrays":     []MetaArray{{Type: Uint8, Value: []uint8{1}}},
+	"u64s.large": []uint64{1, math.MaxUint64},
+}
+
+func TestMetadataAccessors(t *testing.T) {
+	m := testMetadata
+
+	check := func(name string, got interface{}, err error, expected interface{}) {
+		t.Helper()
+
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+
+			return
+		}
+
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("%s: expected %v (%T), got %v (%T)", name, expected, expected, got, got)
+		}
+	}
+
+	i, err := m.Int("u32")
+	check("Int", i, err, 4096)
+
+	i, err = m.Int("f64")
+	check("Int of float", i, err, 1000000)
+
+	i64, err := m.Int64("u64.small")
+	check("Int64", i64, err, int64(1<<40))
+
+	u64, err := m.Uint64("u64")
+	check("Uint64", u64, err, uint64(math.MaxUint64))
+
+	f, err := m.Float64("f32")
+	check("Float64", f, err, 0.5)
+
+	f, err = m.Float64("i32")
+	check("Float64 of int", f, err, -5.0)
+
+	b, err := m.Bool("bool")
+	check("Bool", b, err, true)
+
+	s, err := m.String("string")
+	check("String", s, err, "llama")
+
+	ss, err := m.Strings("strings")
+	check("Strings", ss, err, []string{"a", "b"})
+
+	a, err := m.Arrays("arrays")
+	check("Arrays", a, err, []MetaArray{{Type: Uint8, Value: []uint8{1}}})
+
+	is, err := m.Ints("i32s")
+	check("Ints", is, err, []int{1, -2})
+
+	i64s, err := m.Int64s("u32s")
+	check("Int64s", i64s, err, []int64{1, 2})
+
+	u64s, err := m.Uint64s("u64s.large")
+	check("Uint64s", u64s, err, []uint64{1, math.MaxUint64})
+
+	fs, err := m.Float64s("f32s")
+	check("Float64s", fs, err, []float64{1, 2.5})
+
+	v, err := m.Any("u8")
+	check("Any", v, err, uint8(200))
+}
+
+func TestMetadataErrors(t *testing.T) {
+	m := testMetadata
+
+	cases := []struct {
+		name     string
+		get      func() (interface{}, error)
+		expected error
+	}{
+		{"Int missing", func() (interface{}, error) { return m.Int("missing") }, ErrNotFound},
+		{"Int of string", func() (interface{}, error) { return m.Int("string") }, ErrWrongType},
+		{"Int of array", func() (interface{}, error) { return m.Int("u32s") }, ErrWrongType},
+		{"Int of fraction", func() (interface{}, error) { return m.Int("f32") }, ErrOutOfRange},
+		{"Int64 above MaxInt64", func() (interface{}, error) { return m.Int64("u64") }, ErrOutOfRange},
+		{"Uint64 of negative", func() (interface{}, error) { return m.Uint64("i64") }, ErrOutOfRange},
+		{"Uint64 of negative int32", func() (interface{}, error) { return m.Uint64("i32") }, ErrOutOfRange},
+		{"Bool of number", func() (interface{}, error) { return m.Bool("u8") }, ErrWrongType},
+		{"String missing", func() (interface{}, error) { return m.String("missing") }, ErrNotFound},
+		{"String of number", func() (interface{}, error) { return m.String("u32") }, ErrWrongType},
+		{"Strings of string", func() (interface{}, error) { return m.Strings("string") }, ErrWrongType},
+		{"MetaValueNumbers of negative element", func() (interface{}, error) { return MetaValueNumbers[uint32](m, "i32s") }, ErrOutOfRange},
+		{"Ints of large element", func() (interface{}, error) { return m.Ints("u64s.large") }, ErrOutOfRange},
+		{"Ints of fraction element", func() (interface{}, error) { return m.Ints("f32s") }, ErrOutOfRange},
+		{"Ints of strings", func() (interface{}, error) { return m.Ints("strings") }, ErrWrongType},
+		{"Ints of scalar", func() (interface{}, error) { return m.Ints("u32") }, ErrWrongType},
+		{"Ints missing", func() (interface{}, error) { return m.Ints("missing") }, ErrNotFound},
+		{"uint8 overflow", func() (interface{}, error) { return MetaValueNumber[uint8](m, "u32") }, ErrOutOfRange},
+		{"int8 overflow", func() (interface{}, error) { return MetaValueNumber[int8](m, "u8") }, ErrOutOfRange},
+	}
+
+	for _, c := range cases {
+		_, err := c.get()
+
+		if !errors.Is(err, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, err)
+
+			continue
+		}
+
+		var valueErr *MetaValueError
+		if !errors.As(err, &valueErr) {
+			t.Errorf("%s: expected a *MetaValueError, got %T", c.name, err)
+		}
+
+		for _, other := range []error{ErrNotFound, ErrWrongType, ErrOutOfRange} {
+			if other != c.expected && errors.Is(err, other) {
+				t.Errorf("%s: %v is also %v", c.name, err, other)
+			}
+		}
+	}
+
+	// The element is part of the error.
+	_, err := m.Ints("u64s.large")
+	if err == nil || err.Error() != `metadata value "u64s.large": out of range: element 1: 18446744073709551615 cannot be represented exactly as an integer` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMetadataOr(t *testing.T) {
+	m := testMetadata
+
+	// Missing keys give the default.
+	i, err := m.IntOr("missing", 42)
+	if err != nil || i != 42 {
+		t.Errorf("IntOr missing: expected 42, got %d, %v", i, err)
+	}
+
+	s, err := m.StringOr("missing", "default")
+	if err != nil || s != "default" {
+		t.Errorf("StringOr missing: expected default, got %q, %v", s, err)
+	}
+
+	// Values found are returned as is.
+	i, err = m.IntOr("u32", 42)
+	if err != nil || i != 4096 {
+		t.Errorf("IntOr: expected 4096, got %d, %v", i, err)
+	}
+
+	// The wrong type is still an error, not the default.
+	i, err = m.IntOr("string", 42)
+	if !errors.Is(err, ErrWrongType) {
+		t.Errorf("IntOr wrong type: expected ErrWrongType, got %d, %v", i, err)
+	}
+
+	_, err = m.Uint64Or("i64", 1)
+	if !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Uint64Or negative: expected ErrOutOfRange, got %v", err)
+	}
+
+	_, err = m.BoolOr("string", true)
+	if !errors.Is(err, ErrWrongType) {
+		t.Errorf("BoolOr wrong type: expected ErrWrongType, got %v", err)
+	}
+
+	f, err := m.Float64Or("missing", 0.25)
+	if err != nil || f != 0.25 {
+		t.Errorf("Float64Or missing: expected 0.25, got %v, %v", f, err)
+	}
+}
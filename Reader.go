@@ -5,8 +5,12 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
+	"math/bits"
 	"os"
 	"strings"
+	"sync"
+	"unicode/utf8"
 )
 
 // V1: https://github.com/philpax/ggml/blob/2b65fba00c83b9fa041df2ac55ccd8c2f10c5281/docs/gguf.md
@@ -15,7 +19,22 @@ import (
 
 // Reader is a reader for GGUF files.
 type Reader struct {
-	r io.ReadSeeker
+	// r is used for reading the header.
+	r *headerReader
+
+	// options is the configuration given to Open().
+	options options
+
+	// ra is used for reading tensor data.
+	ra io.ReaderAt
+
+	// mapping is the memory mapped file, if opened by OpenMmap().
+	mapping *mmapReaderAt
+
+	// closer is called once by Close().
+	closer    func() error
+	closeOnce sync.Once
+	closeErr  error
 
 	// ByteOrder is the byte order of the GGUF file. This package
 	// does not do any byte swapping for tensor data, it's the
@@ -29,17 +48,125 @@ type Reader struct {
 	// Metadata is the metadata in the file.
 	Metadata Metadata
 
+	// Entries is the metadata in the order found in the file. Unlike
+	// Metadata, the original GGUF type of every value is kept, and
+	// strings are never trimmed.
+	Entries []MetaEntry
+
 	// Tensors is the list of tensors in the file.
 	Tensors []TensorInfo
 
 	tensorOffset int64
 
+	// alignment is the alignment of the tensor data.
+	alignment int64
+
+	// size is the size of the file.
+	size int64
+
+	// invalidStrings are the strings that are not valid UTF-8.
+	invalidStrings []invalidString
+
 	// Helper to read int32 or int64 depending on GGUF version.
 	readUint func(io.Reader, binary.ByteOrder) (uint64, error)
 }
 
-// readString reads a GGUF string from r.
+// need checks that count elements of elemSize bytes can be read
+// without going past the end of the file or MaxHeaderSize. It must be
+// called before allocating memory for a count read from the file.
+func (r *Reader) need(count uint64, elemSize uint64) error {
+	hi, n := bits.Mul64(count, elemSize)
+	if hi != 0 {
+		n = math.MaxUint64
+	}
+
+	pos := uint64(r.r.n)
+
+	end := pos + n
+	if end < pos {
+		end = math.MaxUint64
+	}
+
+	if end > uint64(r.size) {
+		return fmt.Errorf("%w: %d bytes needed at offset %d, file size is %d", ErrTruncated, n, pos, r.size)
+	}
+
+	return checkLimit("MaxHeaderSize", end, r.options.limits.MaxHeaderSize)
+}
+
+// uintSize returns the size in bytes of the lengths and counts in the
+// file.
+func (r *Reader) uintSize() uint64 {
+	if r.Version == 1 {
+		return 4
+	}
+
+	return 8
+}
+
+// invalidString is the location of a string that is not valid UTF-8.
+type invalidString struct {
+	// key is the metadata key, or tensor the name of the tensor, the
+	// string belongs to.
+	key    string
+	tensor string
+
+	// offset is the offset in the file of the first invalid byte.
+	offset int64
+}
+
+// readString reads a GGUF string from r exactly as stored. Strings that
+// are not valid UTF-8 are recorded in invalidStrings, and reported by
+// Verify().
 func (r *Reader) readString() (string, error) {
+	length, err := r.readUint(r.r, r.ByteOrder)
+	if err != nil {
+		return "", err
+	}
+
+	err = checkLimit("MaxStringLength", length, r.options.limits.MaxStringLength)
+	if err != nil {
+		return "", err
+	}
+
+	err = r.need(length, 1)
+	if err != nil {
+		return "", err
+	}
+
+	offset := r.r.n
+	data := make([]byte, length)
+
+	_, err = io.ReadFull(r.r, data)
+	if err != nil {
+		return "", err
+	}
+
+	if !utf8.Valid(data) {
+		r.invalidStrings = append(r.invalidStrings, invalidString{offset: offset + int64(invalidUTF8(data))})
+	}
+
+	return string(data), nil
+}
+
+// invalidUTF8 returns the index of the first invalid UTF-8 sequence in
+// data.
+func invalidUTF8(data []byte) int {
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+
+		i += size
+	}
+
+	return len(data)
+}
+
+// trimString trims leading and trailing whitespace and NUL characters
+// from s.
+func trimString(s string) string {
 	trim := func(r rune) bool {
 		var asciiSpace = [33]bool{
 			0:    true, // null character
@@ -58,21 +185,37 @@ func (r *Reader) readString() (string, error) {
 		return false
 	}
 
-	length, err := r.readUint(r.r, r.ByteOrder)
-	if err != nil {
-		return "", err
-	}
+	return strings.TrimFunc(s, trim)
+}
 
-	data := make([]byte, length)
+// trimValue returns a copy of a metadata value with all strings
+// trimmed by trimString().
+func trimValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return trimString(v)
 
-	_, err = io.ReadFull(r.r, data)
-	if err != nil {
-		return "", err
-	}
+	case []string:
+		a := make([]string, len(v))
 
-	datastr := strings.TrimFunc(string(data), trim)
+		for i, s := range v {
+			a[i] = trimString(s)
+		}
 
-	return string(datastr), nil
+		return a
+
+	case []MetaArray:
+		a := make([]MetaArray, len(v))
+
+		for i, e := range v {
+			a[i] = MetaArray{Type: e.Type, Value: trimValue(e.Value)}
+		}
+
+		return a
+
+	default:
+		return value
+	}
 }
 
 // readMetaDataValueScalar reads a GGUF scalar value from r. String is a special
@@ -128,171 +271,276 @@ func (r *Reader) readMetaDataValueScalar(typ Type) (interface{}, error) {
 
 // readMetaDataValueArray reads a GGUF metadata array from r.
 func readMetaDataValueArray[T readables](r *Reader, length uint64) ([]T, error) {
-	a := make([]T, length)
+	var zero T
 
-	for i := uint64(0); i < length; i++ {
-		v, err := read[T](r.r, r.ByteOrder)
-		if err != nil {
-			return nil, err
-		}
+	err := r.need(length, uint64(binary.Size(zero)))
+	if err != nil {
+		return nil, err
+	}
 
-		a[i] = v
+	a := make([]T, length)
+
+	err = binary.Read(r.r, r.ByteOrder, a)
+	if err != nil {
+		return nil, err
 	}
 
 	return a, nil
 }
 
-// readMetaValue reads a GGUF metadata value from r.
-func (r *Reader) readMetaValue() (interface{}, error) {
+// readMetaValue reads a GGUF metadata value from r and stores it in
+// entry. Strings are stored exactly as found in the file.
+func (r *Reader) readMetaValue(entry *MetaEntry) error {
 	typ, err := read[Type](r.r, r.ByteOrder)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	switch typ {
-	case Array:
-		aType, err := read[Type](r.r, r.ByteOrder)
+	entry.Type = typ
+
+	if typ == Array {
+		entry.ArrayType, err = read[Type](r.r, r.ByteOrder)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		length, err := r.readUint(r.r, r.ByteOrder)
+		entry.Value, err = r.readMetaArray(entry.ArrayType, 1)
+
+		return err
+	}
+
+	entry.Value, err = r.readMetaDataValueScalar(typ)
+
+	return err
+}
+
+// readMetaArray reads the length and elements of a GGUF metadata
+// array with elements of type aType. depth is the nesting depth of the
+// array, 1 for arrays that are not nested.
+func (r *Reader) readMetaArray(aType Type, depth uint64) (interface{}, error) {
+	err := checkLimit("MaxArrayDepth", depth, r.options.limits.MaxArrayDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := r.readUint(r.r, r.ByteOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkLimit("MaxArrayLength", length, r.options.limits.MaxArrayLength)
+	if err != nil {
+		return nil, err
+	}
+
+	switch aType {
+	case Uint8:
+		return readMetaDataValueArray[uint8](r, length)
+
+	case Int8:
+		return readMetaDataValueArray[int8](r, length)
+
+	case Uint16:
+		return readMetaDataValueArray[uint16](r, length)
+
+	case Int16:
+		return readMetaDataValueArray[int16](r, length)
+
+	case Uint32:
+		return readMetaDataValueArray[uint32](r, length)
+
+	case Int32:
+		return readMetaDataValueArray[int32](r, length)
+
+	case Float32:
+		return readMetaDataValueArray[float32](r, length)
+
+	case Bool:
+		a, err := readMetaDataValueArray[uint8](r, length)
 		if err != nil {
 			return nil, err
 		}
 
-		switch aType {
-		case Uint8:
-			return readMetaDataValueArray[uint8](r, length)
+		b := make([]bool, length)
 
-		case Int8:
-			return readMetaDataValueArray[int8](r, length)
-
-		case Uint16:
-			return readMetaDataValueArray[uint16](r, length)
+		for i, v := range a {
+			if v != 0 && v != 1 {
+				return nil, fmt.Errorf("invalid bool value: %d", v)
+			}
 
-		case Int16:
-			return readMetaDataValueArray[int16](r, length)
+			b[i] = v == 1
+		}
 
-		case Uint32:
-			return readMetaDataValueArray[uint32](r, length)
+		return b, nil
 
-		case Int32:
-			return readMetaDataValueArray[int32](r, length)
+	case String:
+		// Every string is at least its length.
+		err = r.need(length, r.uintSize())
+		if err != nil {
+			return nil, err
+		}
 
-		case Float32:
-			return readMetaDataValueArray[float32](r, length)
+		a := make([]string, length)
 
-		case Bool:
-			a, err := readMetaDataValueArray[uint8](r, length)
+		for i := uint64(0); i < length; i++ {
+			v, err := r.readString()
 			if err != nil {
 				return nil, err
 			}
 
-			b := make([]bool, length)
-
-			for i, v := range a {
-				if v != 0 && v != 1 {
-					return nil, fmt.Errorf("invalid bool value: %d", v)
-				}
-
-				b[i] = v == 1
-			}
+			a[i] = v
+		}
 
-			return b, nil
+		return a, nil
 
-		case String:
-			a := make([]string, length)
+	case Uint64:
+		return readMetaDataValueArray[uint64](r, length)
 
-			for i := uint64(0); i < length; i++ {
-				v, err := r.readString()
-				if err != nil {
-					return nil, err
-				}
+	case Int64:
+		return readMetaDataValueArray[int64](r, length)
 
-				a[i] = v
-			}
+	case Float64:
+		return readMetaDataValueArray[float64](r, length)
 
-			return a, nil
+	case Array:
+		// Every array is at least its type and length.
+		err = r.need(length, 4+r.uintSize())
+		if err != nil {
+			return nil, err
+		}
 
-		case Uint64:
-			return readMetaDataValueArray[uint64](r, length)
+		a := make([]MetaArray, 0, length)
 
-		case Int64:
-			return readMetaDataValueArray[int64](r, length)
+		for i := uint64(0); i < length; i++ {
+			typ, err := read[Type](r.r, r.ByteOrder)
+			if err != nil {
+				return nil, err
+			}
 
-		case Float64:
-			return readMetaDataValueArray[float64](r, length)
+			v, err := r.readMetaArray(typ, depth+1)
+			if err != nil {
+				return nil, err
+			}
 
-		default:
-			return nil, fmt.Errorf("unsupported array type: %d", aType)
+			a = append(a, MetaArray{Type: typ, Value: v})
 		}
 
+		return a, nil
+
 	default:
-		return r.readMetaDataValueScalar(Type(typ))
+		return nil, fmt.Errorf("unsupported array type: %d", aType)
 	}
 }
 
-// OpenFile opens a GGUF file.
-func OpenFile(filename string) (*Reader, error) {
+// OpenFile opens a GGUF file. The file is kept open until Close() is
+// called.
+func OpenFile(filename string, opts ...Option) (*Reader, error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
 
-	return Open(f)
+	r, err := Open(f, opts...)
+	if err != nil {
+		f.Close()
+
+		return nil, err
+	}
+
+	r.closer = f.Close
+
+	return r, nil
+}
+
+// Close releases the resources held by the Reader. For files opened
+// by OpenFile() the file is closed, for files opened by OpenMmap() the
+// file is unmapped, and any slices returned by Bytes() or the view
+// functions must no longer be used. Reading tensor data after Close
+// returns os.ErrClosed. For files opened from a user-supplied reader,
+// Close does nothing.
+func (r *Reader) Close() error {
+	// The Reader is left unchanged, as tensor data may be read
+	// concurrently. Reads fail through the closed file or mapping.
+	r.closeOnce.Do(func() {
+		if r.closer != nil {
+			r.closeErr = r.closer()
+		}
+	})
+
+	return r.closeErr
+}
+
+// OpenReaderAt opens a GGUF file of the given size from r. Tensor
+// data is read using r.ReadAt(), so tensors can be read concurrently.
+func OpenReaderAt(r io.ReaderAt, size int64, opts ...Option) (*Reader, error) {
+	return Open(io.NewSectionReader(r, 0, size), opts...)
 }
 
 // Open opens a GGUF file from r. r must be positoned at the start
-// of the file.
-func Open(readseeker io.ReadSeeker) (*Reader, error) {
-	var buf [4]byte
+// of the file. If r implements io.ReaderAt, it will be used for
+// reading tensor data. Otherwise access to r is serialized. The header
+// is checked against DefaultLimits unless other limits are given by
+// WithLimits().
+func Open(readseeker io.ReadSeeker, opts ...Option) (*Reader, error) {
+	start, err := readseeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
 
-	_, err := readseeker.Read(buf[:])
+	size, err := readseeker.Seek(0, io.SeekEnd)
 	if err != nil {
 		return nil, err
 	}
 
-	if !bytes.Equal(buf[:], []byte(magic)) {
-		return nil, fmt.Errorf("not a GGUF file, unknown magic: %q", buf)
+	_, err = readseeker.Seek(start, io.SeekStart)
+	if err != nil {
+		return nil, err
 	}
 
-	// Jump to the last byte of the version, and check if this
-	// could be a big-endian file.
-	_, err = readseeker.Seek(3, io.SeekCurrent)
+	hr := &headerReader{r: readseeker, n: start}
+
+	var buf [4]byte
+
+	_, err = io.ReadFull(hr, buf[:])
 	if err != nil {
 		return nil, err
 	}
 
-	bigEndianMarker := int8(0)
-	err = binary.Read(readseeker, binary.LittleEndian, &bigEndianMarker)
+	if !bytes.Equal(buf[:], []byte(magic)) {
+		return nil, fmt.Errorf("not a GGUF file, unknown magic: %q", buf)
+	}
+
+	_, err = io.ReadFull(hr, buf[:])
 	if err != nil {
 		return nil, err
 	}
 
+	// If the last byte of the version is set, this could be a
+	// big-endian file.
 	var byteOrder binary.ByteOrder = binary.LittleEndian
 
-	if bigEndianMarker != 0 {
+	if buf[3] != 0 {
 		byteOrder = binary.BigEndian
 	}
 
-	// Jump back to read the version in file byteorder.
-	_, err = readseeker.Seek(-4, io.SeekCurrent)
-	if err != nil {
-		return nil, err
-	}
+	version := byteOrder.Uint32(buf[:])
 
-	version, err := read[uint32](readseeker, byteOrder)
-	if err != nil {
-		return nil, err
+	ra, ok := readseeker.(io.ReaderAt)
+	if !ok {
+		ra = &seekerReaderAt{rs: readseeker}
 	}
 
 	r := &Reader{
-		r:         readseeker,
+		r:         hr,
+		options:   newOptions(opts),
+		ra:        ra,
+		size:      size,
 		ByteOrder: byteOrder,
 		Version:   int(version),
 	}
 
+	limits := r.options.limits
+
 	switch version {
 	case 1:
 		r.readUint = readCast[uint32, uint64]
@@ -304,29 +552,62 @@ func Open(readseeker io.ReadSeeker) (*Reader, error) {
 		return nil, fmt.Errorf("invalid version: %d", version)
 	}
 
-	tensorCount, err := r.readUint(readseeker, r.ByteOrder)
+	tensorCount, err := r.readUint(r.r, r.ByteOrder)
 	if err != nil {
 		return nil, err
 	}
 
-	metadataCount, err := r.readUint(readseeker, r.ByteOrder)
+	err = checkLimit("MaxTensorCount", tensorCount, limits.MaxTensorCount)
+	if err != nil {
+		return nil, err
+	}
+
+	metadataCount, err := r.readUint(r.r, r.ByteOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkLimit("MaxMetadataCount", metadataCount, limits.MaxMetadataCount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every metadata entry is at least a key length and a type.
+	err = r.need(metadataCount, r.uintSize()+4)
 	if err != nil {
 		return nil, err
 	}
 
 	r.Metadata = make(map[string]interface{})
+	r.Entries = make([]MetaEntry, 0)
 
 	for i := uint64(0); i < metadataCount; i++ {
-		name, err := r.readString()
+		var entry MetaEntry
+
+		invalid := len(r.invalidStrings)
+
+		entry.Key, err = r.readString()
 		if err != nil {
 			return nil, err
 		}
 
-		value, err := r.readMetaValue()
+		err = r.readMetaValue(&entry)
 		if err != nil {
 			return nil, err
 		}
 
+		for j := invalid; j < len(r.invalidStrings); j++ {
+			r.invalidStrings[j].key = entry.Key
+		}
+
+		r.Entries = append(r.Entries, entry)
+
+		name, value := entry.Key, entry.Value
+
+		if r.options.trimStrings {
+			name, value = trimString(name), trimValue(value)
+		}
+
 		if u, ok := value.(uint32); ok && name == "general.file_type" {
 			value = Filetype(u)
 		}
@@ -339,6 +620,10 @@ func Open(readseeker io.ReadSeeker) (*Reader, error) {
 	if a, found := r.Metadata["general.alignment"]; found {
 		switch v := a.(type) {
 		case uint32:
+			if v == 0 {
+				return nil, fmt.Errorf("invalid alignment: %d", v)
+			}
+
 			alignment = int64(v)
 
 		default:
@@ -346,49 +631,74 @@ func Open(readseeker io.ReadSeeker) (*Reader, error) {
 		}
 	}
 
+	// Every tensor info is at least a name length, the number of
+	// dimensions, a type and an offset.
+	err = r.need(tensorCount, 2*r.uintSize()+8)
+	if err != nil {
+		return nil, err
+	}
+
 	r.Tensors = make([]TensorInfo, tensorCount)
 
 	for i := uint64(0); i < tensorCount; i++ {
 		r.Tensors[i].g = r
 
-		r.Tensors[i].Name, err = r.readString()
+		invalid := len(r.invalidStrings)
+
+		r.Tensors[i].rawName, err = r.readString()
 		if err != nil {
 			return nil, err
 		}
 
-		nDimensions, err := read[uint32](readseeker, r.ByteOrder)
+		if invalid < len(r.invalidStrings) {
+			r.invalidStrings[invalid].tensor = r.Tensors[i].rawName
+		}
+
+		r.Tensors[i].Name = r.Tensors[i].rawName
+
+		if r.options.trimStrings {
+			r.Tensors[i].Name = trimString(r.Tensors[i].rawName)
+		}
+
+		nDimensions, err := read[uint32](r.r, r.ByteOrder)
 		if err != nil {
 			return nil, err
 		}
 
+		err = checkLimit("MaxDimensions", uint64(nDimensions), limits.MaxDimensions)
+		if err != nil {
+			return nil, fmt.Errorf("tensor %q: %w", r.Tensors[i].Name, err)
+		}
+
 		r.Tensors[i].Dimensions = make([]uint64, nDimensions)
 
 		for j := uint32(0); j < nDimensions; j++ {
-			r.Tensors[i].Dimensions[j], err = r.readUint(readseeker, r.ByteOrder)
+			r.Tensors[i].Dimensions[j], err = r.readUint(r.r, r.ByteOrder)
 			if err != nil {
 				return nil, err
 			}
 		}
 
-		typ, err := read[uint32](readseeker, r.ByteOrder)
+		typ, err := read[uint32](r.r, r.ByteOrder)
 		if err != nil {
 			return nil, err
 		}
 
 		r.Tensors[i].Type = GGML(typ)
 
-		r.Tensors[i].Offset, err = r.readUint(readseeker, r.ByteOrder)
+		r.Tensors[i].Offset, err = r.readUint(r.r, r.ByteOrder)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	current, err := readseeker.Seek(0, io.SeekCurrent)
+	err = checkLimit("MaxHeaderSize", uint64(r.r.n), limits.MaxHeaderSize)
 	if err != nil {
 		return nil, err
 	}
 
-	r.tensorOffset = (current + alignment - 1) / alignment * alignment
+	r.alignment = alignment
+	r.tensorOffset = (r.r.n + alignment - 1) / alignment * alignment
 
 	return r, nil
 }
@@ -417,3 +727,20 @@ func (r *Reader) TensorSize() int64 {
 
 	return size
 }
+
+// TensorSizeErr is like TensorSize, but returns an error instead of
+// panicking if the size of a tensor cannot be determined.
+func (r *Reader) TensorSizeErr() (int64, error) {
+	size := int64(0)
+
+	for i := range r.Tensors {
+		s, err := r.Tensors[i].SizeErr()
+		if err != nil {
+			return 0, err
+		}
+
+		size += s
+	}
+
+	return size, nil
+}
@@ -0,0 +1,138 @@
+package gguf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stringsWriter returns a writer with strings that would be altered by
+// trimming, and strings that are not valid UTF-8.
+func stringsWriter(t *testing.T) *Writer {
+	t.Helper()
+
+	w := NewWriter(binary.LittleEndian)
+
+	err := w.AddMetadata("general.architecture", String, "llama")
+	if err == nil {
+		err = w.AddMetadata(" padded.key\x00", String, "\x00 value\t\n")
+	}
+
+	if err == nil {
+		err = w.AddArray("tokenizer.ggml.tokens", String, []string{" the", "\n", "\x00", "  ", "tok\xff\xfeen"})
+	}
+
+	if err == nil {
+		err = w.AddMetadata("test.invalid", String, "\xc3")
+	}
+
+	if err == nil {
+		err = w.AddArray("test.nested", Array, []MetaArray{{Type: String, Value: []string{" a ", "b"}}})
+	}
+
+	if err == nil {
+		err = w.AddTensor(" a.weight\n", []uint64{1}, GgmlFloat32, bytes.NewReader(make([]byte, 4)))
+	}
+
+	if err == nil {
+		err = w.AddTensor("b.weight\x80", []uint64{1}, GgmlFloat32, bytes.NewReader(make([]byte, 4)))
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return w
+}
+
+func TestStringsPreserved(t *testing.T) {
+	r, data := encode(t, stringsWriter(t))
+
+	expected := Metadata{
+		"general.architecture":  "llama",
+		" padded.key\x00":       "\x00 value\t\n",
+		"tokenizer.ggml.tokens": []string{" the", "\n", "\x00", "  ", "tok\xff\xfeen"},
+		"test.invalid":          "\xc3",
+		"test.nested":           []MetaArray{{Type: String, Value: []string{" a ", "b"}}},
+	}
+
+	if !reflect.DeepEqual(r.Metadata, expected) {
+		t.Errorf("expected metadata %q, got %q", expected, r.Metadata)
+	}
+
+	for i, name := range []string{" a.weight\n", "b.weight\x80"} {
+		if r.Tensors[i].Name != name {
+			t.Errorf("tensor %d: expected name %q, got %q", i, name, r.Tensors[i].Name)
+		}
+	}
+
+	// offset returns the offset of the first invalid byte in s, which
+	// is at index i.
+	offset := func(s string, i int) int64 {
+		return int64(bytes.Index(data, []byte(s)) + i)
+	}
+
+	expectedInvalid := []invalidString{
+		{key: "tokenizer.ggml.tokens", offset: offset("tok\xff", 3)},
+		{key: "test.invalid", offset: offset("\xc3", 0)},
+		{tensor: "b.weight\x80", offset: offset("b.weight\x80", 8)},
+	}
+
+	if !reflect.DeepEqual(r.invalidStrings, expectedInvalid) {
+		t.Errorf("expected invalid strings %+v, got %+v", expectedInvalid, r.invalidStrings)
+	}
+
+	findings := r.Verify()
+
+	if len(findings) != len(expectedInvalid) {
+		t.Fatalf("expected %d findings, got %v", len(expectedInvalid), findings)
+	}
+
+	for i, f := range findings {
+		if f.Severity != SeverityWarning || !strings.HasPrefix(f.Message, "invalid UTF-8 at offset") {
+			t.Errorf("unexpected finding %s", f)
+		}
+
+		if f.Key != expectedInvalid[i].key || f.Tensor != expectedInvalid[i].tensor {
+			t.Errorf("finding %d: expected key %q and tensor %q, got %s", i, expectedInvalid[i].key, expectedInvalid[i].tensor, f)
+		}
+	}
+}
+
+func TestTrimmedStrings(t *testing.T) {
+	r, data := encode(t, stringsWriter(t), WithTrimmedStrings())
+
+	expected := Metadata{
+		"general.architecture":  "llama",
+		"padded.key":            "value",
+		"tokenizer.ggml.tokens": []string{"the", "", "", "", "tok\xff\xfeen"},
+		"test.invalid":          "\xc3",
+		"test.nested":           []MetaArray{{Type: String, Value: []string{"a", "b"}}},
+	}
+
+	if !reflect.DeepEqual(r.Metadata, expected) {
+		t.Errorf("expected metadata %q, got %q", expected, r.Metadata)
+	}
+
+	if r.Tensors[0].Name != "a.weight" {
+		t.Errorf("expected a trimmed tensor name, got %q", r.Tensors[0].Name)
+	}
+
+	// Entries and the header are kept exactly as found in the file.
+	if r.Entries[1].Key != " padded.key\x00" || r.Entries[1].Value != "\x00 value\t\n" {
+		t.Errorf("entry was trimmed: %q", r.Entries[1])
+	}
+
+	var b bytes.Buffer
+
+	_, err := NewWriterFrom(r).WriteTo(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(b.Bytes(), data) {
+		t.Error("rewritten file differs from the original")
+	}
+}
@@ -0,0 +1,256 @@
+package gguf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// RequantizePolicy decides the target type of every tensor when
+// requantizing a file.
+type RequantizePolicy struct {
+	// Type is the target type for weight tensors with two or more
+	// dimensions.
+	Type GGML
+
+	// Overrides are checked in order before anything else. The type
+	// of the first override matching the tensor name is used.
+	Overrides []RequantizeOverride
+
+	// KeepNormsF32 stores one-dimensional float and quantized tensors,
+	// like norms and biases, as F32. If not set, they are copied
+	// unchanged. Integer tensors are always copied unchanged.
+	KeepNormsF32 bool
+
+	// Imatrix is the importance matrix used to weigh the quantization.
+	// It's optional.
+	Imatrix *Imatrix
+
+	// Filetype is stored as general.file_type. If nil, it's derived
+	// from Type.
+	Filetype *Filetype
+}
+
+// RequantizeOverride sets the target type for tensors with names
+// matching Pattern.
+type RequantizeOverride struct {
+	Pattern *regexp.Regexp
+	Type    GGML
+}
+
+// requantizeFallbacks is the type used when the rows of a tensor are
+// not a whole number of blocks of the target type.
+var requantizeFallbacks = map[GGML]GGML{
+	GgmlQ2_K: GgmlQ4_0,
+	GgmlQ3_K: GgmlQ4_0,
+	GgmlQ4_K: GgmlQ5_0,
+	GgmlQ5_K: GgmlQ5_1,
+	GgmlQ6_K: GgmlQ8_0,
+}
+
+// ggmlFiletypes maps a target type to the file type of a file where
+// all weights are of that type.
+var ggmlFiletypes = map[GGML]Filetype{
+	GgmlFloat32:  AllF32,
+	GgmlFloat16:  MostlyF16,
+	GgmlBFloat16: MostlyBF16,
+	GgmlQ4_0:     MostlyQ4_0,
+	GgmlQ4_1:     MostlyQ4_1,
+	GgmlQ5_0:     MostlyQ5_0,
+	GgmlQ5_1:     MostlyQ5_1,
+	GgmlQ8_0:     MostlyQ8_0,
+	GgmlQ2_K:     MostlyQ2_K,
+	GgmlQ3_K:     MostlyQ3_KS,
+	GgmlQ4_K:     MostlyQ4_KS,
+	GgmlQ5_K:     MostlyQ5_KS,
+	GgmlQ6_K:     MostlyQ6_K,
+}
+
+// isInteger returns true for the integer types, which hold values like
+// token ids and positions rather than weights.
+func isInteger(typ GGML) bool {
+	switch typ {
+	case GgmlInt8, GgmlInt16, GgmlInt32, GgmlInt64:
+		return true
+	}
+
+	return false
+}
+
+// TargetType returns the type t should be stored as.
+func (p *RequantizePolicy) TargetType(t *TensorInfo) GGML {
+	for _, o := range p.Overrides {
+		if o.Pattern.MatchString(t.Name) {
+			return o.Type
+		}
+	}
+
+	if len(t.Dimensions) <= 1 {
+		if p.KeepNormsF32 && !isInteger(t.Type) {
+			return GgmlFloat32
+		}
+
+		return t.Type
+	}
+
+	if !strings.HasSuffix(t.Name, "weight") {
+		return t.Type
+	}
+
+	typ := p.Type
+
+	for len(t.Dimensions) > 0 {
+		s, found := sizes[typ]
+		if !found || t.Dimensions[0]%s.valuesinblock == 0 {
+			break
+		}
+
+		fallback, found := requantizeFallbacks[typ]
+		if !found {
+			return GgmlFloat16
+		}
+
+		typ = fallback
+	}
+
+	return typ
+}
+
+// Requantize writes a copy of the file read by r to w, with every
+// tensor converted to the type decided by policy. Tensors are
+// converted one at a time while writing. Tensors already of the target
+// type are copied unchanged.
+func Requantize(r *Reader, w io.Writer, policy RequantizePolicy) error {
+	out := NewWriter(r.ByteOrder)
+
+	for _, e := range r.Entries {
+		if e.Key == "general.file_type" || e.Key == "general.quantization_version" {
+			continue
+		}
+
+		out.setEntry(e)
+	}
+
+	quantized := false
+
+	for i := range r.Tensors {
+		t := &r.Tensors[i]
+		target := policy.TargetType(t)
+
+		if _, found := sizes[target]; found && sizes[target].valuesinblock > 1 {
+			quantized = true
+		}
+
+		var data io.Reader
+
+		if target == t.Type {
+			data = &lazyTensorReader{t: t}
+		} else {
+			if _, found := dequantizers[t.Type]; !found {
+				return fmt.Errorf("tensor %q: cannot convert from %s", t.Name, t.Type)
+			}
+
+			if _, found := quantizers[target]; !found {
+				return fmt.Errorf("tensor %q: cannot convert to %s", t.Name, target)
+			}
+
+			data = &convertingReader{t: t, target: target, imatrix: policy.Imatrix}
+		}
+
+		err := out.AddTensor(t.rawName, t.Dimensions, target, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	ftype, found := ggmlFiletypes[policy.Type]
+	if policy.Filetype != nil {
+		ftype, found = *policy.Filetype, true
+	}
+
+	if found {
+		out.setEntry(MetaEntry{Key: "general.file_type", Type: Uint32, Value: uint32(ftype)})
+	}
+
+	if quantized {
+		out.setEntry(MetaEntry{Key: "general.quantization_version", Type: Uint32, Value: uint32(2)})
+	}
+
+	_, err := out.WriteTo(w)
+
+	return err
+}
+
+// convertingReader converts a tensor to another type on the first call
+// to Read(), and returns the result.
+type convertingReader struct {
+	t       *TensorInfo
+	target  GGML
+	imatrix *Imatrix
+
+	r io.Reader
+}
+
+// Read implements io.Reader.
+func (c *convertingReader) Read(p []byte) (int, error) {
+	if c.r == nil {
+		data, err := c.convert()
+		if err != nil {
+			return 0, fmt.Errorf("tensor %q: %w", c.t.Name, err)
+		}
+
+		c.r = bytes.NewReader(data)
+	}
+
+	n, err := c.r.Read(p)
+	if err == io.EOF {
+		// Release the converted data as soon as it's consumed.
+		c.r = bytes.NewReader(nil)
+	}
+
+	return n, err
+}
+
+// convert dequantizes the tensor and quantizes it as the target type.
+func (c *convertingReader) convert() ([]byte, error) {
+	values, err := c.t.Dequantize()
+	if err != nil {
+		return nil, err
+	}
+
+	byteOrder := c.t.g.ByteOrder
+
+	if c.imatrix == nil {
+		return Quantize(c.target, values, byteOrder)
+	}
+
+	if _, found := c.imatrix.Importance[c.t.Name]; !found {
+		return Quantize(c.target, values, byteOrder)
+	}
+
+	importance, err := c.imatrix.For(c.t)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every matrix, like the experts of a MoE tensor, has its own
+	// importance vector.
+	rowLength := int(c.t.Dimensions[0])
+	matrices := len(importance) / rowLength
+	matrixSize := len(values) / matrices
+
+	var data []byte
+
+	for m := 0; m < matrices; m++ {
+		q, err := QuantizeWeighted(c.target, values[m*matrixSize:(m+1)*matrixSize], importance[m*rowLength:(m+1)*rowLength], byteOrder)
+		if err != nil {
+			return nil, err
+		}
+
+		data = append(data, q...)
+	}
+
+	return data, nil
+}
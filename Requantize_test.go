@@ -0,0 +1,29 @@
+package gguf
+
+import (
+	"testing"
+)
+
+func TestTargetTypeKeepNormsF32(t *testing.T) {
+	policy := RequantizePolicy{Type: GgmlQ4_0, KeepNormsF32: true}
+
+	cases := map[GGML]GGML{
+		GgmlFloat32:  GgmlFloat32,
+		GgmlFloat16:  GgmlFloat32,
+		GgmlBFloat16: GgmlFloat32,
+		GgmlQ8_0:     GgmlFloat32,
+		GgmlInt8:     GgmlInt8,
+		GgmlInt16:    GgmlInt16,
+		GgmlInt32:    GgmlInt32,
+		GgmlInt64:    GgmlInt64,
+	}
+
+	for typ, want := range cases {
+		tensor := &TensorInfo{Name: "blk.0.attn_norm.weight", Dimensions: []uint64{64}, Type: typ}
+
+		got := policy.TargetType(tensor)
+		if got != want {
+			t.Errorf("%s: got %s, want %s", typ, got, want)
+		}
+	}
+}
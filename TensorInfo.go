@@ -1,16 +1,28 @@
 package gguf
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"math"
+	"math/bits"
 )
 
+// ErrUnknownType is returned when the size of a tensor of an unknown
+// type cannot be determined.
+var ErrUnknownType = errors.New("unknown tensor type")
+
 // TensorInfo is used to represent a tensor in a GGUF file.
 type TensorInfo struct {
 	g *Reader
 
-	// The name of the tensor.
+	// The name of the tensor, exactly as found in the file unless
+	// WithTrimmedStrings() is used.
 	Name string
 
+	// rawName is the name exactly as found in the file.
+	rawName string
+
 	Dimensions []uint64
 
 	Type GGML
@@ -19,38 +31,110 @@ type TensorInfo struct {
 }
 
 // Reader returns an io.Reader that can be used to read the tensor
-// data. The reader is positioned at the start of the tensor data.
-// The caller of this function is responsible for calculating how
-// much data to read.
+// data. The reader is positioned at the start of the tensor data and
+// returns io.EOF after Size() bytes.
 func (t *TensorInfo) Reader() (io.Reader, error) {
-	// FIXME: Use io.NewSectionReader.
-	_, err := t.g.r.Seek(t.g.tensorOffset, io.SeekStart)
+	return t.SectionReader()
+}
+
+// SectionReader returns an io.SectionReader covering exactly the
+// tensor data. Every call returns an independent reader, and they
+// can be used concurrently from multiple goroutines.
+func (t *TensorInfo) SectionReader() (*io.SectionReader, error) {
+	if t.g == nil {
+		return nil, fmt.Errorf("tensor %q is not part of a file", t.Name)
+	}
+
+	start, size, err := t.dataRange()
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = t.g.r.Seek(int64(t.Offset), io.SeekCurrent)
+	return io.NewSectionReader(t.g.ra, start, size), nil
+}
+
+// dataRange returns the position in the file and the size of the
+// tensor data. An error wrapping ErrTruncated is returned if the data
+// is not within the file.
+func (t *TensorInfo) dataRange() (int64, int64, error) {
+	size, err := t.SizeErr()
 	if err != nil {
-		return nil, err
+		return 0, 0, err
+	}
+
+	start := uint64(t.g.tensorOffset) + t.Offset
+	end := start + uint64(size)
+
+	if start < t.Offset || end < start || end > uint64(t.g.size) {
+		return 0, 0, fmt.Errorf("tensor %q: %w: %d bytes at offset %d exceeds the file size of %d bytes", t.Name, ErrTruncated, size, t.Offset, t.g.size)
 	}
 
-	return t.g.r, nil
+	return int64(start), size, nil
 }
 
 // Size returns the size of the tensor data in bytes. This can be
 // useful in comfination with TensorSize() on the Reader if you
-// would like to show a progress bar.
+// would like to show a progress bar. Size panics if the size cannot
+// be determined, use SizeErr() to get an error instead.
 func (t *TensorInfo) Size() int64 {
+	size, err := t.SizeErr()
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return size
+}
+
+// SizeErr returns the size of the tensor data in bytes. For types
+// unknown to this package, the size is inferred from the offset of the
+// following tensor or the end of the file. An inferred size includes
+// any padding after the tensor data. If the size cannot be determined,
+// an error wrapping ErrUnknownType is returned.
+func (t *TensorInfo) SizeErr() (int64, error) {
 	s, found := sizes[t.Type]
 	if !found {
-		panic("unknown type: " + t.Type.String())
+		return t.inferSize()
 	}
 
 	values := uint64(1)
 
 	for _, d := range t.Dimensions {
-		values *= d
+		hi, lo := bits.Mul64(values, d)
+		if hi != 0 {
+			return 0, fmt.Errorf("tensor %q: size of %v overflows", t.Name, t.Dimensions)
+		}
+
+		values = lo
+	}
+
+	hi, size := bits.Mul64(values/s.valuesinblock, s.blocksize)
+	if hi != 0 || size > math.MaxInt64 {
+		return 0, fmt.Errorf("tensor %q: size of %v overflows", t.Name, t.Dimensions)
+	}
+
+	return int64(size), nil
+}
+
+// inferSize infers the size of the tensor data from the offset of the
+// following tensor, or the end of the file for the last tensor.
+func (t *TensorInfo) inferSize() (int64, error) {
+	if t.g == nil || t.g.size < t.g.tensorOffset {
+		return 0, fmt.Errorf("tensor %q: %w: %s", t.Name, ErrUnknownType, t.Type)
+	}
+
+	end := uint64(t.g.size - t.g.tensorOffset)
+
+	for i := range t.g.Tensors {
+		o := t.g.Tensors[i].Offset
+
+		if o > t.Offset && o < end {
+			end = o
+		}
+	}
+
+	if t.Offset > end {
+		return 0, fmt.Errorf("tensor %q: %w: %s, and the offset is beyond the end of the file", t.Name, ErrUnknownType, t.Type)
 	}
 
-	return int64((values / s.valuesinblock) * s.blocksize)
+	return int64(end - t.Offset), nil
 }
@@ -0,0 +1,92 @@
+package gguf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestSizeErrOverflow(t *testing.T) {
+	cases := []struct {
+		dimensions []uint64
+		typ        GGML
+	}{
+		{[]uint64{1 << 32, 1 << 32, 2}, GgmlFloat32},
+		{[]uint64{1 << 62}, GgmlFloat32},
+		{[]uint64{1 << 61}, GgmlFloat32},
+		{[]uint64{1 << 40, 1 << 30}, GgmlQ4_0},
+	}
+
+	for _, c := range cases {
+		tensor := &TensorInfo{Name: "a", Dimensions: c.dimensions, Type: c.typ}
+
+		size, err := tensor.SizeErr()
+		if err == nil {
+			t.Errorf("%v %s: expected an error, got a size of %d", c.dimensions, c.typ, size)
+		}
+	}
+}
+
+func TestDataOutsideFile(t *testing.T) {
+	offsets := []uint64{1 << 62, 1<<64 - 8, 1 << 20}
+
+	for _, offset := range offsets {
+		r, _ := encode(t, testWriter(t, binary.LittleEndian))
+
+		tensor := &r.Tensors[1]
+		tensor.Offset = offset
+
+		_, err := tensor.SectionReader()
+		if !errors.Is(err, ErrTruncated) {
+			t.Errorf("offset %d: SectionReader: expected ErrTruncated, got %v", offset, err)
+		}
+
+		_, err = tensor.Dequantize()
+		if !errors.Is(err, ErrTruncated) {
+			t.Errorf("offset %d: Dequantize: expected ErrTruncated, got %v", offset, err)
+		}
+	}
+}
+
+func TestSizeWideTypes(t *testing.T) {
+	cases := []struct {
+		typ  GGML
+		size int64
+	}{
+		{GgmlBFloat16, 2 * 3 * 5},
+		{GgmlFloat64, 8 * 3 * 5},
+		{GgmlInt64, 8 * 3 * 5},
+	}
+
+	w := NewWriter(binary.LittleEndian)
+
+	total := int64(0)
+
+	for _, c := range cases {
+		err := w.AddTensor(c.typ.String(), []uint64{3, 5}, c.typ, bytes.NewReader(make([]byte, c.size)))
+		if err != nil {
+			t.Fatalf("%s: %s", c.typ, err)
+		}
+
+		total += c.size
+	}
+
+	r, _ := encode(t, w)
+
+	for i, c := range cases {
+		size, err := r.Tensors[i].SizeErr()
+		if err != nil || size != c.size {
+			t.Errorf("%s: expected a size of %d, got %d, %v", c.typ, c.size, size, err)
+		}
+	}
+
+	if r.TensorSize() != total {
+		t.Errorf("expected a tensor size of %d, got %d", total, r.TensorSize())
+	}
+
+	size, err := r.TensorSizeErr()
+	if err != nil || size != total {
+		t.Errorf("TensorSizeErr: expected %d, got %d, %v", total, size, err)
+	}
+}
@@ -72,3 +72,15 @@ func (t Type) String() string {
 		return fmt.Sprintf("unknown-type-%d", t)
 	}
 }
+
+// parseType returns the scalar type with the given name, as returned
+// by String().
+func parseType(name string) (Type, error) {
+	for t := Uint8; t <= Float64; t++ {
+		if t != Array && t.String() == name {
+			return t, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown scalar type: %q", name)
+}
@@ -0,0 +1,236 @@
+package gguf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity is the severity of a Finding.
+type Severity int
+
+const (
+	// SeverityWarning is used for problems that most readers will
+	// tolerate, like an inconsistent file type.
+	SeverityWarning Severity = iota
+
+	// SeverityError is used for problems that make the file, or
+	// parts of it, unusable.
+	SeverityError
+)
+
+// String returns the string representation of the severity.
+// Implements fmt.Stringer.
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+
+	case SeverityError:
+		return "error"
+
+	default:
+		return fmt.Sprintf("Severity(%d)", s)
+	}
+}
+
+// maxDimensions is the maximum number of dimensions supported by ggml.
+const maxDimensions = 4
+
+// Finding is a single problem found by Verify().
+type Finding struct {
+	Severity Severity
+
+	// Tensor is the name of the tensor the finding is about, if any.
+	Tensor string
+
+	// Key is the metadata key the finding is about, if any.
+	Key string
+
+	// Message describes the problem.
+	Message string
+}
+
+// String returns a human readable representation of the finding.
+// Implements fmt.Stringer.
+func (f Finding) String() string {
+	switch {
+	case f.Tensor != "":
+		return fmt.Sprintf("%s: tensor %q: %s", f.Severity, f.Tensor, f.Message)
+
+	case f.Key != "":
+		return fmt.Sprintf("%s: key %q: %s", f.Severity, f.Key, f.Message)
+
+	default:
+		return fmt.Sprintf("%s: %s", f.Severity, f.Message)
+	}
+}
+
+// HasErrors returns true if any of the findings is an error.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Verify checks the structure of the file and returns every problem
+// found. The tensor data itself is not read. An empty result means
+// that no problems were found.
+func (r *Reader) Verify() []Finding {
+	var findings []Finding
+
+	add := func(severity Severity, tensor string, key string, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			Severity: severity,
+			Tensor:   tensor,
+			Key:      key,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	keys := make(map[string]bool)
+
+	for _, e := range r.Entries {
+		if keys[e.Key] {
+			add(SeverityError, "", e.Key, "duplicate metadata key")
+		}
+
+		keys[e.Key] = true
+	}
+
+	// The specification requires UTF-8, but some files in the wild
+	// have tokens with partial characters, so it's only a warning.
+	for _, s := range r.invalidStrings {
+		add(SeverityWarning, s.tensor, s.key, "invalid UTF-8 at offset %d", s.offset)
+	}
+
+	if r.alignment&(r.alignment-1) != 0 {
+		add(SeverityError, "", "general.alignment", "alignment %d is not a power of two", r.alignment)
+	}
+
+	names := make(map[string]bool)
+
+	// ranges holds the data ranges of all tensors with a known size.
+	type dataRange struct {
+		name       string
+		start, end int64
+	}
+
+	var ranges []dataRange
+
+	for i := range r.Tensors {
+		t := &r.Tensors[i]
+
+		if names[t.Name] {
+			add(SeverityError, t.Name, "", "duplicate tensor name")
+		}
+
+		names[t.Name] = true
+
+		if len(t.Dimensions) == 0 {
+			add(SeverityError, t.Name, "", "no dimensions")
+		}
+
+		if len(t.Dimensions) > maxDimensions {
+			add(SeverityError, t.Name, "", "%d dimensions, at most %d are supported", len(t.Dimensions), maxDimensions)
+		}
+
+		for j, d := range t.Dimensions {
+			if d == 0 {
+				add(SeverityError, t.Name, "", "dimension %d is zero", j)
+			}
+		}
+
+		s, known := sizes[t.Type]
+		if !known {
+			add(SeverityWarning, t.Name, "", "unknown type %s", t.Type)
+		}
+
+		if known && len(t.Dimensions) > 0 && t.Dimensions[0]%s.valuesinblock != 0 {
+			add(SeverityError, t.Name, "", "row length %d is not a multiple of the %s block size %d", t.Dimensions[0], t.Type, s.valuesinblock)
+		}
+
+		if t.Offset%uint64(r.alignment) != 0 {
+			add(SeverityError, t.Name, "", "offset %d is not aligned to %d", t.Offset, r.alignment)
+		}
+
+		start, size, err := t.dataRange()
+		if err != nil {
+			// The tensor name is already part of the finding.
+			add(SeverityError, t.Name, "", "%s", strings.TrimPrefix(err.Error(), fmt.Sprintf("tensor %q: ", t.Name)))
+
+			continue
+		}
+
+		ranges = append(ranges, dataRange{name: t.Name, start: start, end: start + size})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].start < ranges[j].start
+	})
+
+	// Compare against the range reaching furthest so far, not just the
+	// previous one, as a large range can cover several later ones.
+	for i, last := 1, 0; i < len(ranges); i++ {
+		cur := ranges[i]
+
+		if cur.start < ranges[last].end {
+			add(SeverityError, cur.name, "", "data overlaps tensor %q", ranges[last].name)
+		}
+
+		if cur.end > ranges[last].end {
+			last = i
+		}
+	}
+
+	findings = append(findings, r.verifyFiletype()...)
+
+	return findings
+}
+
+// verifyFiletype checks that general.file_type matches the types of
+// the tensors.
+func (r *Reader) verifyFiletype() []Finding {
+	const key = "general.file_type"
+
+	value, found := r.Metadata[key]
+	if !found {
+		return nil
+	}
+
+	ftype, ok := value.(Filetype)
+	if !ok {
+		return []Finding{{Severity: SeverityWarning, Key: key, Message: fmt.Sprintf("value is %T, expected uint32", value)}}
+	}
+
+	expected, found := filetypeGGML[ftype]
+	if !found {
+		return []Finding{{Severity: SeverityWarning, Key: key, Message: fmt.Sprintf("unknown or unsupported file type %d", uint32(ftype))}}
+	}
+
+	counts := make(map[GGML]int)
+
+	for _, t := range r.Tensors {
+		counts[t.Type]++
+	}
+
+	if len(r.Tensors) > 0 && counts[expected] == 0 {
+		return []Finding{{Severity: SeverityWarning, Key: key, Message: fmt.Sprintf("file type is %q, but no tensors are %s", ftype, expected)}}
+	}
+
+	if ftype == AllF32 && counts[expected] != len(r.Tensors) {
+		return []Finding{{Severity: SeverityWarning, Key: key, Message: fmt.Sprintf("file type is %q, but %d tensors are not %s", ftype, len(r.Tensors)-counts[expected], expected)}}
+	}
+
+	// Q4_1 with some F16 is never inferred, it looks like Q4_1.
+	inferred, err := r.InferFiletype()
+	if err == nil && inferred != ftype && ftype != MostlyQ4_1SomeF16 {
+		return []Finding{{Severity: SeverityWarning, Key: key, Message: fmt.Sprintf("file type is %q, but the tensors suggest %q", ftype, inferred)}}
+	}
+
+	return nil
+}
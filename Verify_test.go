@@ -0,0 +1,221 @@
+package gguf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// rawTensor describes a tensor written as-is by rawFile().
+type rawTensor struct {
+	name       string
+	dimensions []uint64
+	typ        GGML
+	offset     uint64
+}
+
+// rawKV is a metadata entry written as-is by rawFile(). Only strings
+// and uint32 values are supported.
+type rawKV struct {
+	key   string
+	value interface{}
+}
+
+// rawFile builds a little endian v3 file without any of the checks
+// done by Writer, followed by dataSize bytes of tensor data.
+func rawFile(kvs []rawKV, tensors []rawTensor, dataSize int) []byte {
+	var b bytes.Buffer
+
+	le := binary.LittleEndian
+
+	writeString := func(s string) {
+		_ = binary.Write(&b, le, uint64(len(s)))
+		b.WriteString(s)
+	}
+
+	b.WriteString("GGUF")
+	_ = binary.Write(&b, le, uint32(3))
+	_ = binary.Write(&b, le, uint64(len(tensors)))
+	_ = binary.Write(&b, le, uint64(len(kvs)))
+
+	for _, kv := range kvs {
+		writeString(kv.key)
+
+		switch v := kv.value.(type) {
+		case string:
+			_ = binary.Write(&b, le, String)
+			writeString(v)
+
+		case uint32:
+			_ = binary.Write(&b, le, Uint32)
+			_ = binary.Write(&b, le, v)
+		}
+	}
+
+	for _, t := range tensors {
+		writeString(t.name)
+		_ = binary.Write(&b, le, uint32(len(t.dimensions)))
+		_ = binary.Write(&b, le, t.dimensions)
+		_ = binary.Write(&b, le, uint32(t.typ))
+		_ = binary.Write(&b, le, t.offset)
+	}
+
+	for b.Len()%32 != 0 {
+		b.WriteByte(0)
+	}
+
+	b.Write(make([]byte, dataSize))
+
+	return b.Bytes()
+}
+
+func TestVerify(t *testing.T) {
+	arch := rawKV{"general.architecture", "llama"}
+
+	cases := []struct {
+		name     string
+		kvs      []rawKV
+		tensors  []rawTensor
+		dataSize int
+		// expected holds the findings in order. The messages are
+		// prefixes, as some end in the file size.
+		expected []Finding
+	}{
+		{
+			name: "valid",
+			kvs:  []rawKV{arch, {"general.file_type", uint32(AllF32)}},
+			tensors: []rawTensor{
+				{"a.weight", []uint64{8}, GgmlFloat32, 0},
+				{"b.weight", []uint64{8}, GgmlFloat32, 32},
+			},
+			dataSize: 64,
+		},
+		{
+			// B and C are both inside A, C must not be compared
+			// against B only.
+			name: "overlap",
+			kvs:  []rawKV{arch},
+			tensors: []rawTensor{
+				{"a.weight", []uint64{32}, GgmlFloat32, 0},
+				{"b.weight", []uint64{8}, GgmlFloat32, 32},
+				{"c.weight", []uint64{8}, GgmlFloat32, 64},
+				{"d.weight", []uint64{8}, GgmlFloat32, 128},
+			},
+			dataSize: 160,
+			expected: []Finding{
+				{SeverityError, "b.weight", "", `data overlaps tensor "a.weight"`},
+				{SeverityError, "c.weight", "", `data overlaps tensor "a.weight"`},
+			},
+		},
+		{
+			name: "misalignment",
+			kvs:  []rawKV{arch},
+			tensors: []rawTensor{
+				{"a.weight", []uint64{8}, GgmlFloat32, 4},
+			},
+			dataSize: 64,
+			expected: []Finding{
+				{SeverityError, "a.weight", "", "offset 4 is not aligned to 32"},
+			},
+		},
+		{
+			name: "alignment",
+			kvs:  []rawKV{arch, {"general.alignment", uint32(24)}},
+			tensors: []rawTensor{
+				{"a.weight", []uint64{8}, GgmlFloat32, 0},
+			},
+			dataSize: 64,
+			expected: []Finding{
+				{SeverityError, "", "general.alignment", "alignment 24 is not a power of two"},
+			},
+		},
+		{
+			name: "outside file",
+			kvs:  []rawKV{arch},
+			tensors: []rawTensor{
+				{"a.weight", []uint64{8}, GgmlFloat32, 0},
+				{"b.weight", []uint64{8}, GgmlFloat32, 32},
+			},
+			dataSize: 48,
+			expected: []Finding{
+				{SeverityError, "b.weight", "", "truncated file: 32 bytes at offset 32 exceeds the file size of"},
+			},
+		},
+		{
+			name: "unknown type",
+			kvs:  []rawKV{arch},
+			tensors: []rawTensor{
+				{"a.weight", []uint64{8}, GGML(200), 0},
+			},
+			dataSize: 64,
+			expected: []Finding{
+				{SeverityWarning, "a.weight", "", "unknown type GGML(200)"},
+			},
+		},
+		{
+			name: "bad dimensions",
+			kvs:  []rawKV{arch},
+			tensors: []rawTensor{
+				{"a.weight", []uint64{8, 0}, GgmlFloat32, 0},
+				{"b.weight", []uint64{1, 1, 1, 1, 1}, GgmlFloat32, 32},
+				{"c.weight", []uint64{33}, GgmlQ8_0, 64},
+			},
+			dataSize: 128,
+			expected: []Finding{
+				{SeverityError, "a.weight", "", "dimension 1 is zero"},
+				{SeverityError, "b.weight", "", "5 dimensions, at most 4 are supported"},
+				{SeverityError, "c.weight", "", "row length 33 is not a multiple of the q8_0 block size 32"},
+			},
+		},
+		{
+			name: "duplicate names",
+			kvs:  []rawKV{arch, arch},
+			tensors: []rawTensor{
+				{"a.weight", []uint64{8}, GgmlFloat32, 0},
+				{"a.weight", []uint64{8}, GgmlFloat32, 32},
+			},
+			dataSize: 64,
+			expected: []Finding{
+				{SeverityError, "", "general.architecture", "duplicate metadata key"},
+				{SeverityError, "a.weight", "", "duplicate tensor name"},
+			},
+		},
+		{
+			name: "file type mismatch",
+			kvs:  []rawKV{arch, {"general.file_type", uint32(MostlyQ4_0)}},
+			tensors: []rawTensor{
+				{"a.weight", []uint64{8}, GgmlFloat32, 0},
+			},
+			dataSize: 32,
+			expected: []Finding{
+				{SeverityWarning, "", "general.file_type", `file type is "mostly Q4_0", but no tensors are q4_0`},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		data := rawFile(c.kvs, c.tensors, c.dataSize)
+
+		r, err := OpenReaderAt(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+
+		findings := r.Verify()
+
+		if len(findings) != len(c.expected) {
+			t.Errorf("%s: expected %d findings, got %d: %v", c.name, len(c.expected), len(findings), findings)
+
+			continue
+		}
+
+		for i := range findings {
+			f, e := findings[i], c.expected[i]
+
+			if f.Severity != e.Severity || f.Tensor != e.Tensor || f.Key != e.Key || !strings.HasPrefix(f.Message, e.Message) {
+				t.Errorf("%s: finding %d: expected %q, got %q", c.name, i, c.expected[i], findings[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,682 @@
+package gguf
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Writer is a writer for GGUF files. Metadata and tensors are added
+// to the writer, and the complete file is produced by WriteTo().
+type Writer struct {
+	// ByteOrder is the byte order used for the header. Like Reader,
+	// this package does not do any byte swapping for tensor data,
+	// the data must already be in ByteOrder.
+	ByteOrder binary.ByteOrder
+
+	// Version is the GGUF version to write.
+	Version int
+
+	entries []MetaEntry
+	tensors []writerTensor
+}
+
+// writerTensor is a tensor waiting to be written.
+type writerTensor struct {
+	TensorInfo
+
+	data io.Reader
+
+	// keepOffset is set if Offset should be used as-is instead of
+	// being computed.
+	keepOffset bool
+
+	// size is the number of bytes to read from data, or err if the
+	// size could not be determined.
+	size int64
+	err  error
+}
+
+// NewWriter returns a new Writer producing GGUF v3 files in the
+// given byte order.
+func NewWriter(byteOrder binary.ByteOrder) *Writer {
+	return &Writer{
+		ByteOrder: byteOrder,
+		Version:   3,
+	}
+}
+
+// NewWriterFrom returns a Writer set up to reproduce the file read by
+// r. Version, byte order, metadata order and types, and tensor offsets
+// are kept. Tensor data is read from r when the file is written. If
+// nothing is changed, the written file is identical to the original,
+// as long as the space between tensors is zero padding as written by
+// ggml and gguf-py.
+func NewWriterFrom(r *Reader) *Writer {
+	w := &Writer{
+		ByteOrder: r.ByteOrder,
+		Version:   r.Version,
+		entries:   append([]MetaEntry(nil), r.Entries...),
+		tensors:   make([]writerTensor, len(r.Tensors)),
+	}
+
+	for i := range r.Tensors {
+		t := &r.Tensors[i]
+
+		// Tensors of unknown types are copied using their inferred
+		// size. Tensors with data outside the file are rejected here,
+		// before their offset is used to pad the output.
+		_, size, err := t.dataRange()
+
+		w.tensors[i] = writerTensor{
+			TensorInfo: TensorInfo{
+				Name:       t.rawName,
+				Dimensions: append([]uint64(nil), t.Dimensions...),
+				Type:       t.Type,
+				Offset:     t.Offset,
+			},
+			data:       &lazyTensorReader{t: t},
+			keepOffset: true,
+			size:       size,
+			err:        err,
+		}
+	}
+
+	return w
+}
+
+// AddMetadata adds a scalar metadata value. The value must be of a
+// Go type matching typ. Named types such as Filetype are accepted as
+// long as the underlying type matches.
+func (w *Writer) AddMetadata(name string, typ Type, value interface{}) error {
+	if typ == Array {
+		return fmt.Errorf("metadata value %q: use AddArray() for arrays", name)
+	}
+
+	v, err := normalizeScalar(typ, value)
+	if err != nil {
+		return fmt.Errorf("metadata value %q: %w", name, err)
+	}
+
+	return w.addEntry(MetaEntry{Key: name, Type: typ, Value: v})
+}
+
+// AddArray adds an array metadata value. The value must be a slice
+// of a Go type matching elemType.
+func (w *Writer) AddArray(name string, elemType Type, value interface{}) error {
+	v, err := normalizeArray(elemType, value)
+	if err != nil {
+		return fmt.Errorf("metadata value %q: %w", name, err)
+	}
+
+	return w.addEntry(MetaEntry{Key: name, Type: Array, ArrayType: elemType, Value: v})
+}
+
+// SetMetadata sets a scalar metadata value. If the value already
+// exists, it's replaced in place, keeping the order of the metadata.
+// Otherwise it's added at the end.
+func (w *Writer) SetMetadata(name string, typ Type, value interface{}) error {
+	if typ == Array {
+		return fmt.Errorf("metadata value %q: use SetArray() for arrays", name)
+	}
+
+	v, err := normalizeScalar(typ, value)
+	if err != nil {
+		return fmt.Errorf("metadata value %q: %w", name, err)
+	}
+
+	w.setEntry(MetaEntry{Key: name, Type: typ, Value: v})
+
+	return nil
+}
+
+// SetArray sets an array metadata value. If the value already exists,
+// it's replaced in place, keeping the order of the metadata. Otherwise
+// it's added at the end.
+func (w *Writer) SetArray(name string, elemType Type, value interface{}) error {
+	v, err := normalizeArray(elemType, value)
+	if err != nil {
+		return fmt.Errorf("metadata value %q: %w", name, err)
+	}
+
+	w.setEntry(MetaEntry{Key: name, Type: Array, ArrayType: elemType, Value: v})
+
+	return nil
+}
+
+// SetMetadataFrom sets the metadata values of the tagged fields of the
+// struct v, as returned by MarshalMetadata(). Existing values are
+// replaced in place. If v has no general.architecture field, {arch} is
+// replaced by the general.architecture already set in the writer.
+func (w *Writer) SetMetadataFrom(v interface{}) error {
+	arch := ""
+
+	for _, e := range w.entries {
+		if s, ok := e.Value.(string); ok && e.Key == "general.architecture" {
+			arch = s
+		}
+	}
+
+	entries, err := marshalMetadata(v, arch)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		w.setEntry(e)
+	}
+
+	return nil
+}
+
+// RemoveMetadata removes the metadata value with the given name. It's
+// not an error if the value doesn't exist.
+func (w *Writer) RemoveMetadata(name string) {
+	entries := w.entries[:0]
+
+	for _, e := range w.entries {
+		if e.Key != name {
+			entries = append(entries, e)
+		}
+	}
+
+	w.entries = entries
+}
+
+// setEntry replaces or adds an already normalized entry.
+func (w *Writer) setEntry(e MetaEntry) {
+	for i := range w.entries {
+		if w.entries[i].Key == e.Key {
+			w.entries[i] = e
+
+			return
+		}
+	}
+
+	w.entries = append(w.entries, e)
+}
+
+// addEntry adds an already normalized entry.
+func (w *Writer) addEntry(e MetaEntry) error {
+	for _, existing := range w.entries {
+		if existing.Key == e.Key {
+			return fmt.Errorf("metadata value %q already added", e.Key)
+		}
+	}
+
+	w.entries = append(w.entries, e)
+
+	return nil
+}
+
+// AddTensor adds a tensor to the writer. Exactly Size() bytes will
+// be read from data when the file is written. Only types known to this
+// package can be added.
+func (w *Writer) AddTensor(name string, dimensions []uint64, typ GGML, data io.Reader) error {
+	s, found := sizes[typ]
+	if !found {
+		return fmt.Errorf("tensor %q: unknown type: %s", name, typ)
+	}
+
+	if len(dimensions) > 0 && dimensions[0]%s.valuesinblock != 0 {
+		return fmt.Errorf("tensor %q: first dimension %d is not a multiple of the %s block size %d", name, dimensions[0], typ, s.valuesinblock)
+	}
+
+	for _, t := range w.tensors {
+		if t.Name == name {
+			return fmt.Errorf("tensor %q already added", name)
+		}
+	}
+
+	t := writerTensor{
+		TensorInfo: TensorInfo{
+			Name:       name,
+			Dimensions: append([]uint64(nil), dimensions...),
+			Type:       typ,
+		},
+		data: data,
+	}
+
+	t.size, t.err = t.SizeErr()
+
+	w.tensors = append(w.tensors, t)
+
+	return nil
+}
+
+// SetTensorData sets the reader the data of the named tensor is read
+// from when the file is written. Exactly Size() bytes will be read.
+func (w *Writer) SetTensorData(name string, data io.Reader) error {
+	for i := range w.tensors {
+		if w.tensors[i].Name == name {
+			w.tensors[i].data = data
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("tensor %q not found", name)
+}
+
+// alignment returns the alignment as defined by general.alignment.
+func (w *Writer) alignment() (int64, error) {
+	for _, e := range w.entries {
+		if e.Key != "general.alignment" {
+			continue
+		}
+
+		a, ok := e.Value.(uint32)
+		if !ok || a == 0 {
+			return 0, fmt.Errorf("invalid alignment: %v (%s)", e.Value, e.Type)
+		}
+
+		return int64(a), nil
+	}
+
+	return defaultAlignment, nil
+}
+
+// WriteTo writes the complete GGUF file to out. Tensor offsets are
+// computed from the tensor sizes and general.alignment, unless they
+// were kept by NewWriterFrom().
+// Implements io.WriterTo.
+func (w *Writer) WriteTo(out io.Writer) (int64, error) {
+	c := &countingWriter{w: out}
+	b := bufio.NewWriter(c)
+
+	err := w.write(b, false)
+	if err != nil {
+		return c.n, err
+	}
+
+	err = b.Flush()
+
+	return c.n, err
+}
+
+// WriteHeaderTo writes only the header to out, including the padding
+// up to the start of the tensor data. Tensor data is not needed.
+func (w *Writer) WriteHeaderTo(out io.Writer) (int64, error) {
+	c := &countingWriter{w: out}
+	b := bufio.NewWriter(c)
+
+	err := w.write(b, true)
+	if err != nil {
+		return c.n, err
+	}
+
+	err = b.Flush()
+
+	return c.n, err
+}
+
+// write writes the file to b. If headerOnly is set, the tensor data is
+// left out.
+func (w *Writer) write(b io.Writer, headerOnly bool) error {
+	e := &encoder{
+		w:         &countingWriter{w: b},
+		byteOrder: w.ByteOrder,
+		version:   w.Version,
+	}
+
+	switch w.Version {
+	case 1:
+		e.writeUint = writeCast[uint64, uint32]
+
+	case 2, 3:
+		e.writeUint = write[uint64]
+
+	default:
+		return fmt.Errorf("invalid version: %d", w.Version)
+	}
+
+	alignment, err := w.alignment()
+	if err != nil {
+		return err
+	}
+
+	end := uint64(0)
+
+	for i := range w.tensors {
+		t := &w.tensors[i]
+
+		if t.err != nil {
+			return t.err
+		}
+
+		if !t.keepOffset {
+			t.Offset = uint64(align(int64(end), alignment))
+		} else if t.Offset < end {
+			return fmt.Errorf("tensor %q at offset %d overlaps the previous tensor", t.Name, t.Offset)
+		}
+
+		end = t.Offset + uint64(t.size)
+	}
+
+	_, err = io.WriteString(e.w, magic)
+	if err != nil {
+		return err
+	}
+
+	err = write(e.w, w.ByteOrder, uint32(w.Version))
+	if err != nil {
+		return err
+	}
+
+	err = e.writeUint(e.w, w.ByteOrder, uint64(len(w.tensors)))
+	if err != nil {
+		return err
+	}
+
+	err = e.writeUint(e.w, w.ByteOrder, uint64(len(w.entries)))
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range w.entries {
+		err = e.writeEntry(entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range w.tensors {
+		err = e.writeTensorInfo(&w.tensors[i].TensorInfo)
+		if err != nil {
+			return fmt.Errorf("tensor %q: %w", w.tensors[i].Name, err)
+		}
+	}
+
+	err = e.pad(alignment)
+	if err != nil {
+		return err
+	}
+
+	if headerOnly {
+		return nil
+	}
+
+	dataStart := e.w.n
+
+	for _, t := range w.tensors {
+		if t.data == nil {
+			return fmt.Errorf("tensor %q: no data", t.Name)
+		}
+
+		err = e.zeros(dataStart + int64(t.Offset) - e.w.n)
+		if err != nil {
+			return err
+		}
+
+		n, err := io.CopyN(e.w, t.data, t.size)
+		if err != nil {
+			return fmt.Errorf("tensor %q: read %d of %d bytes: %w", t.Name, n, t.size, err)
+		}
+	}
+
+	return e.pad(alignment)
+}
+
+// lazyTensorReader reads the data of a tensor from a Reader. The
+// section reader isn't created until the first call to Read().
+type lazyTensorReader struct {
+	t *TensorInfo
+	r io.Reader
+}
+
+// Read implements io.Reader.
+func (l *lazyTensorReader) Read(p []byte) (int, error) {
+	if l.r == nil {
+		r, err := l.t.SectionReader()
+		if err != nil {
+			return 0, err
+		}
+
+		l.r = r
+	}
+
+	return l.r.Read(p)
+}
+
+// align rounds n up to the nearest multiple of alignment.
+func align(n int64, alignment int64) int64 {
+	return (n + alignment - 1) / alignment * alignment
+}
+
+// encoder writes GGUF structures to w.
+type encoder struct {
+	w         *countingWriter
+	byteOrder binary.ByteOrder
+	version   int
+
+	// Helper to write uint32 or uint64 depending on GGUF version.
+	writeUint func(io.Writer, binary.ByteOrder, uint64) error
+}
+
+// writeString writes a GGUF string.
+func (e *encoder) writeString(s string) error {
+	err := e.writeUint(e.w, e.byteOrder, uint64(len(s)))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(e.w, s)
+
+	return err
+}
+
+// writeScalar writes a normalized scalar value.
+func (e *encoder) writeScalar(typ Type, value interface{}) error {
+	if typ >= Uint64 && e.version == 1 {
+		return fmt.Errorf("type %s is not supported in version 1", typ)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return e.writeString(v)
+
+	case bool:
+		b := uint8(0)
+		if v {
+			b = 1
+		}
+
+		return write(e.w, e.byteOrder, b)
+
+	default:
+		return binary.Write(e.w, e.byteOrder, v)
+	}
+}
+
+// writeEntry writes a metadata key/value pair.
+func (e *encoder) writeEntry(entry MetaEntry) error {
+	err := e.writeString(entry.Key)
+	if err != nil {
+		return err
+	}
+
+	err = write(e.w, e.byteOrder, uint32(entry.Type))
+	if err != nil {
+		return err
+	}
+
+	if entry.Type == Array {
+		err = e.writeArray(entry.ArrayType, entry.Value)
+	} else {
+		err = e.writeScalar(entry.Type, entry.Value)
+	}
+
+	if err != nil {
+		return fmt.Errorf("metadata value %q: %w", entry.Key, err)
+	}
+
+	return nil
+}
+
+// writeArray writes the element type, length and elements of a
+// normalized array.
+func (e *encoder) writeArray(elemType Type, value interface{}) error {
+	if elemType >= Uint64 && e.version == 1 {
+		return fmt.Errorf("type %s is not supported in version 1", elemType)
+	}
+
+	err := write(e.w, e.byteOrder, uint32(elemType))
+	if err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case []string:
+		err = e.writeUint(e.w, e.byteOrder, uint64(len(v)))
+		if err != nil {
+			return err
+		}
+
+		for _, s := range v {
+			err = e.writeString(s)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case []bool:
+		err = e.writeUint(e.w, e.byteOrder, uint64(len(v)))
+		if err != nil {
+			return err
+		}
+
+		for _, b := range v {
+			err = e.writeScalar(Bool, b)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case []MetaArray:
+		err = e.writeUint(e.w, e.byteOrder, uint64(len(v)))
+		if err != nil {
+			return err
+		}
+
+		for _, a := range v {
+			err = e.writeArray(a.Type, a.Value)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	default:
+		if binary.Size(v) < 0 {
+			return fmt.Errorf("unsupported array value: %T", value)
+		}
+
+		err = e.writeUint(e.w, e.byteOrder, uint64(arrayLen(v)))
+		if err != nil {
+			return err
+		}
+
+		return binary.Write(e.w, e.byteOrder, v)
+	}
+}
+
+// writeTensorInfo writes the tensor info header for t.
+func (e *encoder) writeTensorInfo(t *TensorInfo) error {
+	err := e.writeString(t.Name)
+	if err != nil {
+		return err
+	}
+
+	err = write(e.w, e.byteOrder, uint32(len(t.Dimensions)))
+	if err != nil {
+		return err
+	}
+
+	for _, d := range t.Dimensions {
+		err = e.writeUint(e.w, e.byteOrder, d)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = write(e.w, e.byteOrder, uint32(t.Type))
+	if err != nil {
+		return err
+	}
+
+	return e.writeUint(e.w, e.byteOrder, t.Offset)
+}
+
+// pad writes zero bytes until the file position is a multiple of
+// alignment.
+func (e *encoder) pad(alignment int64) error {
+	return e.zeros(align(e.w.n, alignment) - e.w.n)
+}
+
+// zeros writes n zero bytes without allocating a buffer of that size,
+// as the gap between tensors can be large.
+func (e *encoder) zeros(n int64) error {
+	_, err := io.CopyN(e.w, zeroReader{}, n)
+
+	return err
+}
+
+// zeroReader is an io.Reader returning an endless stream of zeros.
+type zeroReader struct{}
+
+// Read implements io.Reader.
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+
+	return len(p), nil
+}
+
+// arrayLen returns the length of a normalized numeric array.
+func arrayLen(value interface{}) int {
+	switch v := value.(type) {
+	case []uint8:
+		return len(v)
+	case []int8:
+		return len(v)
+	case []uint16:
+		return len(v)
+	case []int16:
+		return len(v)
+	case []uint32:
+		return len(v)
+	case []int32:
+		return len(v)
+	case []float32:
+		return len(v)
+	case []uint64:
+		return len(v)
+	case []int64:
+		return len(v)
+	case []float64:
+		return len(v)
+	default:
+		return 0
+	}
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements io.Writer.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+
+	return n, err
+}
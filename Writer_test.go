@@ -0,0 +1,279 @@
+package gguf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// encode writes w to memory and opens the result.
+func encode(t *testing.T, w *Writer, opts ...Option) (*Reader, []byte) {
+	t.Helper()
+
+	var b bytes.Buffer
+
+	_, err := w.WriteTo(&b)
+	if err != nil {
+		t.Fatalf("WriteTo: %s", err)
+	}
+
+	r, err := OpenReaderAt(bytes.NewReader(b.Bytes()), int64(b.Len()), opts...)
+	if err != nil {
+		t.Fatalf("OpenReaderAt: %s", err)
+	}
+
+	return r, b.Bytes()
+}
+
+// testWriter returns a writer with a little metadata and two F32
+// tensors.
+func testWriter(t *testing.T, byteOrder binary.ByteOrder) *Writer {
+	t.Helper()
+
+	w := NewWriter(byteOrder)
+
+	err := w.AddMetadata("general.architecture", String, "llama")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = w.AddArray("tokenizer.ggml.tokens", String, []string{"<s>", " the", "\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = w.AddTensor("a.weight", []uint64{4, 2}, GgmlFloat32, bytes.NewReader(make([]byte, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = w.AddTensor("b.weight", []uint64{3}, GgmlFloat32, bytes.NewReader(make([]byte, 12)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return w
+}
+
+func TestNewWriterFromRoundTrip(t *testing.T) {
+	for _, byteOrder := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		r, data := encode(t, testWriter(t, byteOrder))
+
+		var b bytes.Buffer
+
+		_, err := NewWriterFrom(r).WriteTo(&b)
+		if err != nil {
+			t.Fatalf("%s: WriteTo: %s", byteOrder, err)
+		}
+
+		if !bytes.Equal(b.Bytes(), data) {
+			t.Errorf("%s: rewritten file differs from the original", byteOrder)
+		}
+	}
+}
+
+func TestNewWriterFromOffsetOutsideFile(t *testing.T) {
+	r, _ := encode(t, testWriter(t, binary.LittleEndian))
+
+	r.Tensors[1].Offset = 1 << 62
+
+	out := &countingWriter{w: &bytes.Buffer{}}
+
+	_, err := NewWriterFrom(r).WriteTo(out)
+	if !errors.Is(err, ErrTruncated) {
+		t.Fatalf("expected ErrTruncated, got %v", err)
+	}
+
+	if out.n != 0 {
+		t.Errorf("%d bytes written before the error", out.n)
+	}
+}
+
+// zeroPaddedReaderAt reads data followed by an endless stream of zeros.
+type zeroPaddedReaderAt struct {
+	data []byte
+}
+
+func (z zeroPaddedReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n := 0
+	if off < int64(len(z.data)) {
+		n = copy(p, z.data[off:])
+	}
+
+	for i := n; i < len(p); i++ {
+		p[i] = 0
+	}
+
+	return len(p), nil
+}
+
+func TestNewWriterFromPaddingStreamed(t *testing.T) {
+	const gap = 1 << 30
+
+	_, data := encode(t, testWriter(t, binary.LittleEndian))
+
+	r, err := OpenReaderAt(zeroPaddedReaderAt{data: data}, int64(len(data))+gap)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Tensors[1].Offset += gap
+
+	out := &countingWriter{w: io.Discard}
+
+	var before, after runtime.MemStats
+
+	runtime.ReadMemStats(&before)
+
+	_, err = NewWriterFrom(r).WriteTo(out)
+	if err != nil {
+		t.Fatalf("WriteTo: %s", err)
+	}
+
+	runtime.ReadMemStats(&after)
+
+	if out.n != int64(len(data))+gap {
+		t.Errorf("wrote %d bytes, expected %d", out.n, int64(len(data))+gap)
+	}
+
+	allocated := after.TotalAlloc - before.TotalAlloc
+	if allocated > 16<<20 {
+		t.Errorf("%d bytes allocated writing %d bytes of padding", allocated, gap)
+	}
+}
+
+func TestNestedArrayRoundTrip(t *testing.T) {
+	// Two levels of nesting, with inner arrays of different types and
+	// empty inner arrays at both levels.
+	nested := []MetaArray{
+		{Type: Array, Value: []MetaArray{
+			{Type: Uint8, Value: []uint8{1, 2}},
+			{Type: String, Value: []string{}},
+			{Type: Float64, Value: []float64{0.5, -1}},
+		}},
+		{Type: Array, Value: []MetaArray{}},
+		{Type: String, Value: []string{"a", ""}},
+		{Type: Int64, Value: []int64{}},
+		{Type: Bool, Value: []bool{true, false}},
+		{Type: Array, Value: []MetaArray{
+			{Type: Int32, Value: []int32{-7}},
+			{Type: Array, Value: []MetaArray{}},
+		}},
+	}
+
+	for _, byteOrder := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		w := NewWriter(byteOrder)
+
+		err := w.AddArray("test.nested", Array, nested)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = w.AddMetadata("test.after", Uint32, uint32(42))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r, data := encode(t, w)
+
+		if !reflect.DeepEqual(r.Metadata["test.nested"], nested) {
+			t.Errorf("%s: expected %v, got %v", byteOrder, nested, r.Metadata["test.nested"])
+		}
+
+		// The value after the array is read from the right offset.
+		if r.Metadata["test.after"] != uint32(42) {
+			t.Errorf("%s: expected 42 after the array, got %v", byteOrder, r.Metadata["test.after"])
+		}
+
+		var b bytes.Buffer
+
+		_, err = NewWriterFrom(r).WriteTo(&b)
+		if err != nil {
+			t.Fatalf("%s: WriteTo: %s", byteOrder, err)
+		}
+
+		if !bytes.Equal(b.Bytes(), data) {
+			t.Errorf("%s: rewritten file differs from the original", byteOrder)
+		}
+	}
+}
+
+func TestWriteVersion1Overflow(t *testing.T) {
+	cases := []struct {
+		tensors  [][]uint64
+		expected string
+	}{
+		{[][]uint64{{1 << 32}}, `tensor "t0": 4294967296 overflows uint32`},
+
+		// The first tensor fits, but pushes the offset of the
+		// second beyond 32 bits.
+		{[][]uint64{{1 << 30}, {1}}, `tensor "t1": 4294967296 overflows uint32`},
+	}
+
+	for _, c := range cases {
+		w := NewWriter(binary.LittleEndian)
+		w.Version = 1
+
+		for i, dims := range c.tensors {
+			err := w.AddTensor(fmt.Sprintf("t%d", i), dims, GgmlFloat32, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		_, err := w.WriteHeaderTo(io.Discard)
+		if err == nil || err.Error() != c.expected {
+			t.Errorf("%v: expected %q, got %v", c.tensors, c.expected, err)
+		}
+
+		// Version 3 has room for both.
+		w.Version = 3
+
+		_, err = w.WriteHeaderTo(io.Discard)
+		if err != nil {
+			t.Errorf("%v: version 3: %s", c.tensors, err)
+		}
+	}
+
+	// Small files are still written as version 1.
+	w := testWriter(t, binary.LittleEndian)
+	w.Version = 1
+
+	r, _ := encode(t, w)
+	if r.Version != 1 || len(r.Tensors) != 2 || r.Tensors[1].Offset != 32 {
+		t.Errorf("unexpected version 1 file: version %d, tensors %+v", r.Version, r.Tensors)
+	}
+}
+
+func TestWriteCast(t *testing.T) {
+	var b bytes.Buffer
+
+	err := writeCast[uint64, uint32](&b, binary.LittleEndian, math.MaxUint32)
+	if err != nil || !bytes.Equal(b.Bytes(), []byte{0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("MaxUint32: got %x, %v", b.Bytes(), err)
+	}
+
+	b.Reset()
+
+	for _, v := range []int64{-1, 1 << 32, math.MinInt64} {
+		err = writeCast[int64, uint32](&b, binary.LittleEndian, v)
+		if err == nil {
+			t.Errorf("%d: expected an error", v)
+		}
+	}
+
+	err = writeCast[uint64, int32](&b, binary.LittleEndian, 1<<31)
+	if err == nil {
+		t.Error("1<<31: expected an error")
+	}
+
+	if b.Len() != 0 {
+		t.Errorf("%d bytes written for values out of range", b.Len())
+	}
+}
@@ -0,0 +1,29 @@
+package gguf
+
+import (
+	"math"
+)
+
+// BFloat16ToFloat32 converts a bfloat16 value to float32. The
+// conversion is exact.
+func BFloat16ToFloat32(b uint16) float32 {
+	return math.Float32frombits(uint32(b) << 16)
+}
+
+// Float32ToBFloat16 converts a float32 value to bfloat16, rounding to
+// nearest even like ggml does. NaN values are kept as quiet NaN.
+func Float32ToBFloat16(f float32) uint16 {
+	u := math.Float32bits(f)
+
+	if u&0x7fffffff > 0x7f800000 {
+		return uint16(u>>16) | 64
+	}
+
+	return uint16((u + (0x7fff + ((u >> 16) & 1))) >> 16)
+}
+
+// Float64ToFloat32 converts a float64 value to float32, rounding to
+// nearest. Values too large for float32 become infinite.
+func Float64ToFloat32(f float64) float32 {
+	return float32(f)
+}
@@ -0,0 +1,246 @@
+package gguf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// dequantizer decodes whole blocks from src into dst. len(src) is a
+// multiple of the block size, and dst has room for all the values.
+type dequantizer func(dst []float32, src []byte, byteOrder binary.ByteOrder)
+
+// dequantizers is a map of GGML to the function decoding it. It's used
+// by Dequantize().
+var dequantizers = map[GGML]dequantizer{
+	GgmlFloat32:  dequantizeF32,
+	GgmlFloat16:  dequantizeF16,
+	GgmlBFloat16: dequantizeBF16,
+	GgmlFloat64:  dequantizeF64,
+	GgmlQ4_0:     dequantizeQ4_0,
+	GgmlQ4_1:     dequantizeQ4_1,
+	GgmlQ5_0:     dequantizeQ5_0,
+	GgmlQ5_1:     dequantizeQ5_1,
+	GgmlQ8_0:     dequantizeQ8_0,
+	GgmlQ8_1:     dequantizeQ8_1,
+	GgmlQ2_K:     dequantizeQ2_K,
+	GgmlQ3_K:     dequantizeQ3_K,
+	GgmlQ4_K:     dequantizeQ4_K,
+	GgmlQ5_K:     dequantizeQ5_K,
+	GgmlQ6_K:     dequantizeQ6_K,
+	GgmlQ8_K:     dequantizeQ8_K,
+	GgmlIQ2_XXS:  dequantizeIQ2_XXS,
+	GgmlIQ2_XS:   dequantizeIQ2_XS,
+	GgmlIQ2_S:    dequantizeIQ2_S,
+	GgmlIQ3_XXS:  dequantizeIQ3_XXS,
+	GgmlIQ3_S:    dequantizeIQ3_S,
+	GgmlIQ1_S:    dequantizeIQ1_S,
+	GgmlIQ1_M:    dequantizeIQ1_M,
+	GgmlIQ4_NL:   dequantizeIQ4_NL,
+	GgmlIQ4_XS:   dequantizeIQ4_XS,
+}
+
+// Dequantize decodes data encoded as typ to float32 values. Multi-byte
+// fields in the data are read in byteOrder. data must consist of
+// whole blocks.
+func Dequantize(typ GGML, data []byte, byteOrder binary.ByteOrder) ([]float32, error) {
+	s, found := sizes[typ]
+	if !found {
+		return nil, fmt.Errorf("unknown type: %s", typ)
+	}
+
+	dst := make([]float32, uint64(len(data))/s.blocksize*s.valuesinblock)
+
+	err := DequantizeInto(dst, typ, data, byteOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	return dst, nil
+}
+
+// DequantizeInto decodes data encoded as typ to float32 values stored
+// in dst. dst must have room for all the values in data.
+func DequantizeInto(dst []float32, typ GGML, data []byte, byteOrder binary.ByteOrder) error {
+	s, found := sizes[typ]
+	if !found {
+		return fmt.Errorf("unknown type: %s", typ)
+	}
+
+	f, found := dequantizers[typ]
+	if !found {
+		return fmt.Errorf("dequantization of %s is not supported", typ)
+	}
+
+	if uint64(len(data))%s.blocksize != 0 {
+		return fmt.Errorf("%d bytes is not a whole number of %s blocks", len(data), typ)
+	}
+
+	values := uint64(len(data)) / s.blocksize * s.valuesinblock
+	if uint64(len(dst)) < values {
+		return fmt.Errorf("destination has room for %d values, need %d", len(dst), values)
+	}
+
+	f(dst[:values], data, byteOrder)
+
+	return nil
+}
+
+// Dequantize reads the tensor data and decodes it to float32 values.
+func (t *TensorInfo) Dequantize() ([]float32, error) {
+	r, err := t.SectionReader()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, r.Size())
+
+	_, err = io.ReadFull(r, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return Dequantize(t.Type, data, t.g.ByteOrder)
+}
+
+func dequantizeF32(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	for i := range dst {
+		dst[i] = math.Float32frombits(byteOrder.Uint32(src[i*4:]))
+	}
+}
+
+func dequantizeF16(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	for i := range dst {
+		dst[i] = Float16ToFloat32(byteOrder.Uint16(src[i*2:]))
+	}
+}
+
+func dequantizeBF16(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	for i := range dst {
+		dst[i] = BFloat16ToFloat32(byteOrder.Uint16(src[i*2:]))
+	}
+}
+
+func dequantizeF64(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	for i := range dst {
+		dst[i] = Float64ToFloat32(math.Float64frombits(byteOrder.Uint64(src[i*8:])))
+	}
+}
+
+// half reads a float16 value from b.
+func half(b []byte, byteOrder binary.ByteOrder) float32 {
+	return Float16ToFloat32(byteOrder.Uint16(b))
+}
+
+func dequantizeQ4_0(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	const blocksize = 2 + qK4_0/2
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK4_0 : (i+1)*qK4_0]
+
+		d := half(b, byteOrder)
+		qs := b[2:]
+
+		for j := 0; j < qK4_0/2; j++ {
+			y[j] = float32(int(qs[j]&0x0f)-8) * d
+			y[j+qK4_0/2] = float32(int(qs[j]>>4)-8) * d
+		}
+	}
+}
+
+func dequantizeQ4_1(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	const blocksize = 4 + qK4_1/2
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK4_1 : (i+1)*qK4_1]
+
+		d := half(b, byteOrder)
+		m := half(b[2:], byteOrder)
+		qs := b[4:]
+
+		for j := 0; j < qK4_1/2; j++ {
+			y[j] = float32(qs[j]&0x0f)*d + m
+			y[j+qK4_1/2] = float32(qs[j]>>4)*d + m
+		}
+	}
+}
+
+func dequantizeQ5_0(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	const blocksize = 2 + 4 + qK5_0/2
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK5_0 : (i+1)*qK5_0]
+
+		d := half(b, byteOrder)
+		qh := binary.LittleEndian.Uint32(b[2:])
+		qs := b[6:]
+
+		for j := 0; j < qK5_0/2; j++ {
+			xh0 := byte((qh>>j)<<4) & 0x10
+			xh1 := byte(qh>>(j+12)) & 0x10
+
+			y[j] = float32(int(qs[j]&0x0f|xh0)-16) * d
+			y[j+qK5_0/2] = float32(int(qs[j]>>4|xh1)-16) * d
+		}
+	}
+}
+
+func dequantizeQ5_1(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	const blocksize = 4 + 4 + qK5_1/2
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK5_1 : (i+1)*qK5_1]
+
+		d := half(b, byteOrder)
+		m := half(b[2:], byteOrder)
+		qh := binary.LittleEndian.Uint32(b[4:])
+		qs := b[8:]
+
+		for j := 0; j < qK5_1/2; j++ {
+			xh0 := byte((qh>>j)<<4) & 0x10
+			xh1 := byte(qh>>(j+12)) & 0x10
+
+			y[j] = float32(qs[j]&0x0f|xh0)*d + m
+			y[j+qK5_1/2] = float32(qs[j]>>4|xh1)*d + m
+		}
+	}
+}
+
+func dequantizeQ8_0(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	const blocksize = 2 + qK8_0
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK8_0 : (i+1)*qK8_0]
+
+		d := half(b, byteOrder)
+		qs := b[2:]
+
+		for j := 0; j < qK8_0; j++ {
+			y[j] = float32(int8(qs[j])) * d
+		}
+	}
+}
+
+func dequantizeQ8_1(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	const blocksize = 2 + 2 + qK8_0
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK8_0 : (i+1)*qK8_0]
+
+		// The second half is the precomputed sum of the values,
+		// which is not needed for dequantization.
+		d := half(b, byteOrder)
+		qs := b[4:]
+
+		for j := 0; j < qK8_0; j++ {
+			y[j] = float32(int8(qs[j])) * d
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package gguf
+
+import (
+	"encoding/binary"
+)
+
+// kvaluesIQ4NL is the non-linear codebook used by IQ4_NL and IQ4_XS.
+var kvaluesIQ4NL = [16]int8{-127, -104, -83, -65, -49, -35, -22, -10, 1, 13, 25, 38, 53, 69, 89, 113}
+
+func dequantizeIQ4_NL(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	const blocksize = 2 + qK4_NL/2
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK4_NL : (i+1)*qK4_NL]
+
+		d := half(b, byteOrder)
+		qs := b[2:]
+
+		for j := 0; j < qK4_NL/2; j++ {
+			y[j] = d * float32(kvaluesIQ4NL[qs[j]&0x0f])
+			y[j+qK4_NL/2] = d * float32(kvaluesIQ4NL[qs[j]>>4])
+		}
+	}
+}
+
+func dequantizeIQ4_XS(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlIQ4_XS].blocksize)
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK_K : (i+1)*qK_K]
+
+		d := half(b, byteOrder)
+		scalesH := byteOrder.Uint16(b[2:])
+		scalesL := b[4 : 4+qK_K/64]
+		qs := b[4+qK_K/64:]
+
+		for ib := 0; ib < qK_K/32; ib++ {
+			ls := int((scalesL[ib/2]>>(4*(ib%2)))&0x0f) | int((scalesH>>(2*ib))&3)<<4
+			dl := d * float32(ls-32)
+
+			for j := 0; j < 16; j++ {
+				y[j] = dl * float32(kvaluesIQ4NL[qs[j]&0x0f])
+				y[j+16] = dl * float32(kvaluesIQ4NL[qs[j]>>4])
+			}
+
+			y = y[32:]
+			qs = qs[16:]
+		}
+	}
+}
+
+// iq1Delta is the offset added to IQ1_S and IQ1_M grid values.
+const iq1Delta = 0.125
+
+// sign returns -1 if bit j of signs is set, and 1 otherwise.
+func sign(signs uint8, j int) float32 {
+	if signs&kmaskIQ2XS[j] != 0 {
+		return -1
+	}
+
+	return 1
+}
+
+func dequantizeIQ2_XXS(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlIQ2_XXS].blocksize)
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK_K : (i+1)*qK_K]
+
+		d := half(b, byteOrder)
+		qs := b[2:]
+
+		for ib32 := 0; ib32 < qK_K/32; ib32++ {
+			// Four uint16 values: two words of grid indexes
+			// followed by a word of signs and scale.
+			q := qs[8*ib32:]
+			aux0 := uint32(byteOrder.Uint16(q)) | uint32(byteOrder.Uint16(q[2:]))<<16
+			aux1 := uint32(byteOrder.Uint16(q[4:])) | uint32(byteOrder.Uint16(q[6:]))<<16
+
+			db := d * (0.5 + float32(aux1>>28)) * 0.25
+
+			for l := 0; l < 4; l++ {
+				grid := gridIQ2XXS[uint8(aux0>>(8*l))]
+				signs := ksignsIQ2XS[(aux1>>(7*l))&127]
+
+				for j := 0; j < 8; j++ {
+					y[j] = db * float32(uint8(grid>>(8*j))) * sign(signs, j)
+				}
+
+				y = y[8:]
+			}
+		}
+	}
+}
+
+func dequantizeIQ2_XS(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlIQ2_XS].blocksize)
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK_K : (i+1)*qK_K]
+
+		d := half(b, byteOrder)
+		qs := b[2 : 2+qK_K/4]
+		scales := b[2+qK_K/4:]
+
+		for ib32 := 0; ib32 < qK_K/32; ib32++ {
+			db := [2]float32{
+				d * (0.5 + float32(scales[ib32]&0x0f)) * 0.25,
+				d * (0.5 + float32(scales[ib32]>>4)) * 0.25,
+			}
+
+			for l := 0; l < 4; l++ {
+				q := byteOrder.Uint16(qs[2*(4*ib32+l):])
+				grid := gridIQ2XS[q&511]
+				signs := ksignsIQ2XS[q>>9]
+
+				for j := 0; j < 8; j++ {
+					y[j] = db[l/2] * float32(uint8(grid>>(8*j))) * sign(signs, j)
+				}
+
+				y = y[8:]
+			}
+		}
+	}
+}
+
+func dequantizeIQ2_S(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlIQ2_S].blocksize)
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK_K : (i+1)*qK_K]
+
+		d := half(b, byteOrder)
+		qs := b[2 : 2+qK_K/8]
+		signs := b[2+qK_K/8 : 2+qK_K/4]
+		qh := b[2+qK_K/4 : 2+qK_K/4+qK_K/32]
+		scales := b[2+qK_K/4+qK_K/32:]
+
+		for ib32 := 0; ib32 < qK_K/32; ib32++ {
+			db := [2]float32{
+				d * (0.5 + float32(scales[ib32]&0x0f)) * 0.25,
+				d * (0.5 + float32(scales[ib32]>>4)) * 0.25,
+			}
+
+			for l := 0; l < 4; l++ {
+				grid := gridIQ2S[int(qs[l])|(int(qh[ib32])<<(8-2*l))&0x300]
+
+				for j := 0; j < 8; j++ {
+					y[j] = db[l/2] * float32(uint8(grid>>(8*j))) * sign(signs[l], j)
+				}
+
+				y = y[8:]
+			}
+
+			qs = qs[4:]
+			signs = signs[4:]
+		}
+	}
+}
+
+func dequantizeIQ3_XXS(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlIQ3_XXS].blocksize)
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK_K : (i+1)*qK_K]
+
+		d := half(b, byteOrder)
+		qs := b[2 : 2+qK_K/4]
+		scalesAndSigns := b[2+qK_K/4:]
+
+		for ib32 := 0; ib32 < qK_K/32; ib32++ {
+			aux := binary.LittleEndian.Uint32(scalesAndSigns[4*ib32:])
+			db := d * (0.5 + float32(aux>>28)) * 0.5
+
+			for l := 0; l < 4; l++ {
+				signs := ksignsIQ2XS[(aux>>(7*l))&127]
+				grid1 := gridIQ3XXS[qs[2*l]]
+				grid2 := gridIQ3XXS[qs[2*l+1]]
+
+				for j := 0; j < 4; j++ {
+					y[j] = db * float32(uint8(grid1>>(8*j))) * sign(signs, j)
+					y[j+4] = db * float32(uint8(grid2>>(8*j))) * sign(signs, j+4)
+				}
+
+				y = y[8:]
+			}
+
+			qs = qs[8:]
+		}
+	}
+}
+
+func dequantizeIQ3_S(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlIQ3_S].blocksize)
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK_K : (i+1)*qK_K]
+
+		d := half(b, byteOrder)
+		qs := b[2 : 2+qK_K/4]
+		qh := b[2+qK_K/4 : 2+qK_K/4+qK_K/32]
+		signs := b[2+qK_K/4+qK_K/32 : 2+qK_K/4+qK_K/32+qK_K/8]
+		scales := b[2+qK_K/4+qK_K/32+qK_K/8:]
+
+		for ib32 := 0; ib32 < qK_K/32; ib32++ {
+			var db float32
+			if ib32%2 == 0 {
+				db = d * float32(1+2*int(scales[ib32/2]&0x0f))
+			} else {
+				db = d * float32(1+2*int(scales[ib32/2]>>4))
+			}
+
+			for l := 0; l < 4; l++ {
+				grid1 := gridIQ3S[int(qs[2*l])|(int(qh[ib32])<<(8-2*l))&256]
+				grid2 := gridIQ3S[int(qs[2*l+1])|(int(qh[ib32])<<(7-2*l))&256]
+
+				for j := 0; j < 4; j++ {
+					y[j] = db * float32(uint8(grid1>>(8*j))) * sign(signs[l], j)
+					y[j+4] = db * float32(uint8(grid2>>(8*j))) * sign(signs[l], j+4)
+				}
+
+				y = y[8:]
+			}
+
+			qs = qs[8:]
+			signs = signs[4:]
+		}
+	}
+}
+
+func dequantizeIQ1_S(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlIQ1_S].blocksize)
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK_K : (i+1)*qK_K]
+
+		d := half(b, byteOrder)
+		qs := b[2 : 2+qK_K/8]
+		qh := b[2+qK_K/8:]
+
+		for ib := 0; ib < qK_K/32; ib++ {
+			h := byteOrder.Uint16(qh[2*ib:])
+			dl := d * float32(2*((h>>12)&7)+1)
+
+			delta := float32(iq1Delta)
+			if h&0x8000 != 0 {
+				delta = -iq1Delta
+			}
+
+			for l := 0; l < 4; l++ {
+				grid := gridIQ1S[int(qs[l])|int((h>>(3*l))&7)<<8]
+
+				for j := 0; j < 8; j++ {
+					y[j] = dl * (float32(int8(grid>>(8*j))) + delta)
+				}
+
+				y = y[8:]
+			}
+
+			qs = qs[4:]
+		}
+	}
+}
+
+func dequantizeIQ1_M(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlIQ1_M].blocksize)
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK_K : (i+1)*qK_K]
+
+		qs := b[:qK_K/8]
+		qh := b[qK_K/8 : qK_K/8+qK_K/16]
+
+		var sc [4]uint16
+		for k := range sc {
+			sc[k] = byteOrder.Uint16(b[qK_K/8+qK_K/16+2*k:])
+		}
+
+		// The block scale is spread over the top nibbles of the
+		// four scale words.
+		d := Float16ToFloat32(sc[0]>>12 | (sc[1]>>8)&0x00f0 | (sc[2]>>4)&0x0f00 | sc[3]&0xf000)
+
+		for ib := 0; ib < qK_K/32; ib++ {
+			shift := 6 * (ib % 2)
+			dl := [2]float32{
+				d * float32(2*((sc[ib/2]>>shift)&7)+1),
+				d * float32(2*((sc[ib/2]>>(shift+3))&7)+1),
+			}
+
+			for l := 0; l < 4; l++ {
+				h := qh[l/2] >> (4 * (l % 2))
+				idx := int(qs[l]) | int(h&7)<<8
+
+				delta := float32(iq1Delta)
+				if h&0x08 != 0 {
+					delta = -iq1Delta
+				}
+
+				grid := gridIQ1S[idx]
+
+				for j := 0; j < 8; j++ {
+					y[j] = dl[l/2] * (float32(int8(grid>>(8*j))) + delta)
+				}
+
+				y = y[8:]
+			}
+
+			qs = qs[4:]
+			qh = qh[2:]
+		}
+	}
+}
@@ -0,0 +1,385 @@
+package gguf
+
+import (
+	"testing"
+)
+
+func TestDequantizeIQ(t *testing.T) {
+	testDequantize(t, dequantizeIQCases)
+}
+
+var dequantizeIQCases = []dequantizeCase{
+	{
+		name: "random",
+		typ:  GgmlIQ2_XXS,
+		data: "c0288a34b79c2cfd59f96b846889492d62e19108c44f7e448fe76883883d50cf3e435633fae2773f1d3c21008fb170223e182c4ae4bed9f0772173330d819e6c16ad",
+		want: []float32{
+			1.15039062, 1.15039062, -1.15039062, -3.5949707, 1.15039062, -1.15039062, 1.15039062, -3.5949707,
+			3.5949707, -1.15039062, 3.5949707, -6.18334961, -3.5949707, -3.5949707, -1.15039062, -1.15039062,
+			-1.15039062, -3.5949707, -6.18334961, 6.18334961, 3.5949707, -1.15039062, -3.5949707, -3.5949707,
+			1.15039062, -6.18334961, 1.15039062, -1.15039062, 1.15039062, 3.5949707, -1.15039062, -3.5949707,
+			-3.36303711, 1.07617188, 3.36303711, -3.36303711, 5.78442383, 5.78442383, -3.36303711, -1.07617188,
+			3.36303711, -1.07617188, 1.07617188, -1.07617188, -1.07617188, 1.07617188, -1.07617188, 3.36303711,
+			1.07617188, 1.07617188, 1.07617188, -1.07617188, 3.36303711, 5.78442383, 3.36303711, -1.07617188,
+			-1.07617188, -3.36303711, 5.78442383, -1.07617188, 1.07617188, 1.07617188, 1.07617188, -3.36303711,
+			1.07617188, -1.07617188, -1.07617188, -1.07617188, -3.36303711, -1.07617188, -1.07617188, 3.36303711,
+			5.78442383, 1.07617188, 5.78442383, -1.07617188, 1.07617188, 1.07617188, 1.07617188, -1.07617188,
+			-1.07617188, 3.36303711, -1.07617188, -1.07617188, -1.07617188, -1.07617188, 5.78442383, -3.36303711,
+			1.07617188, 1.07617188, -3.36303711, -5.78442383, -1.07617188, -1.07617188, 3.36303711, 1.07617188,
+			0.333984375, 0.333984375, 0.333984375, 0.333984375, -1.04370117, 1.79516602, -1.04370117, 0.333984375,
+			0.333984375, -1.04370117, -0.333984375, -1.04370117, -1.79516602, 1.79516602, 1.79516602, 0.333984375,
+			-1.04370117, -0.333984375, 1.79516602, -0.333984375, -0.333984375, -0.333984375, -0.333984375, 1.04370117,
+			-0.333984375, 1.04370117, 1.04370117, -0.333984375, -0.333984375, 1.79516602, 0.333984375, -0.333984375,
+			-0.259765625, -0.259765625, -0.259765625, 1.39624023, -0.811767578, -0.259765625, -0.811767578, 0.259765625,
+			0.259765625, -0.259765625, -0.259765625, -1.39624023, -0.811767578, -0.811767578, -0.259765625, 0.259765625,
+			0.811767578, 0.811767578, -0.259765625, 1.39624023, -0.259765625, -0.259765625, -1.39624023, 1.39624023,
+			1.39624023, 0.811767578, 0.259765625, 0.811767578, 0.811767578, -0.811767578, -0.259765625, 1.39624023,
+			0.111328125, 0.347900391, 0.111328125, 0.111328125, -0.111328125, -0.347900391, -0.111328125, -0.111328125,
+			0.111328125, 0.111328125, -0.111328125, 0.111328125, 0.111328125, 0.111328125, -0.111328125, 0.111328125,
+			0.111328125, 0.347900391, 0.111328125, -0.598388672, -0.111328125, -0.111328125, -0.111328125, 0.347900391,
+			-0.347900391, 0.598388672, 0.347900391, 0.111328125, 0.111328125, 0.111328125, -0.347900391, 0.347900391,
+			-0.185546875, 0.579833984, 0.997314453, -0.997314453, -0.185546875, 0.579833984, -0.185546875, 0.185546875,
+			-0.185546875, 0.185546875, 0.579833984, 0.185546875, 0.185546875, -0.185546875, -0.579833984, -0.185546875,
+			-0.579833984, -0.997314453, -0.185546875, -0.185546875, -0.997314453, 0.579833984, -0.185546875, 0.997314453,
+			-0.185546875, -0.185546875, 0.185546875, -0.185546875, 0.185546875, 0.997314453, 0.579833984, -0.579833984,
+			2.43530273, -4.1887207, -4.1887207, -0.779296875, -2.43530273, 0.779296875, 4.1887207, 0.779296875,
+			-0.779296875, 0.779296875, 0.779296875, -4.1887207, -2.43530273, 2.43530273, -0.779296875, 0.779296875,
+			-0.779296875, 0.779296875, 2.43530273, -2.43530273, -0.779296875, 0.779296875, -0.779296875, 0.779296875,
+			0.779296875, 0.779296875, 4.1887207, -0.779296875, 0.779296875, -4.1887207, -4.1887207, -0.779296875,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlIQ2_XS,
+		data: "c0288a34b79c2cfd59f96b846889492d62e19108c44f7e448fe76883883d50cf3e435633fae2773f1d3c21008fb170223e182c4ae4bed9f0772173330d819e6c16ad6b8c2db2fc88e648",
+		want: []float32{
+			4.58764648, -0.853515625, 2.66723633, -0.853515625, -0.853515625, 0.853515625, 2.66723633, -0.853515625,
+			0.853515625, -0.853515625, -2.66723633, -2.66723633, 0.853515625, 2.66723633, -2.66723633, 0.853515625,
+			0.482421875, -1.50756836, -1.50756836, -0.482421875, -0.482421875, -1.50756836, -0.482421875, 1.50756836,
+			0.482421875, 0.482421875, -1.50756836, -0.482421875, -1.50756836, -0.482421875, -1.50756836, -1.50756836,
+			4.98657227, -0.927734375, 0.927734375, 0.927734375, 0.927734375, 4.98657227, -0.927734375, 0.927734375,
+			0.927734375, 0.927734375, -0.927734375, 0.927734375, 2.89916992, 2.89916992, -2.89916992, 2.89916992,
+			1.97143555, -3.39086914, -3.39086914, 1.97143555, -1.97143555, 3.39086914, 0.630859375, -1.97143555,
+			0.630859375, 0.630859375, 1.97143555, 0.630859375, -0.630859375, -1.97143555, -1.97143555, -1.97143555,
+			3.13110352, 3.13110352, -1.00195312, 3.13110352, 1.00195312, 1.00195312, 3.13110352, -1.00195312,
+			-5.38549805, -3.13110352, -3.13110352, 5.38549805, 5.38549805, -5.38549805, 1.00195312, 5.38549805,
+			0.579833984, -0.185546875, 0.185546875, 0.997314453, 0.579833984, -0.997314453, 0.185546875, 0.185546875,
+			-0.997314453, -0.185546875, 0.997314453, 0.579833984, -0.997314453, -0.997314453, -0.997314453, -0.579833984,
+			-0.185546875, 0.185546875, 0.185546875, 0.185546875, 0.579833984, 0.579833984, -0.579833984, 0.579833984,
+			0.579833984, -0.579833984, -0.185546875, -0.579833984, -0.579833984, 0.579833984, 0.997314453, 0.579833984,
+			-0.853515625, -2.66723633, -2.66723633, 0.853515625, 0.853515625, -0.853515625, -2.66723633, -2.66723633,
+			-2.66723633, 0.853515625, 0.853515625, 0.853515625, 0.853515625, -4.58764648, 0.853515625, 2.66723633,
+			-0.927734375, 0.927734375, 0.927734375, -4.98657227, -0.927734375, 0.927734375, 2.89916992, -2.89916992,
+			-0.927734375, 0.927734375, 4.98657227, 0.927734375, -4.98657227, -4.98657227, -4.98657227, 0.927734375,
+			-3.5949707, -3.5949707, -1.15039062, -6.18334961, -6.18334961, 6.18334961, 3.5949707, -3.5949707,
+			3.5949707, -6.18334961, -3.5949707, -6.18334961, -1.15039062, 1.15039062, 1.15039062, 1.15039062,
+			3.39086914, 1.97143555, 0.630859375, 0.630859375, 1.97143555, 0.630859375, 0.630859375, 0.630859375,
+			3.39086914, 0.630859375, 3.39086914, -1.97143555, -3.39086914, 3.39086914, -3.39086914, -1.97143555,
+			-0.630859375, 1.97143555, 1.97143555, 0.630859375, -0.630859375, 3.39086914, 0.630859375, 0.630859375,
+			0.630859375, 0.630859375, -1.97143555, -1.97143555, 3.39086914, 0.630859375, 0.630859375, 0.630859375,
+			-1.50756836, 1.50756836, -0.482421875, 1.50756836, 1.50756836, -0.482421875, 0.482421875, -0.482421875,
+			-1.50756836, -0.482421875, -0.482421875, -0.482421875, -0.482421875, 1.50756836, -2.59301758, 0.482421875,
+			1.07617188, 1.07617188, 5.78442383, -5.78442383, -1.07617188, -1.07617188, -5.78442383, 1.07617188,
+			3.36303711, 3.36303711, 1.07617188, 5.78442383, -5.78442383, 5.78442383, 3.36303711, -3.36303711,
+			-1.97143555, 3.39086914, 1.97143555, -3.39086914, -0.630859375, 3.39086914, 1.97143555, -1.97143555,
+			1.97143555, 0.630859375, 1.97143555, 1.97143555, 0.630859375, 0.630859375, -0.630859375, -1.97143555,
+			1.04370117, -0.333984375, -1.04370117, 0.333984375, -1.04370117, -0.333984375, 1.04370117, 0.333984375,
+			1.04370117, -1.04370117, -0.333984375, 0.333984375, -1.04370117, 0.333984375, -0.333984375, 1.04370117,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlIQ2_S,
+		data: "c0288a34b79c2cfd59f96b846889492d62e19108c44f7e448fe76883883d50cf3e435633fae2773f1d3c21008fb170223e182c4ae4bed9f0772173330d819e6c16ad6b8c2db2fc88e648a34661314bea7ddc",
+		want: []float32{
+			0.811767578, -0.259765625, -0.259765625, 0.811767578, -0.259765625, 1.39624023, -0.259765625, 1.39624023,
+			-0.811767578, -0.811767578, 0.811767578, 0.811767578, -0.259765625, -0.811767578, 0.259765625, 0.811767578,
+			0.779296875, -0.779296875, 4.1887207, -0.779296875, -4.1887207, -0.779296875, -2.43530273, -2.43530273,
+			4.1887207, -4.1887207, 4.1887207, 0.779296875, 4.1887207, -0.779296875, -4.1887207, -0.779296875,
+			-0.482421875, -0.482421875, -0.482421875, 1.50756836, -1.50756836, -0.482421875, -0.482421875, 0.482421875,
+			-0.482421875, -2.59301758, -2.59301758, -0.482421875, -2.59301758, -2.59301758, 2.59301758, 2.59301758,
+			-1.04370117, 0.333984375, -1.79516602, -0.333984375, -0.333984375, 1.04370117, 0.333984375, 0.333984375,
+			1.79516602, 0.333984375, -1.04370117, -0.333984375, -0.333984375, -0.333984375, 1.79516602, 1.04370117,
+			-0.347900391, 0.111328125, 0.111328125, 0.347900391, 0.347900391, -0.598388672, 0.347900391, 0.111328125,
+			0.347900391, 0.598388672, 0.598388672, 0.111328125, 0.598388672, 0.347900391, 0.111328125, 0.598388672,
+			-0.482421875, -1.50756836, -2.59301758, -0.482421875, 0.482421875, 2.59301758, 0.482421875, -1.50756836,
+			-2.59301758, 0.482421875, 0.482421875, 2.59301758, -1.50756836, -1.50756836, 0.482421875, -0.482421875,
+			0.111328125, 0.111328125, 0.111328125, 0.347900391, -0.347900391, -0.347900391, -0.111328125, 0.347900391,
+			0.598388672, -0.111328125, 0.111328125, 0.347900391, 0.347900391, -0.111328125, 0.111328125, 0.111328125,
+			0.811767578, -0.259765625, -0.259765625, -0.259765625, -0.259765625, -0.811767578, 0.259765625, 1.39624023,
+			0.259765625, 0.259765625, 0.259765625, -0.259765625, -0.259765625, 1.39624023, 0.811767578, 0.811767578,
+			4.58764648, 2.66723633, -0.853515625, -0.853515625, 4.58764648, -2.66723633, 0.853515625, 0.853515625,
+			2.66723633, -2.66723633, 0.853515625, -0.853515625, 2.66723633, 0.853515625, -4.58764648, 2.66723633,
+			0.333984375, 0.333984375, -1.04370117, 0.333984375, 1.04370117, -1.04370117, -1.04370117, -1.79516602,
+			0.333984375, -1.04370117, -1.79516602, -0.333984375, -1.04370117, -0.333984375, 0.333984375, -1.79516602,
+			-2.43530273, 0.779296875, 0.779296875, -2.43530273, -2.43530273, 2.43530273, -0.779296875, -0.779296875,
+			4.1887207, 0.779296875, 2.43530273, 0.779296875, -2.43530273, -2.43530273, -0.779296875, -2.43530273,
+			-3.36303711, -1.07617188, -1.07617188, 1.07617188, -5.78442383, -3.36303711, -1.07617188, 1.07617188,
+			-1.07617188, 1.07617188, 5.78442383, 1.07617188, 1.07617188, -5.78442383, 3.36303711, 3.36303711,
+			-1.00195312, -3.13110352, 5.38549805, 1.00195312, -1.00195312, -5.38549805, -1.00195312, 3.13110352,
+			-3.13110352, -3.13110352, 3.13110352, 3.13110352, -1.00195312, -1.00195312, 5.38549805, 1.00195312,
+			-2.99194336, 1.73950195, -1.73950195, -0.556640625, 0.556640625, 0.556640625, 1.73950195, 1.73950195,
+			-0.556640625, 1.73950195, 0.556640625, 1.73950195, 0.556640625, 0.556640625, 0.556640625, -2.99194336,
+			4.98657227, -0.927734375, -4.98657227, -4.98657227, -4.98657227, 0.927734375, 0.927734375, -0.927734375,
+			0.927734375, 2.89916992, -0.927734375, -4.98657227, 0.927734375, -2.89916992, -2.89916992, 2.89916992,
+			1.00195312, -1.00195312, -1.00195312, 1.00195312, -5.38549805, 1.00195312, 1.00195312, 1.00195312,
+			-1.00195312, 3.13110352, -3.13110352, -3.13110352, 3.13110352, -3.13110352, 3.13110352, -1.00195312,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlIQ3_XXS,
+		data: "c0288a34b79c2cfd59f96b846889492d62e19108c44f7e448fe76883883d50cf3e435633fae2773f1d3c21008fb170223e182c4ae4bed9f0772173330d819e6c16ad6b8c2db2fc88e648a34661314bea7ddc3e74f9fea0f4e1fa35e5064439617922",
+		want: []float32{
+			-11.0957031, -2.56054688, 13.2294922, -4.26757812, 7.68164062, -11.0957031, -11.0957031, -0.853515625,
+			0.853515625, 7.68164062, 4.26757812, -7.68164062, -0.853515625, 2.56054688, 4.26757812, 5.97460938,
+			13.2294922, -9.38867188, -7.68164062, 0.853515625, -4.26757812, -0.853515625, 9.38867188, 13.2294922,
+			-0.853515625, 9.38867188, 7.68164062, 2.56054688, -0.853515625, 11.0957031, 5.97460938, 13.2294922,
+			2.33789062, 0.333984375, -1.00195312, -1.66992188, -2.33789062, -0.333984375, -3.67382812, -1.66992188,
+			-1.00195312, 2.33789062, 0.333984375, 1.66992188, -2.33789062, 0.333984375, 5.17675781, 1.66992188,
+			1.66992188, -1.66992188, 1.00195312, -1.00195312, -2.33789062, 5.17675781, 3.00585938, -0.333984375,
+			-1.66992188, -0.333984375, -0.333984375, 1.66992188, 5.17675781, 1.00195312, -1.66992188, 4.34179688,
+			-0.259765625, -2.85742188, 0.259765625, 1.81835938, 0.779296875, -1.81835938, 0.259765625, -0.259765625,
+			-1.29882812, 0.779296875, -4.02636719, -2.33789062, 0.259765625, 1.81835938, 1.29882812, -0.779296875,
+			-0.259765625, 0.779296875, -1.81835938, 1.29882812, 0.259765625, 2.85742188, 0.259765625, 0.779296875,
+			-0.779296875, -1.29882812, 0.259765625, -1.81835938, 1.29882812, 2.85742188, 2.85742188, -3.37695312,
+			-3.00585938, -7.01367188, 1.00195312, -5.00976562, 7.01367188, 11.0214844, -9.01757812, 5.00976562,
+			3.00585938, 1.00195312, -15.5302734, 5.00976562, -5.00976562, 3.00585938, -1.00195312, -3.00585938,
+			-5.00976562, -15.5302734, -5.00976562, 3.00585938, -5.00976562, -15.5302734, -5.00976562, 11.0214844,
+			-3.00585938, -5.00976562, 1.00195312, 3.00585938, 15.5302734, -9.01757812, -1.00195312, 3.00585938,
+			14.9550781, -14.9550781, -8.05273438, -3.45117188, -12.6542969, -8.05273438, 14.9550781, -1.15039062,
+			3.45117188, 5.75195312, 10.3535156, -17.8310547, 10.3535156, -14.9550781, -5.75195312, -14.9550781,
+			-17.8310547, 3.45117188, -5.75195312, 5.75195312, 8.05273438, -5.75195312, -1.15039062, 3.45117188,
+			-3.45117188, -8.05273438, -5.75195312, 1.15039062, -1.15039062, -3.45117188, -1.15039062, 3.45117188,
+			17.8310547, 12.6542969, 5.75195312, 1.15039062, 1.15039062, -1.15039062, 1.15039062, -1.15039062,
+			-3.45117188, 5.75195312, 1.15039062, -8.05273438, 17.8310547, -8.05273438, -1.15039062, 10.3535156,
+			-1.15039062, -8.05273438, -3.45117188, 5.75195312, 12.6542969, 17.8310547, 5.75195312, -1.15039062,
+			-3.45117188, -5.75195312, -1.15039062, 3.45117188, -5.75195312, 1.15039062, -5.75195312, -1.15039062,
+			-5.17675781, 3.67382812, -3.00585938, 0.333984375, -1.00195312, -0.333984375, 1.66992188, 1.00195312,
+			4.34179688, -2.33789062, 2.33789062, -4.34179688, 3.00585938, 4.34179688, -3.00585938, -3.00585938,
+			-3.00585938, -1.66992188, 5.17675781, -3.67382812, -1.66992188, 3.67382812, 0.333984375, 5.17675781,
+			5.17675781, 1.00195312, 1.66992188, 1.66992188, 5.17675781, -3.67382812, 1.66992188, -0.333984375,
+			-0.185546875, 2.87597656, 0.556640625, -0.927734375, -2.04101562, -1.29882812, 2.41210938, 0.185546875,
+			1.29882812, -0.185546875, 0.556640625, 0.185546875, 1.66992188, 2.87597656, -1.29882812, 0.927734375,
+			-0.927734375, 2.04101562, -0.927734375, 1.29882812, 2.04101562, -0.185546875, -0.556640625, 0.927734375,
+			-0.185546875, -2.87597656, 0.556640625, 0.185546875, -0.185546875, 2.41210938, 2.87597656, -1.29882812,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlIQ3_S,
+		data: "c0288a34b79c2cfd59f96b846889492d62e19108c44f7e448fe76883883d50cf3e435633fae2773f1d3c21008fb170223e182c4ae4bed9f0772173330d819e6c16ad6b8c2db2fc88e648a34661314bea7ddc3e74f9fea0f4e1fa35e50644396179227244306bbd735329b6d0e548",
+		want: []float32{
+			-3.37695312, -1.44726562, 6.27148438, 4.34179688, 0.482421875, -2.41210938, 2.41210938, -3.37695312,
+			4.34179688, -2.41210938, -6.27148438, 1.44726562, 1.44726562, 0.482421875, -1.44726562, 5.30664062,
+			-7.23632812, 0.482421875, 2.41210938, 0.482421875, 2.41210938, -0.482421875, -6.27148438, 7.23632812,
+			-2.41210938, 2.41210938, 7.23632812, 3.37695312, -3.37695312, -3.37695312, 3.37695312, 2.41210938,
+			-0.853515625, -9.38867188, 12.8027344, -0.853515625, 7.68164062, 2.56054688, -2.56054688, 2.56054688,
+			5.97460938, -2.56054688, 2.56054688, -7.68164062, 4.26757812, -11.0957031, -9.38867188, -7.68164062,
+			-0.853515625, 0.853515625, -7.68164062, -0.853515625, -0.853515625, -2.56054688, -4.26757812, 0.853515625,
+			4.26757812, 9.38867188, -11.0957031, -0.853515625, -4.26757812, 4.26757812, -0.853515625, -12.8027344,
+			0.111328125, -0.408203125, -0.556640625, -0.333984375, -0.333984375, -0.111328125, 0.037109375, 0.037109375,
+			0.037109375, 0.037109375, -0.111328125, 0.482421875, -0.408203125, -0.185546875, -0.408203125, 0.259765625,
+			-0.185546875, 0.556640625, 0.037109375, -0.111328125, -0.259765625, -0.037109375, -0.333984375, -0.259765625,
+			0.408203125, -0.111328125, -0.185546875, -0.111328125, -0.333984375, -0.037109375, -0.185546875, -0.185546875,
+			5.00976562, 5.00976562, 15.0292969, 1.00195312, 15.0292969, -7.01367188, 9.01757812, -9.01757812,
+			3.00585938, 9.01757812, -3.00585938, 3.00585938, -11.0214844, -3.00585938, -7.01367188, -1.00195312,
+			-5.00976562, 7.01367188, 11.0214844, 7.01367188, 9.01757812, -3.00585938, -7.01367188, -13.0253906,
+			1.00195312, -5.00976562, 7.01367188, -1.00195312, -7.01367188, -15.0292969, -7.01367188, -7.01367188,
+			-2.04101562, 2.04101562, -4.49023438, 0.408203125, -3.67382812, -2.85742188, 2.04101562, 0.408203125,
+			-0.408203125, 4.49023438, -3.67382812, 6.12304688, 5.30664062, -2.85742188, -0.408203125, -6.12304688,
+			2.04101562, -5.30664062, -2.04101562, 3.67382812, 1.22460938, 3.67382812, 2.85742188, 2.85742188,
+			6.12304688, 1.22460938, -0.408203125, 2.85742188, 2.85742188, 2.04101562, -2.85742188, 2.85742188,
+			-7.53320312, 5.38085938, 3.22851562, -1.07617188, -1.07617188, -1.07617188, 1.07617188, 1.07617188,
+			-11.8378906, 3.22851562, 5.38085938, 3.22851562, 3.22851562, -1.07617188, -9.68554688, 11.8378906,
+			-9.68554688, 1.07617188, 1.07617188, -3.22851562, -16.1425781, -5.38085938, -3.22851562, 1.07617188,
+			1.07617188, -5.38085938, 7.53320312, 1.07617188, 1.07617188, -11.8378906, 16.1425781, 5.38085938,
+			9.46289062, -0.630859375, 3.15429688, 0.630859375, -0.630859375, -8.20117188, -5.67773438, 4.41601562,
+			5.67773438, 6.93945312, -0.630859375, 9.46289062, 3.15429688, 1.89257812, -9.46289062, 1.89257812,
+			4.41601562, 9.46289062, 0.630859375, 3.15429688, -0.630859375, -4.41601562, 3.15429688, 9.46289062,
+			-3.15429688, -8.20117188, 3.15429688, -5.67773438, 6.93945312, -4.41601562, -0.630859375, 4.41601562,
+			-2.33789062, 1.00195312, -0.333984375, -1.00195312, -3.00585938, -2.33789062, 1.66992188, -0.333984375,
+			-2.33789062, -2.33789062, 0.333984375, 0.333984375, -1.66992188, -0.333984375, -3.00585938, 3.00585938,
+			-1.00195312, -1.66992188, 2.33789062, 1.00195312, -3.00585938, 3.67382812, -5.00976562, 0.333984375,
+			-0.333984375, 2.33789062, 5.00976562, -1.66992188, 0.333984375, -3.67382812, 3.00585938, 1.00195312,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlIQ1_S,
+		data: "c0288a34b79c2cfd59f96b846889492d62e19108c44f7e448fe76883883d50cf3e435633fae2773f1d3c21008fb170223e18",
+		want: []float32{
+			-0.227294922, 0.0324707031, 0.292236328, 0.292236328, 0.0324707031, -0.227294922, 0.0324707031, 0.292236328,
+			0.292236328, 0.0324707031, -0.227294922, 0.292236328, 0.292236328, 0.0324707031, 0.292236328, -0.227294922,
+			0.0324707031, 0.0324707031, 0.0324707031, 0.0324707031, 0.0324707031, -0.227294922, -0.227294922, 0.292236328,
+			0.0324707031, 0.0324707031, 0.292236328, 0.0324707031, 0.0324707031, 0.292236328, 0.0324707031, -0.227294922,
+			-0.0603027344, -0.0603027344, 0.422119141, -0.542724609, 0.422119141, -0.0603027344, 0.422119141, -0.542724609,
+			0.422119141, -0.542724609, 0.422119141, 0.422119141, 0.422119141, 0.422119141, 0.422119141, 0.422119141,
+			-0.0603027344, -0.542724609, -0.542724609, -0.542724609, -0.542724609, -0.542724609, -0.0603027344, -0.0603027344,
+			0.422119141, -0.542724609, 0.422119141, 0.422119141, 0.422119141, -0.542724609, 0.422119141, -0.542724609,
+			-0.227294922, 0.292236328, 0.0324707031, 0.0324707031, 0.0324707031, -0.227294922, 0.292236328, 0.292236328,
+			-0.227294922, 0.0324707031, -0.227294922, 0.292236328, 0.0324707031, -0.227294922, 0.0324707031, 0.292236328,
+			0.0324707031, 0.0324707031, 0.0324707031, -0.227294922, 0.0324707031, 0.292236328, 0.292236328, 0.0324707031,
+			0.292236328, 0.292236328, 0.292236328, 0.292236328, 0.292236328, -0.227294922, 0.292236328, 0.292236328,
+			-0.227294922, -0.227294922, 0.292236328, 0.0324707031, 0.292236328, 0.0324707031, 0.292236328, 0.0324707031,
+			0.292236328, 0.0324707031, -0.227294922, -0.227294922, 0.0324707031, 0.292236328, -0.227294922, 0.0324707031,
+			0.0324707031, -0.227294922, 0.0324707031, 0.0324707031, 0.0324707031, 0.0324707031, -0.227294922, -0.227294922,
+			0.0324707031, 0.0324707031, 0.0324707031, 0.292236328, 0.0324707031, 0.0324707031, 0.0324707031, 0.292236328,
+			0.0417480469, -0.0324707031, -0.0324707031, 0.00463867188, 0.00463867188, 0.0417480469, 0.00463867188, -0.0324707031,
+			0.00463867188, 0.0417480469, 0.00463867188, 0.00463867188, 0.00463867188, 0.00463867188, 0.00463867188, 0.00463867188,
+			-0.0324707031, 0.0417480469, 0.0417480469, 0.0417480469, 0.0417480469, 0.0417480469, -0.0324707031, -0.0324707031,
+			0.00463867188, 0.00463867188, 0.00463867188, 0.00463867188, -0.0324707031, 0.00463867188, -0.0324707031, -0.0324707031,
+			0.227294922, -0.0324707031, -0.0324707031, -0.0324707031, 0.227294922, -0.292236328, 0.227294922, 0.227294922,
+			0.227294922, -0.0324707031, 0.227294922, -0.0324707031, -0.0324707031, -0.0324707031, -0.0324707031, -0.292236328,
+			-0.292236328, -0.0324707031, -0.0324707031, -0.292236328, 0.227294922, -0.292236328, -0.0324707031, 0.227294922,
+			-0.292236328, -0.292236328, -0.0324707031, -0.0324707031, -0.0324707031, -0.292236328, -0.0324707031, -0.292236328,
+			0.208740234, -0.162353516, 0.208740234, 0.0231933594, 0.0231933594, 0.0231933594, -0.162353516, -0.162353516,
+			0.0231933594, 0.0231933594, 0.208740234, 0.0231933594, 0.0231933594, -0.162353516, 0.0231933594, 0.208740234,
+			0.0231933594, -0.162353516, 0.0231933594, 0.208740234, -0.162353516, 0.208740234, 0.0231933594, -0.162353516,
+			0.0231933594, 0.0231933594, 0.0231933594, 0.0231933594, 0.0231933594, 0.0231933594, 0.0231933594, -0.162353516,
+			0.125244141, 0.0139160156, 0.0139160156, 0.0139160156, 0.125244141, 0.125244141, -0.0974121094, 0.125244141,
+			-0.0974121094, 0.125244141, 0.125244141, -0.0974121094, -0.0974121094, 0.125244141, 0.125244141, 0.125244141,
+			-0.0974121094, -0.0974121094, -0.0974121094, 0.125244141, 0.125244141, -0.0974121094, -0.0974121094, -0.0974121094,
+			-0.0974121094, 0.125244141, 0.0139160156, 0.0139160156, 0.125244141, 0.0139160156, 0.0139160156, 0.0139160156,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlIQ1_M,
+		data: "a5278a34b79c2cfd59f96b846889492d62e19108c44f7e448fe76883883d50cf3e435633fae2773f1d3c21008fb170223e082ccae48ed920",
+		want: []float32{
+			0.422119141, -0.0603027344, 0.422119141, -0.542724609, -0.542724609, -0.0603027344, -0.0603027344, 0.422119141,
+			-0.422119141, 0.0603027344, 0.0603027344, 0.542724609, -0.422119141, 0.542724609, -0.422119141, 0.0603027344,
+			-0.487060547, -0.487060547, 0.0695800781, 0.0695800781, 0.0695800781, -0.487060547, 0.0695800781, 0.0695800781,
+			0.626220703, 0.0695800781, 0.626220703, -0.487060547, 0.626220703, 0.0695800781, 0.0695800781, 0.0695800781,
+			-0.0324707031, 0.0417480469, 0.00463867188, 0.0417480469, -0.0324707031, 0.00463867188, 0.00463867188, 0.0417480469,
+			0.00463867188, 0.00463867188, -0.0324707031, 0.00463867188, -0.0324707031, -0.0324707031, -0.0324707031, 0.0417480469,
+			0.0417480469, 0.0417480469, -0.292236328, -0.292236328, 0.0417480469, 0.375732422, -0.292236328, 0.0417480469,
+			0.0417480469, 0.0417480469, -0.292236328, 0.0417480469, 0.0417480469, 0.0417480469, 0.0417480469, 0.0417480469,
+			-0.0417480469, -0.0417480469, -0.375732422, 0.292236328, -0.0417480469, 0.292236328, 0.292236328, -0.375732422,
+			-0.375732422, 0.292236328, -0.375732422, 0.292236328, 0.292236328, 0.292236328, 0.292236328, 0.292236328,
+			0.0510253906, 0.0510253906, 0.459228516, 0.0510253906, 0.459228516, 0.459228516, 0.459228516, -0.357177734,
+			-0.459228516, -0.0510253906, -0.459228516, 0.357177734, -0.0510253906, -0.459228516, -0.0510253906, 0.357177734,
+			0.00463867188, 0.0417480469, -0.0324707031, 0.00463867188, 0.00463867188, -0.0324707031, 0.0417480469, 0.0417480469,
+			0.0417480469, 0.0417480469, 0.0417480469, 0.0417480469, 0.0417480469, -0.0324707031, 0.0417480469, 0.0417480469,
+			-0.459228516, -0.459228516, -0.459228516, -0.459228516, -0.459228516, -0.459228516, 0.357177734, 0.357177734,
+			0.459228516, 0.0510253906, -0.357177734, -0.357177734, 0.0510253906, 0.459228516, -0.357177734, 0.0510253906,
+			-0.0417480469, -0.375732422, -0.0417480469, 0.292236328, -0.375732422, 0.292236328, 0.292236328, -0.0417480469,
+			-0.292236328, -0.292236328, 0.0417480469, 0.375732422, 0.0417480469, -0.292236328, 0.375732422, -0.292236328,
+			0.292236328, -0.0417480469, 0.292236328, -0.0417480469, -0.0417480469, 0.292236328, -0.0417480469, -0.0417480469,
+			0.375732422, -0.292236328, 0.0417480469, 0.0417480469, 0.375732422, 0.0417480469, -0.292236328, 0.0417480469,
+			0.0324707031, 0.292236328, 0.0324707031, 0.0324707031, -0.227294922, -0.227294922, 0.292236328, -0.227294922,
+			0.0324707031, 0.292236328, 0.0324707031, -0.227294922, 0.0324707031, 0.292236328, 0.292236328, -0.227294922,
+			-0.487060547, -0.487060547, 0.626220703, 0.0695800781, 0.626220703, 0.0695800781, -0.487060547, -0.487060547,
+			0.0695800781, 0.626220703, 0.0695800781, 0.626220703, 0.626220703, -0.487060547, -0.487060547, -0.487060547,
+			-0.125244141, -0.0139160156, -0.0139160156, -0.0139160156, -0.125244141, -0.0139160156, 0.0974121094, 0.0974121094,
+			-0.125244141, -0.125244141, -0.0139160156, -0.0139160156, -0.0139160156, -0.125244141, -0.0139160156, -0.125244141,
+			0.292236328, 0.0324707031, 0.0324707031, 0.0324707031, 0.292236328, 0.0324707031, 0.0324707031, -0.227294922,
+			-0.292236328, 0.227294922, 0.227294922, -0.292236328, -0.0324707031, -0.292236328, -0.0324707031, -0.0324707031,
+			-0.227294922, 0.292236328, -0.227294922, -0.227294922, -0.227294922, 0.292236328, -0.227294922, -0.227294922,
+			-0.227294922, 0.0324707031, -0.227294922, 0.0324707031, 0.292236328, 0.292236328, 0.0324707031, 0.292236328,
+			0.00463867188, 0.00463867188, 0.0417480469, -0.0324707031, 0.00463867188, 0.0417480469, 0.0417480469, -0.0324707031,
+			0.0417480469, -0.0324707031, -0.0324707031, -0.0324707031, 0.00463867188, 0.00463867188, -0.0324707031, 0.00463867188,
+		},
+	},
+	{
+		name: "quantized",
+		typ:  GgmlIQ4_NL,
+		data: "e3a4edb7c1c36ad1a59abf633e121703f481",
+		want: []float32{
+			-1.31712341, 0.190887451, 1.98522949, 1.24076843, -0.477218628, 1.98522949, 0.668106079, -0.477218628,
+			-2.1570282, 1.24076843, -1.69889832, 1.58436584, 0.190887451, 1.24076843, 0.935348511, 1.98522949,
+			-1.69889832, -0.725372314, -1.01170349, -1.01170349, 0.419952393, -1.31712341, -0.477218628, -0.248153687,
+			-0.725372314, 0.419952393, 1.24076843, 1.98522949, 1.98522949, 2.42427063, -2.1570282, -0.0190887451,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlIQ4_NL,
+		data: "c0288a34b79c2cfd59f96b846889492d62e1",
+		want: []float32{
+			0.927734375, -1.81835938, -0.37109375, 1.96679688, 1.96679688, 2.56054688, 0.482421875, 0.482421875,
+			1.41015625, -1.81835938, 0.037109375, 0.482421875, 0.482421875, 2.56054688, -3.08007812, -3.859375,
+			0.037109375, -2.41210938, 1.41015625, 0.482421875, -3.08007812, 4.19335938, -1.29882812, 4.19335938,
+			-0.81640625, 0.037109375, -0.81640625, 0.037109375, -1.81835938, -3.08007812, -0.81640625, 3.30273438,
+		},
+	},
+	{
+		name: "quantized",
+		typ:  GgmlIQ4_XS,
+		data: "2111ccf3f2d01f9fedb7c1c26ad1a59abf632e121703f48123a2c2d612db6be0462df3681f8224fbe93d39c8d79e922a0a5cfcf56bf2911aafaf41d71ae45e0e85c49ffc4c194a1010a18f2f6f6fa1cebc3edfe04bf415154b2e20230952f7621ead23469024cb6a59f51a235de84de6953bcbd3b4b94e308b265a1e6e069d3e6d0d1e38d1c1f1d0",
+		want: []float32{
+			-1.29600048, 0.187826157, 1.95339203, 1.5589571, -0.469565392, 1.95339203, 0.657391548, -0.469565392,
+			-2.12243557, 1.22087002, -1.67165279, 1.5589571, 0.187826157, 1.22087002, 0.920348167, 1.95339203,
+			-1.67165279, -0.713739395, -0.99547863, -0.99547863, 0.413217545, -1.29600048, -0.469565392, -0.244174004,
+			-0.713739395, 0.413217545, 1.5589571, 1.95339203, 1.95339203, 2.38539219, -2.12243557, -0.0187826157,
+			-1.26156569, -1.61092234, -1.61092234, -0.426991463, -1.61092234, 0.737530708, 0.737530708, -2.46490526,
+			-0.426991463, 1.3392005, -1.26156569, 0.0194087029, 2.19318342, -1.61092234, -0.95102644, 0.737530708,
+			-1.61092234, 0.485217571, 1.02866125, 1.3392005, -2.0185051, 1.3392005, -0.426991463, 1.72737455,
+			-0.95102644, -1.61092234, 2.19318342, -0.426991463, -2.0185051, 0.0194087029, -1.61092234, 2.19318342,
+			-0.260452271, -1.38240051, -0.260452271, -0.02003479, 0.2003479, -1.78309631, 1.66288757, -0.500869751,
+			-0.500869751, -1.06184387, -1.06184387, 0.701217651, -0.761322021, 1.66288757, 2.08361816, -0.500869751,
+			-1.78309631, 1.30226135, 1.30226135, -1.06184387, -1.38240051, -0.260452271, -0.260452271, 1.66288757,
+			2.54441833, 0.701217651, -2.26393127, -2.26393127, 0.440765381, -2.26393127, -0.260452271, 2.08361816,
+			2.05168772, 2.05168772, -1.88827896, -0.181565285, 0.453913212, -0.889669895, 1.61593103, 1.61593103,
+			-0.635478497, -0.889669895, 2.05168772, 0.962296009, 0.962296009, 0.23603487, 0.453913212, -2.30587912,
+			0.453913212, 0.453913212, -0.889669895, 1.25280046, -1.88827896, 1.61593103, -0.635478497, -2.30587912,
+			0.0181565285, 0.962296009, 0.23603487, 2.05168772, -0.889669895, -1.88827896, -0.889669895, -1.88827896,
+			-2.46490526, -2.0185051, 2.19318342, 2.19318342, 2.19318342, 2.19318342, -2.0185051, 1.72737455,
+			1.02866125, 1.72737455, 2.19318342, -2.46490526, 0.737530708, -0.95102644, -0.6793046, -0.6793046,
+			-2.0185051, 0.485217571, 0.0194087029, -1.61092234, -0.426991463, -0.426991463, 0.485217571, 1.02866125,
+			0.737530708, -1.26156569, 1.3392005, 1.72737455, -0.95102644, 2.19318342, -2.0185051, -2.0185051,
+			-0.737530708, -1.72737455, 2.46490526, 1.26156569, -0.252313137, 1.61092234, 0.194087029, 1.61092234,
+			-1.72737455, -1.3392005, 1.26156569, 0.426991463, 2.46490526, 0.95102644, -0.737530708, -0.485217571,
+			0.95102644, 1.61092234, 1.61092234, 1.61092234, 2.46490526, 0.6793046, -2.19318342, 0.426991463,
+			2.0185051, -0.485217571, 1.61092234, 0.95102644, -0.252313137, 1.61092234, -1.02866125, 0.426991463,
+			0.252313137, -0.6793046, 0.485217571, -1.26156569, 1.3392005, 0.0194087029, 1.3392005, -0.426991463,
+			-0.6793046, 0.737530708, 0.737530708, -1.26156569, -0.95102644, 0.252313137, 1.72737455, -2.46490526,
+			-0.6793046, 2.19318342, -2.0185051, -1.61092234, -0.6793046, 1.72737455, -0.95102644, 1.72737455,
+			0.252313137, -1.26156569, 1.02866125, 1.3392005, 0.737530708, 0.737530708, -0.95102644, -1.26156569,
+			0.594782829, -0.344347954, 0.391304493, 1.39304399, 1.39304399, -0.344347954, 1.0800004, 1.39304399,
+			1.0800004, 1.0800004, 1.39304399, 0.0156521797, -1.62782669, -1.62782669, -1.62782669, -1.98782682,
+			0.0156521797, -1.29913092, -0.54782629, -1.62782669, -0.344347954, -1.98782682, 0.203478336, -1.01739168,
+			-0.344347954, -1.98782682, -1.62782669, -1.01739168, 1.0800004, 0.829565525, 1.76869631, 1.0800004,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlIQ4_XS,
+		data: "c0288a34b79c2cfd59f96b846889492d62e19108c44f7e448fe76883883d50cf3e435633fae2773f1d3c21008fb170223e182c4ae4bed9f0772173330d819e6c16ad6b8c2db2fc88e648a34661314bea7ddc3e74f9fea0f4e1fa35e50644396179227244306bbd735329b6d0e5484aabb3a94a9ccf51511ef67f8e68a3e74c48c55e490339af3380",
+		want: []float32{
+			3.37695312, 3.37695312, 9.87109375, -12.7285156, 0.259765625, 3.37695312, 3.37695312, 17.9238281,
+			-21.5605469, -27.015625, -27.015625, 0.259765625, -12.7285156, 29.3535156, 23.1191406, -12.7285156,
+			-9.09179688, 29.3535156, -5.71484375, 0.259765625, -5.71484375, 0.259765625, -12.7285156, -21.5605469,
+			-5.71484375, 23.1191406, 3.37695312, -32.9902344, 13.7675781, -12.7285156, -2.59765625, -12.7285156,
+			46.1269531, -4.08203125, 0.408203125, -26.5332031, 0.408203125, 28.1660156, -51.8417969, 46.1269531,
+			36.3300781, -26.5332031, -8.98046875, -26.5332031, 10.2050781, -33.8808594, -4.08203125, 46.1269531,
+			0.408203125, 36.3300781, -8.98046875, 0.408203125, 0.408203125, -26.5332031, -14.2871094, 21.6347656,
+			-26.5332031, -20.0019531, -14.2871094, -26.5332031, 46.1269531, 36.3300781, -4.08203125, -26.5332031,
+			-51.2109375, -39.3359375, 77.1875, 94.2578125, -83.8671875, 77.1875, 94.2578125, 61.6015625,
+			-66.0546875, -0.7421875, -39.3359375, -18.5546875, 36.3671875, -66.0546875, -9.6484375, 94.2578125,
+			77.1875, 48.2421875, 61.6015625, 94.2578125, -0.7421875, -28.203125, 7.421875, 61.6015625,
+			48.2421875, 77.1875, 61.6015625, 36.3671875, -66.0546875, -28.203125, -51.2109375, -83.8671875,
+			-3.33984375, -34.734375, -21.7089844, -21.7089844, 23.0449219, -34.734375, 29.7246094, 17.7011719,
+			-7.34765625, 23.0449219, 12.6914062, 17.7011719, 23.0449219, -27.7207031, 17.7011719, 0.333984375,
+			-3.33984375, -27.7207031, -3.33984375, -21.7089844, -42.4160156, 0.333984375, 4.34179688, -7.34765625,
+			-34.734375, 8.34960938, -7.34765625, 0.333984375, -27.7207031, 12.6914062, 37.7402344, 0.333984375,
+			16.328125, -0.7421875, 48.2421875, 16.328125, 77.1875, 77.1875, -28.203125, -18.5546875,
+			-51.2109375, -39.3359375, -66.0546875, 36.3671875, -9.6484375, -66.0546875, 94.2578125, 36.3671875,
+			-66.0546875, 36.3671875, -18.5546875, 36.3671875, 16.328125, 48.2421875, 36.3671875, -66.0546875,
+			7.421875, -51.2109375, 48.2421875, 7.421875, -83.8671875, -83.8671875, -18.5546875, -83.8671875,
+			54.03125, -12.9882812, 18.1835938, 18.1835938, 11.4296875, 25.4570312, -6.75390625, 54.03125,
+			-6.75390625, 43.1210938, 43.1210938, 25.4570312, 65.9804688, -19.7421875, -35.8476562, 33.7695312,
+			-46.2382812, -58.7070312, 33.7695312, -46.2382812, 65.9804688, 25.4570312, 33.7695312, 11.4296875,
+			5.1953125, 43.1210938, 5.1953125, 25.4570312, 33.7695312, 11.4296875, -19.7421875, 5.1953125,
+			-69.9511719, 13.9902344, -23.6757812, -136.673828, -37.6660156, 1.07617188, 26.9042969, 40.8945312,
+			-69.9511719, 13.9902344, 26.9042969, 57.0371094, 121.607422, -111.921875, -111.921875, 95.7792969,
+			-37.6660156, -89.3222656, 40.8945312, 74.2558594, 95.7792969, -52.7324219, -52.7324219, 26.9042969,
+			40.8945312, 26.9042969, -52.7324219, 13.9902344, 57.0371094, -37.6660156, -37.6660156, -111.921875,
+			13.8789062, -71.2871094, -56.1464844, -0.630859375, 41.0058594, 6.30859375, -33.4355469, -0.630859375,
+			22.0800781, -56.1464844, -8.20117188, 41.0058594, -8.20117188, -71.2871094, 41.0058594, 80.1191406,
+			-71.2871094, 6.30859375, -0.630859375, 13.8789062, -15.7714844, -56.1464844, 30.9121094, 30.9121094,
+			-33.4355469, 22.0800781, 30.9121094, 80.1191406, 41.0058594, -15.7714844, 41.0058594, -0.630859375,
+		},
+	},
+}
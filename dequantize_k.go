@@ -0,0 +1,262 @@
+package gguf
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// scaleMinK4 returns the 6-bit scale and min number j packed in the
+// kScaleSize bytes of q, as used by Q4_K and Q5_K.
+func scaleMinK4(j int, q []byte) (uint8, uint8) {
+	if j < 4 {
+		return q[j] & 63, q[j+4] & 63
+	}
+
+	d := (q[j+4] & 0x0f) | ((q[j-4] >> 6) << 4)
+	m := (q[j+4] >> 4) | ((q[j] >> 6) << 4)
+
+	return d, m
+}
+
+func dequantizeQ2_K(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlQ2_K].blocksize)
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK_K : (i+1)*qK_K]
+
+		scales := b[:qK_K/16]
+		q := b[qK_K/16 : qK_K/16+qK_K/4]
+		d := half(b[qK_K/16+qK_K/4:], byteOrder)
+		min := half(b[qK_K/16+qK_K/4+2:], byteOrder)
+
+		is := 0
+		k := 0
+
+		for n := 0; n < qK_K; n += 128 {
+			shift := 0
+
+			for j := 0; j < 4; j++ {
+				for h := 0; h < 2; h++ {
+					sc := scales[is]
+					is++
+
+					dl := d * float32(sc&0x0f)
+					ml := min * float32(sc>>4)
+
+					for l := 0; l < 16; l++ {
+						y[k] = dl*float32((q[l+16*h]>>shift)&3) - ml
+						k++
+					}
+				}
+
+				shift += 2
+			}
+
+			q = q[32:]
+		}
+	}
+}
+
+func dequantizeQ3_K(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	const kmask1 = 0x03030303
+	const kmask2 = 0x0f0f0f0f
+
+	blocksize := int(sizes[GgmlQ3_K].blocksize)
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK_K : (i+1)*qK_K]
+
+		hm := b[:qK_K/8]
+		q := b[qK_K/8 : qK_K/8+qK_K/4]
+		packed := b[qK_K/8+qK_K/4 : qK_K/8+qK_K/4+kScaleSize]
+		dAll := half(b[qK_K/8+qK_K/4+kScaleSize:], byteOrder)
+
+		var aux [4]uint32
+
+		aux[0] = binary.LittleEndian.Uint32(packed[0:])
+		aux[1] = binary.LittleEndian.Uint32(packed[4:])
+		tmp := binary.LittleEndian.Uint32(packed[8:])
+
+		aux[2] = ((aux[0] >> 4) & kmask2) | (((tmp >> 4) & kmask1) << 4)
+		aux[3] = ((aux[1] >> 4) & kmask2) | (((tmp >> 6) & kmask1) << 4)
+		aux[0] = (aux[0] & kmask2) | (((tmp >> 0) & kmask1) << 4)
+		aux[1] = (aux[1] & kmask2) | (((tmp >> 2) & kmask1) << 4)
+
+		var scales [16]int8
+
+		for j := range scales {
+			scales[j] = int8(aux[j/4] >> (8 * (j % 4)))
+		}
+
+		is := 0
+		k := 0
+		m := uint8(1)
+
+		for n := 0; n < qK_K; n += 128 {
+			shift := 0
+
+			for j := 0; j < 4; j++ {
+				for h := 0; h < 2; h++ {
+					dl := dAll * float32(scales[is]-32)
+					is++
+
+					for l := 16 * h; l < 16*(h+1); l++ {
+						v := int8((q[l] >> shift) & 3)
+						if hm[l]&m == 0 {
+							v -= 4
+						}
+
+						y[k] = dl * float32(v)
+						k++
+					}
+				}
+
+				shift += 2
+				m <<= 1
+			}
+
+			q = q[32:]
+		}
+	}
+}
+
+func dequantizeQ4_K(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlQ4_K].blocksize)
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK_K : (i+1)*qK_K]
+
+		d := half(b, byteOrder)
+		min := half(b[2:], byteOrder)
+		scales := b[4 : 4+kScaleSize]
+		q := b[4+kScaleSize:]
+
+		is := 0
+		k := 0
+
+		for j := 0; j < qK_K; j += 64 {
+			sc, m := scaleMinK4(is, scales)
+			d1 := d * float32(sc)
+			m1 := min * float32(m)
+
+			sc, m = scaleMinK4(is+1, scales)
+			d2 := d * float32(sc)
+			m2 := min * float32(m)
+
+			for l := 0; l < 32; l++ {
+				y[k+l] = d1*float32(q[l]&0x0f) - m1
+				y[k+l+32] = d2*float32(q[l]>>4) - m2
+			}
+
+			q = q[32:]
+			is += 2
+			k += 64
+		}
+	}
+}
+
+func dequantizeQ5_K(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlQ5_K].blocksize)
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK_K : (i+1)*qK_K]
+
+		d := half(b, byteOrder)
+		min := half(b[2:], byteOrder)
+		scales := b[4 : 4+kScaleSize]
+		qh := b[4+kScaleSize : 4+kScaleSize+qK_K/8]
+		ql := b[4+kScaleSize+qK_K/8:]
+
+		is := 0
+		k := 0
+		u1 := uint8(1)
+		u2 := uint8(2)
+
+		for j := 0; j < qK_K; j += 64 {
+			sc, m := scaleMinK4(is, scales)
+			d1 := d * float32(sc)
+			m1 := min * float32(m)
+
+			sc, m = scaleMinK4(is+1, scales)
+			d2 := d * float32(sc)
+			m2 := min * float32(m)
+
+			for l := 0; l < 32; l++ {
+				h1 := uint8(0)
+				if qh[l]&u1 != 0 {
+					h1 = 16
+				}
+
+				h2 := uint8(0)
+				if qh[l]&u2 != 0 {
+					h2 = 16
+				}
+
+				y[k+l] = d1*float32(ql[l]&0x0f+h1) - m1
+				y[k+l+32] = d2*float32(ql[l]>>4+h2) - m2
+			}
+
+			ql = ql[32:]
+			is += 2
+			k += 64
+			u1 <<= 2
+			u2 <<= 2
+		}
+	}
+}
+
+func dequantizeQ6_K(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlQ6_K].blocksize)
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK_K : (i+1)*qK_K]
+
+		ql := b[:qK_K/2]
+		qh := b[qK_K/2 : qK_K/2+qK_K/4]
+		sc := b[qK_K/2+qK_K/4 : qK_K/2+qK_K/4+qK_K/16]
+		d := half(b[qK_K/2+qK_K/4+qK_K/16:], byteOrder)
+
+		for n := 0; n < qK_K; n += 128 {
+			for l := 0; l < 32; l++ {
+				is := l / 16
+
+				q1 := int8((ql[l]&0x0f)|((qh[l]>>0)&3)<<4) - 32
+				q2 := int8((ql[l+32]&0x0f)|((qh[l]>>2)&3)<<4) - 32
+				q3 := int8((ql[l]>>4)|((qh[l]>>4)&3)<<4) - 32
+				q4 := int8((ql[l+32]>>4)|((qh[l]>>6)&3)<<4) - 32
+
+				y[n+l] = d * float32(int8(sc[is])) * float32(q1)
+				y[n+l+32] = d * float32(int8(sc[is+2])) * float32(q2)
+				y[n+l+64] = d * float32(int8(sc[is+4])) * float32(q3)
+				y[n+l+96] = d * float32(int8(sc[is+6])) * float32(q4)
+			}
+
+			ql = ql[64:]
+			qh = qh[32:]
+			sc = sc[8:]
+		}
+	}
+}
+
+func dequantizeQ8_K(dst []float32, src []byte, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlQ8_K].blocksize)
+
+	for i := 0; i < len(src)/blocksize; i++ {
+		b := src[i*blocksize : (i+1)*blocksize]
+		y := dst[i*qK_K : (i+1)*qK_K]
+
+		// The block sums following the values are only used for
+		// dot products.
+		d := math.Float32frombits(byteOrder.Uint32(b))
+		qs := b[4 : 4+qK_K]
+
+		for j := 0; j < qK_K; j++ {
+			y[j] = d * float32(int8(qs[j]))
+		}
+	}
+}
@@ -0,0 +1,600 @@
+package gguf
+
+import (
+	"testing"
+)
+
+func TestDequantizeK(t *testing.T) {
+	testDequantize(t, dequantizeKCases)
+}
+
+// The "high scale bits" blocks set the upper two bits of every packed
+// 6-bit scale, covering the Q3_K scale unpacking and scaleMinK4 for
+// j >= 4.
+var dequantizeKCases = []dequantizeCase{
+	{
+		name: "quantized",
+		typ:  GgmlQ2_K,
+		data: "a9ce7aeeeebdecfcdcccffed8ab9fecec06a6c9773ec88be8aefbb8cb2c33e6d9ce5ab2db3d22ac594b8d8fcd4cdb1bda3ffc7caae7fc8fd5855b82b6d9dea475347ef75dc5f1b458b6ba4346e763440872e3831",
+		want: []float32{
+			-1.63085938, 0.204956055, -1.63085938, 1.12286377, 1.12286377, -1.63085938, -1.63085938, 0.204956055,
+			0.204956055, 1.12286377, 1.12286377, -1.63085938, 0.204956055, 1.12286377, 0.204956055, -0.71295166,
+			-1.95703125, -0.529174805, 2.32653809, -0.529174805, 2.32653809, 0.898681641, 0.898681641, -0.529174805,
+			-1.95703125, -1.95703125, -1.95703125, -1.95703125, -1.95703125, -0.529174805, -0.529174805, -0.529174805,
+			-1.14160156, 0.898193359, 1.91809082, -0.121704102, -1.14160156, 1.91809082, 0.898193359, 1.91809082,
+			0.898193359, 1.91809082, 0.898193359, 1.91809082, -1.14160156, -1.14160156, 1.91809082, 1.91809082,
+			2.00036621, -0.85534668, 0.572509766, 2.00036621, -2.28320312, -2.28320312, 0.572509766, -0.85534668,
+			-0.85534668, 0.572509766, 0.572509766, 2.00036621, -0.85534668, 2.00036621, -2.28320312, 2.00036621,
+			-2.28320312, 0.572509766, 0.572509766, -0.85534668, 2.00036621, 0.572509766, -2.28320312, 2.00036621,
+			-2.28320312, 0.572509766, 2.00036621, -2.28320312, 2.00036621, -2.28320312, 2.00036621, 0.572509766,
+			-0.468078613, 0.857788086, 0.857788086, 0.857788086, 2.18365479, -0.468078613, 0.857788086, -1.79394531,
+			-0.468078613, 2.18365479, -0.468078613, 2.18365479, -0.468078613, -1.79394531, 2.18365479, 2.18365479,
+			1.38842773, -1.05932617, -1.05932617, 0.164550781, -1.05932617, 1.38842773, 0.164550781, 0.164550781,
+			0.164550781, 1.38842773, 0.164550781, 0.164550781, 0.164550781, 1.38842773, -2.28320312, -1.05932617,
+			0.00146484375, 1.2253418, 0.00146484375, -2.44628906, 0.00146484375, 1.2253418, -2.44628906, 1.2253418,
+			0.00146484375, 0.00146484375, 1.2253418, 1.2253418, 1.2253418, 1.2253418, 0.00146484375, 0.00146484375,
+			1.55151367, 1.55151367, 1.55151367, 0.327636719, 0.327636719, 1.55151367, -2.12011719, -0.896240234,
+			-2.12011719, -0.896240234, -2.12011719, 1.55151367, -0.896240234, -0.896240234, 0.327636719, 1.55151367,
+			1.71459961, 1.71459961, 1.71459961, -0.733154297, -1.95703125, 1.71459961, 1.71459961, -0.733154297,
+			1.71459961, 1.71459961, -1.95703125, -1.95703125, 0.490722656, 0.490722656, -1.95703125, -1.95703125,
+			-2.44628906, 2.14324951, -0.916442871, 0.61340332, 2.14324951, 2.14324951, 0.61340332, 2.14324951,
+			0.61340332, -0.916442871, 0.61340332, 0.61340332, 2.14324951, 2.14324951, 0.61340332, -0.916442871,
+			-2.28320312, -0.957336426, 1.69439697, -0.957336426, 1.69439697, 1.69439697, 0.368530273, -0.957336426,
+			0.368530273, 0.368530273, -0.957336426, -0.957336426, 1.69439697, -0.957336426, -0.957336426, -2.28320312,
+			0.735107422, 1.75500488, -1.3046875, -1.3046875, 0.735107422, 1.75500488, -1.3046875, 1.75500488,
+			-0.284790039, -0.284790039, 1.75500488, 0.735107422, 0.735107422, -0.284790039, 0.735107422, -1.3046875,
+			-0.876037598, -1.79394531, 0.0418701172, 0.959777832, -0.876037598, -0.876037598, -0.876037598, -1.79394531,
+			-1.79394531, 0.0418701172, 0.0418701172, 0.959777832, 0.0418701172, 0.959777832, 0.959777832, -1.79394531,
+			0.409423828, 1.83728027, 1.83728027, 1.83728027, 0.409423828, -1.01843262, 1.83728027, 1.83728027,
+			-1.01843262, -1.01843262, 0.409423828, -2.44628906, -1.01843262, 0.409423828, 1.83728027, -1.01843262,
+			-0.529174805, -0.529174805, 2.32653809, -0.529174805, 2.32653809, -0.529174805, -1.95703125, -0.529174805,
+			0.898681641, -0.529174805, 0.898681641, -1.95703125, -0.529174805, -0.529174805, -1.95703125, -0.529174805,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlQ2_K,
+		data: "a510db2b0ddcbd12d7eaf6d56e960eb63add452513bfd2327176cfbfb99fb9a847a7f0471f0f911a62589272f4b20b64f2befa6ed7f60e878090258e67f29e44c305b15267064f048c9d5fb5ea564916c0284ca2",
+		want: []float32{
+			0.49407959, 0.308532715, 0.308532715, 0.308532715, 0.679626465, 0.679626465, 0.49407959, 0.49407959,
+			0.308532715, 0.49407959, 0.679626465, 0.679626465, 0.308532715, 0.679626465, 0.308532715, 0.12298584,
+			0.012298584, 0.012298584, 0.012298584, 0.012298584, 0.012298584, 0.012298584, 0.012298584, 0.012298584,
+			0.012298584, 0.012298584, 0.012298584, 0.012298584, 0.012298584, 0.012298584, 0.012298584, 0.012298584,
+			0.976287842, 1.38449097, 0.568084717, 0.568084717, 0.159881592, 1.38449097, 0.159881592, 0.159881592,
+			0.159881592, 0.568084717, 1.38449097, 1.38449097, 0.976287842, 1.38449097, 0.976287842, 0.976287842,
+			0.432800293, 0.432800293, 0.024597168, 0.432800293, 1.24920654, 1.24920654, 0.024597168, 0.841003418,
+			0.024597168, 0.841003418, 0.024597168, 0.024597168, 0.432800293, 0.024597168, 0.841003418, 0.432800293,
+			1.44726562, 0.482421875, 0, 0.96484375, 0.482421875, 1.44726562, 0.482421875, 1.44726562,
+			1.44726562, 1.44726562, 0, 1.44726562, 1.44726562, 0.482421875, 1.44726562, 0.96484375,
+			0.159881592, 1.05050659, 1.49581909, 0.159881592, 0.605194092, 0.159881592, 0.605194092, 0.605194092,
+			1.05050659, 0.605194092, 0.605194092, 1.49581909, 1.49581909, 1.49581909, 0.159881592, 1.05050659,
+			0.135284424, 1.58255005, 0.617706299, 0.135284424, 0.135284424, 1.10012817, 1.58255005, 0.135284424,
+			0.617706299, 0.617706299, 1.58255005, 1.10012817, 1.10012817, 1.10012817, 1.10012817, 1.10012817,
+			0.086517334, 0.160736084, 0.234954834, 0.086517334, 0.012298584, 0.012298584, 0.160736084, 0.012298584,
+			0.086517334, 0.086517334, 0.160736084, 0.086517334, 0.234954834, 0.160736084, 0.012298584, 0.086517334,
+			0.679412842, 0.679412842, 0.679412842, 0.679412842, 0.939178467, 0.679412842, 0.679412842, 0.939178467,
+			0.159881592, 0.159881592, 0.419647217, 0.679412842, 0.939178467, 0.679412842, 0.679412842, 0.159881592,
+			1.28546143, 0.543273926, 0.543273926, 0.914367676, 1.28546143, 0.914367676, 1.28546143, 0.172180176,
+			0.172180176, 0.543273926, 1.28546143, 0.543273926, 0.914367676, 0.914367676, 0.543273926, 0.914367676,
+			0.18447876, 0.85244751, 0.62979126, 0.85244751, 0.40713501, 0.40713501, 0.85244751, 0.40713501,
+			0.18447876, 0.18447876, 0.40713501, 0.85244751, 0.40713501, 0.18447876, 0.85244751, 0.40713501,
+			0.159881592, 0.345428467, 0.159881592, 0.159881592, 0.345428467, 0.345428467, 0.716522217, 0.345428467,
+			0.716522217, 0.716522217, 0.716522217, 0.345428467, 0.530975342, 0.345428467, 0.530975342, 0.345428467,
+			1.63238525, 1.63238525, 1.63238525, 1.112854, 0.593322754, 1.63238525, 0.0737915039, 0.0737915039,
+			0.0737915039, 0.593322754, 1.112854, 0.0737915039, 1.112854, 1.63238525, 0.593322754, 0.0737915039,
+			0.110687256, 0.110687256, 0.778656006, 0.333343506, 0.555999756, 0.110687256, 0.110687256, 0.110687256,
+			0.110687256, 0.333343506, 0.333343506, 0.778656006, 0.555999756, 0.333343506, 0.110687256, 0.333343506,
+			1.55859375, 1.0390625, 1.55859375, 0.51953125, 1.55859375, 1.55859375, 0, 1.0390625,
+			1.0390625, 1.0390625, 0, 1.0390625, 0.51953125, 1.55859375, 1.0390625, 0.51953125,
+			0.803253174, 0.135284424, 0.580596924, 0.357940674, 0.357940674, 0.135284424, 0.357940674, 0.135284424,
+			0.580596924, 0.580596924, 0.357940674, 0.580596924, 0.803253174, 0.357940674, 0.357940674, 0.135284424,
+		},
+	},
+	{
+		name: "quantized",
+		typ:  GgmlQ3_K,
+		data: "8ea1c0efb32cf4b97d79b92c3bffa140beb853e2099d93ae3cf2eeea9efee9aaf9bca5226ac4f55438b24a30197f10b35cbbf33f538126b31ec586c9416f140b26cefae72cddf78dd3919721b90ea3ec6f5b0a5b616aff1bc31210e57419a90dbb6406b55dba59d6fc0c03f007a6",
+		want: []float32{
+			-1.48329163, 0, -1.48329163, 0.988861084, 0.988861084, -1.97772217, -1.48329163, 0,
+			0, 0.988861084, 0.988861084, -1.97772217, 0.494430542, 1.48329163, 0, -0.494430542,
+			-2.63696289, -0.659240723, 1.97772217, -0.659240723, 1.97772217, 0.659240723, 1.31848145, -0.659240723,
+			-1.31848145, -1.97772217, -1.31848145, -1.97772217, -1.97772217, -0.659240723, 0, -0.659240723,
+			-1.03594971, 0.517974854, 1.55392456, -0, -1.03594971, 1.55392456, 1.55392456, 1.55392456,
+			1.03594971, 2.07189941, 1.03594971, 2.07189941, -1.03594971, -1.55392456, 2.07189941, 2.07189941,
+			1.90708923, -1.27139282, 0, 1.90708923, -2.54278564, -2.54278564, 0.635696411, 0,
+			-0.635696411, 0.635696411, 0.635696411, 1.27139282, 0, 1.90708923, -1.90708923, 1.27139282,
+			-2.0483551, 0.682785034, 1.36557007, -1.36557007, 1.36557007, -0, -2.0483551, 2.0483551,
+			-2.0483551, 0.682785034, 2.73114014, -2.0483551, 2.0483551, -2.0483551, 2.0483551, 0.682785034,
+			-0.6121521, 0.6121521, 0.6121521, 0.6121521, 1.8364563, -0, 1.2243042, -1.8364563,
+			-0.6121521, 2.4486084, -0, 2.4486084, -0, -1.2243042, 1.8364563, 2.4486084,
+			1.6245575, -1.08303833, -1.08303833, 0, -1.6245575, 1.6245575, -0.541519165, 0.541519165,
+			0, 1.08303833, 0.541519165, 0, 0, 0.541519165, -2.16607666, -1.08303833,
+			0.6121521, 1.2243042, -0.6121521, -2.4486084, 0.6121521, 1.2243042, -2.4486084, 1.2243042,
+			0, -0.6121521, 1.2243042, 1.8364563, 0.6121521, 0.6121521, 0, 0,
+			1.27139282, 1.27139282, 1.27139282, 0.635696411, -0, 1.90708923, -1.90708923, -0.635696411,
+			-1.90708923, -0.635696411, -1.90708923, 1.90708923, -0.635696411, -1.27139282, 0.635696411, 2.54278564,
+			1.8364563, 1.8364563, 1.2243042, -0.6121521, -1.8364563, 1.2243042, 1.8364563, -0.6121521,
+			1.8364563, 1.2243042, -2.4486084, -1.8364563, 0, 0.6121521, -1.8364563, -1.8364563,
+			-2.26025391, 2.26025391, -1.50683594, 0.753417969, 2.26025391, 2.26025391, 0.753417969, 2.26025391,
+			0, 0, 0.753417969, 0, 1.50683594, 2.26025391, 0, -0.753417969,
+			-1.90708923, -1.27139282, 1.27139282, -1.27139282, 2.54278564, 1.27139282, 0.635696411, -1.27139282,
+			-0, -0, -0, -0.635696411, 1.90708923, -1.27139282, -1.27139282, -1.90708923,
+			0.988861084, 1.97772217, -1.48329163, -0.988861084, 0.988861084, 1.48329163, -1.48329163, 1.97772217,
+			-0.494430542, -0.494430542, 1.48329163, 0.988861084, 0.494430542, -0, 0.988861084, -0.988861084,
+			-0.988861084, -1.48329163, 0, 0.494430542, -0.988861084, -0.988861084, -0.494430542, -1.48329163,
+			-1.97772217, 0.494430542, 0.494430542, 0.988861084, -0.494430542, 0.494430542, 0.988861084, -1.97772217,
+			0, 1.90708923, 1.90708923, 1.90708923, 0, -0.635696411, 1.90708923, 1.27139282,
+			-0.635696411, -1.27139282, 1.27139282, -2.54278564, -1.27139282, 0, 1.27139282, -0.635696411,
+			-0.682785034, -0.682785034, 2.73114014, -0.682785034, 2.0483551, -0.682785034, -2.0483551, -0,
+			0.682785034, -0, -0, -2.0483551, -0.682785034, -0, -1.36557007, -0,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlQ3_K,
+		data: "a510db2b0ddcbd12d7eaf6d56e960eb63add452513bfd2327176cfbfb99fb9a847a7f0471f0f911a62589272f4b20b64f2befa6ed7f60e878090258e67f29e44c305b15267064f048c9d5fb5ea564916bac81dd3950ddd38454da2daf378ab6f588b9a4bd0e65437758b6391c028",
+		want: []float32{
+			-0.890625, 0.296875, -0, -0.890625, -0.890625, 0.296875, -0.296875, 0.59375,
+			-0.59375, 1.1875, 0.59375, -0.59375, 1.1875, 0.59375, 0.296875, 1.1875,
+			-2.00390625, 2.00390625, 2.00390625, 2.00390625, 3.00585938, 2.00390625, -2.00390625, -1.00195312,
+			0, -4.0078125, 1.00195312, 2.00390625, 3.00585938, 2.00390625, 2.00390625, -4.0078125,
+			-2.89453125, -2.89453125, 0, 0.96484375, -0.96484375, -0.96484375, -3.859375, 1.9296875,
+			0, 1.9296875, 0, -3.859375, 0.96484375, 0, 1.9296875, 0.96484375,
+			-0, 0.185546875, 0.37109375, 0.185546875, -0.185546875, -0.185546875, -0.556640625, -0.185546875,
+			0.7421875, -0, -0.185546875, -0.556640625, 0.556640625, -0, 0.185546875, 0.556640625,
+			-0, 1.1875, 0.59375, 2.375, -0.59375, -0, -0.59375, 1.78125,
+			-1.1875, 1.78125, -0.59375, -1.78125, -1.78125, -1.78125, -0, -1.1875,
+			-0.22265625, 0.66796875, 0.66796875, 0.4453125, -0.66796875, 0.66796875, -0.890625, -0.890625,
+			-0.890625, 0.22265625, 0.4453125, 0, -0.4453125, 0.66796875, -0.66796875, -0.890625,
+			3.1171875, 2.078125, -3.1171875, -1.0390625, -0, -0, -2.078125, 4.15625,
+			3.1171875, -1.0390625, 2.078125, 3.1171875, -3.1171875, 2.078125, -0, 3.1171875,
+			-2.78320312, -1.85546875, 0.927734375, 2.78320312, 0.927734375, -2.78320312, 3.7109375, 1.85546875,
+			1.85546875, 1.85546875, -0, -1.85546875, -0.927734375, -2.78320312, -1.85546875, -0.927734375,
+			-0.779296875, 0.779296875, 0.779296875, -1.55859375, -0.779296875, 1.55859375, 2.33789062, 0,
+			0, -2.33789062, 2.33789062, 0.779296875, -1.55859375, 1.55859375, -2.33789062, 1.55859375,
+			-1.78125, -0, 2.671875, 0.890625, -0.890625, -0.890625, -0.890625, -0,
+			-0.890625, -0.890625, 1.78125, -1.78125, -2.671875, -0, -2.671875, 0.890625,
+			0, -1.00195312, -1.3359375, 0, -1.00195312, -1.00195312, 1.00195312, -1.00195312,
+			-0.333984375, 1.00195312, 1.00195312, -1.00195312, 0.66796875, -1.00195312, -0.66796875, 0.333984375,
+			-0.890625, 0.890625, 0.4453125, -0, 1.3359375, -1.3359375, 0.4453125, -0.890625,
+			-0.4453125, -1.3359375, 1.78125, -0.890625, -0, 0.890625, -0.890625, -1.3359375,
+			0.4453125, 0.4453125, -0.333984375, 0.333984375, 0.22265625, -0, 0.4453125, 0.4453125,
+			-0, -0.111328125, -0.111328125, -0.333984375, -0.22265625, 0.333984375, 0.4453125, 0.333984375,
+			-0.51953125, 0, 0.51953125, -1.55859375, -1.55859375, -2.078125, 0.51953125, -0.51953125,
+			0, 0, 1.0390625, -1.55859375, -0.51953125, -0.51953125, -1.0390625, -1.0390625,
+			-1.22460938, 1.6328125, -0.81640625, 1.22460938, 1.22460938, -0, -0.408203125, 1.6328125,
+			-0.81640625, -0.81640625, -0.408203125, -0.81640625, 0.408203125, -0.408203125, 1.22460938, -0,
+			-0.22265625, 0.333984375, -0.4453125, -0.111328125, -0.22265625, 0, 0.333984375, -0.4453125,
+			-0.333984375, -0.333984375, 0.22265625, 0.333984375, 0.333984375, 0.111328125, 0.22265625, 0.111328125,
+		},
+	},
+	{
+		name: "high scale bits",
+		typ:  GgmlQ3_K,
+		data: "a510db2b0ddcbd12d7eaf6d56e960eb63add452513bfd2327176cfbfb99fb9a847a7f0471f0f911a62589272f4b20b64f2befa6ed7f60e878090258e67f29e44c305b15267064f048c9d5fb5ea564916bac81dd3950ddd38454da2daf378ab6fffeeddccbbaa998877665544c028",
+		want: []float32{
+			3.45117188, -1.15039062, 0, 3.45117188, 3.45117188, -1.15039062, 1.15039062, -2.30078125,
+			2.30078125, -4.6015625, -2.30078125, 2.30078125, -4.6015625, -2.30078125, -1.15039062, -4.6015625,
+			-1.0390625, 1.0390625, 1.0390625, 1.0390625, 1.55859375, 1.0390625, -1.0390625, -0.51953125,
+			0, -2.078125, 0.51953125, 1.0390625, 1.55859375, 1.0390625, 1.0390625, -2.078125,
+			0.333984375, 0.333984375, -0, -0.111328125, 0.111328125, 0.111328125, 0.4453125, -0.22265625,
+			-0, -0.22265625, -0, 0.4453125, -0.111328125, -0, -0.22265625, -0.111328125,
+			-0, 0.7421875, 1.484375, 0.7421875, -0.7421875, -0.7421875, -2.2265625, -0.7421875,
+			2.96875, -0, -0.7421875, -2.2265625, 2.2265625, -0, 0.7421875, 2.2265625,
+			-0, 0.37109375, 0.185546875, 0.7421875, -0.185546875, -0, -0.185546875, 0.556640625,
+			-0.37109375, 0.556640625, -0.185546875, -0.556640625, -0.556640625, -0.556640625, -0, -0.37109375,
+			0.22265625, -0.66796875, -0.66796875, -0.4453125, 0.66796875, -0.66796875, 0.890625, 0.890625,
+			0.890625, -0.22265625, -0.4453125, -0, 0.4453125, -0.66796875, 0.66796875, 0.890625,
+			0.779296875, 0.51953125, -0.779296875, -0.259765625, -0, -0, -0.51953125, 1.0390625,
+			0.779296875, -0.259765625, 0.51953125, 0.779296875, -0.779296875, 0.51953125, -0, 0.779296875,
+			-0.890625, -0.59375, 0.296875, 0.890625, 0.296875, -0.890625, 1.1875, 0.59375,
+			0.59375, 0.59375, -0, -0.59375, -0.296875, -0.890625, -0.59375, -0.296875,
+			-1.15039062, 1.15039062, 1.15039062, -2.30078125, -1.15039062, 2.30078125, 3.45117188, 0,
+			0, -3.45117188, 3.45117188, 1.15039062, -2.30078125, 2.30078125, -3.45117188, 2.30078125,
+			1.0390625, 0, -1.55859375, -0.51953125, 0.51953125, 0.51953125, 0.51953125, 0,
+			0.51953125, 0.51953125, -1.0390625, 1.0390625, 1.55859375, 0, 1.55859375, -0.51953125,
+			-0, 0.333984375, 0.4453125, -0, 0.333984375, 0.333984375, -0.333984375, 0.333984375,
+			0.111328125, -0.333984375, -0.333984375, 0.333984375, -0.22265625, 0.333984375, 0.22265625, -0.111328125,
+			-1.484375, 1.484375, 0.7421875, -0, 2.2265625, -2.2265625, 0.7421875, -1.484375,
+			-0.7421875, -2.2265625, 2.96875, -1.484375, -0, 1.484375, -1.484375, -2.2265625,
+			0.7421875, 0.7421875, -0.556640625, 0.556640625, 0.37109375, -0, 0.7421875, 0.7421875,
+			-0, -0.185546875, -0.185546875, -0.556640625, -0.37109375, 0.556640625, 0.7421875, 0.556640625,
+			0.22265625, -0, -0.22265625, 0.66796875, 0.66796875, 0.890625, -0.22265625, 0.22265625,
+			-0, -0, -0.4453125, 0.66796875, 0.22265625, 0.22265625, 0.4453125, 0.4453125,
+			-0.779296875, 1.0390625, -0.51953125, 0.779296875, 0.779296875, -0, -0.259765625, 1.0390625,
+			-0.51953125, -0.51953125, -0.259765625, -0.51953125, 0.259765625, -0.259765625, 0.779296875, -0,
+			0.59375, -0.890625, 1.1875, 0.296875, 0.59375, -0, -0.890625, 1.1875,
+			0.890625, 0.890625, -0.59375, -0.890625, -0.890625, -0.296875, -0.59375, -0.296875,
+		},
+	},
+	{
+		name: "quantized",
+		typ:  GgmlQ4_K,
+		data: "941d2429fbfabbf5fdfcbdffabcffeae4497c37b5bd2c3d7b8dbbbe2492ce7e7e0358fe60e0aab765492a3c271f628c5e2695b844ce772ae90cabf818eb01e69a6ea7a1aacd80bd1a67fd7ffc8c38eaf0cdb3a9ad7dc90e47165a27cc4e4785f0d3dbb45e1abac357e7c6052c84942217bcfd2d47c5dc1bf6635ae0b3977bc435442e86ad46416618069890c467a2c70",
+		want: []float32{
+			-1.16424561, -0.200057983, -1.48564148, 1.08552551, 1.08552551, -1.80703735, -1.48564148, -0.200057983,
+			0.121337891, 1.08552551, 1.08552551, -1.80703735, 0.442733765, 1.40692139, -0.200057983, -0.200057983,
+			-2.4498291, -0.842849731, 2.37110901, -0.521453857, 2.04971313, 0.764129639, 1.08552551, -0.521453857,
+			-1.16424561, -1.80703735, -1.48564148, -1.80703735, -2.12843323, -0.521453857, 0.121337891, -0.842849731,
+			-1.14587402, 0.433868408, 1.38171387, -0.198028564, -0.829925537, 1.69766235, 1.38171387, 1.69766235,
+			1.06576538, 1.69766235, 1.06576538, 2.01361084, -1.14587402, -1.777771, 2.01361084, 2.01361084,
+			2.01361084, -1.46182251, 0.117919922, 2.01361084, -2.40966797, -2.40966797, 0.749816895, -0.198028564,
+			-0.829925537, 0.433868408, 0.749816895, 1.38171387, -0.198028564, 2.32955933, -1.777771, 1.38171387,
+			-1.80703735, 0.442733765, 1.08552551, -1.16424561, 1.40692139, -0.200057983, -1.80703735, 2.04971313,
+			-2.4498291, 0.764129639, 2.37110901, -2.12843323, 2.04971313, -2.4498291, 2.04971313, 0.442733765,
+			-0.521453857, 0.764129639, 0.764129639, 0.764129639, 1.40692139, 0.121337891, 1.08552551, -2.12843323,
+			-0.521453857, 2.37110901, -0.200057983, 2.37110901, 0.121337891, -1.48564148, 2.04971313, 2.37110901,
+			1.5118103, -0.79788208, -1.08659363, -0.220458984, -1.37530518, 1.5118103, -0.509170532, 0.356964111,
+			0.0682525635, 0.934387207, 0.645675659, -0.220458984, -0.220458984, 0.645675659, -2.24143982, -0.79788208,
+			0.356964111, 1.5118103, -0.509170532, -2.24143982, 0.356964111, 1.22309875, -2.53015137, 1.22309875,
+			0.356964111, -0.509170532, 1.22309875, 1.80052185, 0.934387207, 0.934387207, -0.220458984, 0.356964111,
+			1.52740479, 1.20600891, 0.884613037, 0.884613037, -0.079574585, 1.52740479, -2.3293457, -1.04376221,
+			-2.00794983, -0.722366333, -1.68655396, 1.52740479, -1.04376221, -1.04376221, 0.241821289, 2.49159241,
+			1.84880066, 1.84880066, 1.20600891, -0.722366333, -2.00794983, 1.20600891, 1.52740479, -0.722366333,
+			2.17019653, 1.52740479, -2.3293457, -1.68655396, 0.241821289, 0.563217163, -1.68655396, -2.00794983,
+			-2.40966797, 2.05174255, -1.38011169, 0.679000854, 2.05174255, 2.05174255, 0.679000854, 2.39492798,
+			-0.00736999512, -0.35055542, 1.02218628, -0.00736999512, 1.70855713, 2.39492798, -0.00736999512, -0.693740845,
+			-2.40966797, -1.38011169, 1.3653717, -1.03692627, 2.39492798, 1.02218628, 1.02218628, -1.38011169,
+			-0.00736999512, -0.00736999512, -0.35055542, -0.693740845, 1.70855713, -1.03692627, -1.03692627, -1.72329712,
+			0.868804932, 1.87112427, -1.38641357, -0.885253906, 1.11938477, 1.3699646, -1.63699341, 1.87112427,
+			-0.384094238, -0.634674072, 1.62054443, 0.868804932, 0.367645264, -0.133514404, 1.11938477, -1.13583374,
+			-0.885253906, -1.38641357, 0.11706543, 0.618225098, -0.885253906, -0.885253906, -0.384094238, -1.63699341,
+			-1.88757324, 0.367645264, 0.367645264, 1.11938477, -0.384094238, 0.618225098, 1.11938477, -1.88757324,
+			0.0348205566, 1.72351074, 2.06124878, 2.06124878, 0.0348205566, -0.640655518, 1.72351074, 1.38577271,
+			-0.30291748, -1.31613159, 1.04803467, -2.3293457, -1.31613159, 0.0348205566, 1.38577271, -0.978393555,
+			-0.640655518, -0.978393555, 2.39898682, -0.30291748, 2.06124878, -0.30291748, -1.99160767, -0.30291748,
+			0.372558594, -0.30291748, 0.372558594, -2.3293457, -0.978393555, 0.0348205566, -1.65386963, 0.0348205566,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlQ4_K,
+		data: "c0284ca20ddcbd12d7eaf6d56e960eb63add452513bfd2327176cfbfb99fb9a847a7f0471f0f911a62589272f4b20b64f2befa6ed7f60e878090258e67f29e44c305b15267064f048c9d5fb5ea564916bac81dd3950ddd38454da2daf378ab6f588b9a4bd0e65437758b6391ac65bff8b4d95cde3c45f0d30335be61fb6e69bf88130bc8c98a21ed02e607109af70788",
+		want: []float32{
+			5.10708618, 6.55435181, 2.69497681, 2.69497681, 1.73013306, 7.51919556, 1.24771118, 1.24771118,
+			0.765289307, 3.17739868, 7.51919556, 7.51919556, 4.62466431, 7.51919556, 4.62466431, 4.14224243,
+			3.65982056, 3.65982056, 0.282867432, 3.65982056, 7.51919556, 7.51919556, 0.765289307, 5.10708618,
+			1.24771118, 4.14224243, 1.24771118, 1.24771118, 2.21255493, 1.24771118, 5.58950806, 2.21255493,
+			3.63372803, 14.024353, 4.67279053, 2.59466553, 1.55560303, 11.946228, 14.024353, 3.63372803,
+			7.78997803, 7.78997803, 12.9852905, 11.946228, 11.946228, 9.86810303, 11.946228, 10.9071655,
+			4.67279053, 10.9071655, 16.102478, 4.67279053, 1.55560303, 0.516540527, 9.86810303, 1.55560303,
+			6.75091553, 5.71185303, 9.86810303, 7.78997803, 16.102478, 11.946228, 0.516540527, 6.75091553,
+			5.19146729, 32.3555298, 23.3008423, 32.3555298, 16.5098267, 14.2461548, 32.3555298, 16.5098267,
+			0.664123535, 0.664123535, 11.9824829, 32.3555298, 16.5098267, 5.19146729, 32.3555298, 9.71881104,
+			7.45513916, 11.9824829, 2.92779541, 5.19146729, 16.5098267, 14.2461548, 34.6192017, 9.71881104,
+			27.828186, 30.0918579, 34.6192017, 11.9824829, 23.3008423, 14.2461548, 21.0371704, 14.2461548,
+			10.2778015, 7.60592651, 10.2778015, 4.26608276, 8.94186401, 10.2778015, 0.258270264, 5.60202026,
+			5.60202026, 6.26998901, 1.59420776, 5.60202026, 4.26608276, 10.2778015, 6.26998901, 2.93014526,
+			8.27389526, 0.258270264, 7.60592651, 3.59811401, 4.26608276, 0.258270264, 2.93014526, 0.258270264,
+			5.60202026, 6.26998901, 3.59811401, 7.60592651, 9.60983276, 3.59811401, 2.93014526, 0.926239014,
+			5.85943604, 4.82037354, 7.41802979, 2.22271729, 3.26177979, 7.41802979, 7.41802979, 4.82037354,
+			3.26177979, 7.41802979, 1.70318604, 5.85943604, 2.22271729, 4.82037354, 6.37896729, 8.45709229,
+			4.82037354, 6.37896729, 5.85943604, 6.37896729, 0.664123535, 3.78131104, 2.74224854, 4.30084229,
+			3.26177979, 6.37896729, 2.22271729, 1.18365479, 6.89849854, 3.26177979, 8.45709229, 4.82037354,
+			22.743988, 24.7478943, 2.70492554, 26.7518005, 18.7361755, 0.701019287, 26.7518005, 6.71273804,
+			8.71664429, 8.71664429, 20.7400818, 26.7518005, 30.759613, 14.728363, 20.7400818, 12.7244568,
+			10.7205505, 16.7322693, 18.7361755, 8.71664429, 26.7518005, 28.7557068, 10.7205505, 6.71273804,
+			14.728363, 16.7322693, 12.7244568, 18.7361755, 20.7400818, 12.7244568, 22.743988, 30.759613,
+			7.41845703, 15.9536133, 21.074707, 24.4887695, 21.074707, 9.12548828, 0.590332031, 5.71142578,
+			5.71142578, 9.12548828, 24.4887695, 2.29736328, 19.3676758, 24.4887695, 15.9536133, 26.1958008,
+			14.246582, 5.71142578, 19.3676758, 14.246582, 15.9536133, 17.6606445, 2.29736328, 22.7817383,
+			4.00439453, 10.8325195, 12.5395508, 0.590332031, 17.6606445, 12.5395508, 12.5395508, 14.246582,
+			3.17483521, 3.62014771, 1.83889771, 3.62014771, 1.39358521, 1.61624146, 4.06546021, 3.62014771,
+			0.725616455, 1.39358521, 3.17483521, 2.06155396, 4.06546021, 2.06155396, 2.06155396, 3.17483521,
+			2.50686646, 0.948272705, 0.725616455, 3.39749146, 3.39749146, 2.50686646, 1.17092896, 3.84280396,
+			0.725616455, 3.84280396, 0.725616455, 0.948272705, 2.72952271, 4.06546021, 0.725616455, 2.50686646,
+		},
+	},
+	{
+		name: "high scale bits",
+		typ:  GgmlQ4_K,
+		data: "c0284ca2ffeeddccbbaa9988776655443add452513bfd2327176cfbfb99fb9a847a7f0471f0f911a62589272f4b20b64f2befa6ed7f60e878090258e67f29e44c305b15267064f048c9d5fb5ea564916bac81dd3950ddd38454da2daf378ab6f588b9a4bd0e65437758b6391ac65bff8b4d95cde3c45f0d30335be61fb6e69bf88130bc8c98a21ed02e607109af70788",
+		want: []float32{
+			24.1045227, 31.1181946, 12.4150696, 12.4150696, 7.73928833, 35.7939758, 5.40139771, 5.40139771,
+			3.06350708, 14.7529602, 35.7939758, 35.7939758, 21.7666321, 35.7939758, 21.7666321, 19.4287415,
+			17.0908508, 17.0908508, 0.725616455, 17.0908508, 35.7939758, 35.7939758, 3.06350708, 24.1045227,
+			5.40139771, 19.4287415, 5.40139771, 5.40139771, 10.077179, 5.40139771, 26.4424133, 10.077179,
+			5.63763428, 22.7079468, 7.34466553, 3.93060303, 2.22357178, 19.2938843, 22.7079468, 5.63763428,
+			12.4657593, 12.4657593, 21.0009155, 19.2938843, 19.2938843, 15.8798218, 19.2938843, 17.586853,
+			7.34466553, 17.586853, 26.1220093, 7.34466553, 2.22357178, 0.516540527, 15.8798218, 2.22357178,
+			10.758728, 9.05169678, 15.8798218, 12.4657593, 26.1220093, 19.2938843, 0.516540527, 10.758728,
+			2.45980835, 15.3738708, 11.0691833, 15.3738708, 7.84066772, 6.76449585, 15.3738708, 7.84066772,
+			0.3074646, 0.3074646, 5.68832397, 15.3738708, 7.84066772, 2.45980835, 15.3738708, 4.6121521,
+			3.53598022, 5.68832397, 1.38363647, 2.45980835, 7.84066772, 6.76449585, 16.4500427, 4.6121521,
+			13.2215271, 14.297699, 16.4500427, 5.68832397, 11.0691833, 6.76449585, 9.99301147, 6.76449585,
+			6.77807617, 4.99682617, 6.77807617, 2.77026367, 5.88745117, 6.77807617, 0.0983886719, 3.66088867,
+			3.66088867, 4.10620117, 0.989013672, 3.66088867, 2.77026367, 6.77807617, 4.10620117, 1.87963867,
+			5.44213867, 0.0983886719, 4.99682617, 2.32495117, 2.77026367, 0.0983886719, 1.87963867, 0.0983886719,
+			3.66088867, 4.10620117, 2.32495117, 4.99682617, 6.33276367, 2.32495117, 1.87963867, 0.543701172,
+			20.889801, 16.8077698, 27.0128479, 6.60269165, 10.6847229, 27.0128479, 27.0128479, 16.8077698,
+			10.6847229, 27.0128479, 4.56167603, 20.889801, 6.60269165, 16.8077698, 22.9308167, 31.0948792,
+			16.8077698, 22.9308167, 20.889801, 22.9308167, 0.479644775, 12.7257385, 8.64370728, 14.7667542,
+			10.6847229, 22.9308167, 6.60269165, 2.5206604, 24.9718323, 10.6847229, 31.0948792, 16.8077698,
+			22.5103149, 24.5142212, 2.47125244, 26.5181274, 18.5025024, 0.467346191, 26.5181274, 6.47906494,
+			8.48297119, 8.48297119, 20.5064087, 26.5181274, 30.5259399, 14.4946899, 20.5064087, 12.4907837,
+			10.4868774, 16.4985962, 18.5025024, 8.48297119, 26.5181274, 28.5220337, 10.4868774, 6.47906494,
+			14.4946899, 16.4985962, 12.4907837, 18.5025024, 20.5064087, 12.4907837, 22.5103149, 30.5259399,
+			8.32223511, 18.1562195, 24.0566101, 27.9902039, 24.0566101, 10.289032, 0.455047607, 6.35543823,
+			6.35543823, 10.289032, 27.9902039, 2.42184448, 22.0898132, 27.9902039, 18.1562195, 29.9570007,
+			16.1894226, 6.35543823, 22.0898132, 16.1894226, 18.1562195, 20.1230164, 2.42184448, 26.023407,
+			4.38864136, 12.2558289, 14.2226257, 0.455047607, 20.1230164, 14.2226257, 14.2226257, 16.1894226,
+			21.6693115, 25.5286865, 10.0911865, 25.5286865, 6.23181152, 8.16149902, 29.3880615, 25.5286865,
+			0.442749023, 6.23181152, 21.6693115, 12.020874, 29.3880615, 12.020874, 12.020874, 21.6693115,
+			15.880249, 2.37243652, 0.442749023, 23.598999, 23.598999, 15.880249, 4.30212402, 27.458374,
+			0.442749023, 27.458374, 0.442749023, 2.37243652, 17.8099365, 29.3880615, 0.442749023, 15.880249,
+		},
+	},
+	{
+		name: "quantized",
+		typ:  GgmlQ5_K,
+		data: "4b191e29fcfbfff5fcfdbffdbcedf0ce58f696b9657aa2ee2b0fef7a6d29f6161a1cb746ad3d3708b876ca4e3c5a4d0e973e95f7a6a396cf60c676e39259deeed07a1fcd0d0547ecb7445594f2fb409ac4c2a71878dff45c31936f021b801cb25cd4e51549b006b24cedbffe80870c4e09d88645deda40e902da541ab9f911cf1a7b989bf377597a0d19e0c4a0939542f7afc4c8f8ca928ddc6a5c0683ee8995b993efd5c8c72be221e21218acf558f0",
+		want: []float32{
+			-1.31320953, -0.227737427, -1.62334442, 1.16786957, 1.01280212, -1.93347931, -1.46827698, -0.0726699829,
+			0.0823974609, 1.01280212, 1.01280212, -1.93347931, 0.392532349, 1.47800446, -0.227737427, -0.227737427,
+			-2.39868164, -0.848007202, 2.40840912, -0.382804871, 2.09827423, 0.85773468, 1.16786957, -0.537872314,
+			-1.31320953, -1.77841187, -1.62334442, -1.77841187, -2.08854675, -0.692939758, 0.0823974609, -0.848007202,
+			-1.06631279, 0.458517075, 1.37341499, -0.151414871, -0.913829803, 1.52589798, 1.37341499, 1.83086395,
+			0.915966034, 1.83086395, 1.06844902, 2.13582993, -1.06631279, -1.67624474, 1.98334694, 2.13582993,
+			1.98334694, -1.37127876, 0.153551102, 1.83086395, -2.43865967, -2.43865967, 0.611000061, -0.303897858,
+			-0.761346817, 0.611000061, 0.763483047, 1.37341499, -0.151414871, 2.28831291, -1.82872772, 1.37341499,
+			-1.86733246, 0.412158966, 1.22626305, -1.21604919, 1.38908386, -0.0763034821, -1.86733246, 2.04036713,
+			-2.35579491, 0.574979782, 2.52882957, -2.19297409, 1.87754631, -2.51861572, 2.04036713, 0.412158966,
+			-0.56476593, 0.737800598, 0.900621414, 0.900621414, 1.55190468, 0.086517334, 1.06344223, -2.19297409,
+			-0.56476593, 2.20318794, -0.0763034821, 2.36600876, 0.086517334, -1.37887001, 2.04036713, 2.36600876,
+			1.39667511, -0.794944763, -1.06889725, -0.110063553, -1.47982597, 1.53365135, -0.384016037, 0.437841415,
+			0.163888931, 0.985746384, 0.574817657, -0.247039795, -0.110063553, 0.848770142, -2.30168343, -0.931921005,
+			0.437841415, 1.53365135, -0.520992279, -2.30168343, 0.300865173, 1.25969887, -2.43865967, 1.25969887,
+			0.300865173, -0.520992279, 1.25969887, 1.80760384, 0.848770142, 0.848770142, -0.247039795, 0.300865173,
+			1.51798248, 1.36291504, 1.05278015, 0.897712708, -0.187759399, 1.67304993, -2.35870361, -0.963096619,
+			-2.04856873, -0.808029175, -1.73843384, 1.67304993, -0.963096619, -0.963096619, 0.277442932, 2.44838715,
+			1.67304993, 1.82811737, 1.36291504, -0.652961731, -1.89350128, 1.2078476, 1.51798248, -0.808029175,
+			2.13825226, 1.51798248, -2.35870361, -1.73843384, 0.122375488, 0.58757782, -1.58336639, -2.04856873,
+			-2.4786377, 2.09326744, -1.21742249, 0.67440033, 2.09326744, 2.09326744, 0.67440033, 2.25091934,
+			0.0437927246, -0.429162979, 0.832052231, 0.201444626, 1.77796364, 2.40857124, 0.201444626, -0.58681488,
+			-2.32098579, -1.37507439, 1.46265984, -1.05977058, 2.40857124, 1.14735603, 0.832052231, -1.37507439,
+			0.0437927246, 0.201444626, -0.271511078, -0.58681488, 1.62031174, -1.05977058, -1.05977058, -1.84803009,
+			0.974273682, 1.96670532, -1.38275146, -0.886535645, 1.09832764, 1.34643555, -1.63085938, 1.71859741,
+			-0.390319824, -0.638427734, 1.59454346, 0.850219727, 0.478057861, -0.142211914, 1.22238159, -1.25869751,
+			-0.762481689, -1.50680542, -0.018157959, 0.726165771, -0.886535645, -1.0105896, -0.514373779, -1.63085938,
+			-1.75491333, 0.354003906, 0.354003906, 1.09832764, -0.390319824, 0.726165771, 1.09832764, -1.87896729,
+			0.00486373901, 1.76746368, 2.0879364, 2.0879364, 0.00486373901, -0.475845337, 1.60722733, 1.44699097,
+			-0.315608978, -1.43726349, 0.966281891, -2.39868164, -1.11679077, -0.15537262, 1.44699097, -0.956554413,
+			-0.636081696, -0.956554413, 2.40840912, -0.315608978, 2.0879364, -0.475845337, -2.07820892, -0.15537262,
+			0.485572815, -0.15537262, 0.325336456, -2.23844528, -0.796318054, 0.00486373901, -1.59749985, 0.00486373901,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlQ5_K,
+		data: "c0284ca20ddcbd12d7eaf6d56e960eb63add452513bfd2327176cfbfb99fb9a847a7f0471f0f911a62589272f4b20b64f2befa6ed7f60e878090258e67f29e44c305b15267064f048c9d5fb5ea564916bac81dd3950ddd38454da2daf378ab6f588b9a4bd0e65437758b6391ac65bff8b4d95cde3c45f0d30335be61fb6e69bf88130bc8c98a21ed02e607109af70788444249ae3c9018bfc0c955f72245031c1be748ee467f5a33da66184f1b555e9a",
+		want: []float32{
+			1.24771118, 14.7555237, 12.8258362, 14.7555237, 11.3785706, 10.8961487, 7.03677368, 3.65982056,
+			8.00161743, 0.282867432, 10.4137268, 14.7555237, 11.3785706, 8.96646118, 14.7555237, 2.21255493,
+			9.44888306, 10.4137268, 0.765289307, 8.96646118, 11.3785706, 10.8961487, 15.2379456, 2.21255493,
+			6.07192993, 6.55435181, 7.51919556, 2.69497681, 5.10708618, 3.17739868, 12.3434143, 3.17739868,
+			32.727478, 11.946228, 16.102478, 6.75091553, 30.649353, 32.727478, 17.1415405, 25.4540405,
+			8.82904053, 26.493103, 19.2196655, 25.4540405, 6.75091553, 32.727478, 9.86810303, 4.67279053,
+			29.6102905, 17.1415405, 11.946228, 22.336853, 23.3759155, 17.1415405, 4.67279053, 17.1415405,
+			25.4540405, 9.86810303, 22.336853, 28.571228, 15.0634155, 22.336853, 21.2977905, 1.55560303,
+			23.3008423, 54.9922485, 66.3106079, 43.6738892, 11.9824829, 66.3106079, 30.0918579, 18.7734985,
+			11.9824829, 66.3106079, 41.4102173, 59.5195923, 7.45513916, 54.9922485, 25.5645142, 34.6192017,
+			54.9922485, 61.7832642, 23.3008423, 61.7832642, 36.8828735, 50.4649048, 9.71881104, 16.5098267,
+			11.9824829, 25.5645142, 7.45513916, 2.92779541, 64.046936, 11.9824829, 34.6192017, 54.9922485,
+			18.2934265, 18.9613953, 0.926239014, 8.94186401, 6.26998901, 10.9457703, 8.94186401, 2.26217651,
+			2.93014526, 2.93014526, 17.6254578, 19.629364, 20.9653015, 15.6215515, 17.6254578, 14.9535828,
+			3.59811401, 5.60202026, 6.26998901, 2.93014526, 19.629364, 20.2973328, 3.59811401, 12.9496765,
+			4.93405151, 16.2895203, 4.26608276, 6.26998901, 6.93795776, 4.26608276, 18.2934265, 10.2778015,
+			11.0547485, 13.6524048, 6.89849854, 7.93756104, 15.2109985, 11.5742798, 8.97662354, 10.5352173,
+			10.5352173, 11.5742798, 7.93756104, 9.49615479, 14.6914673, 16.250061, 13.6524048, 8.45709229,
+			4.82037354, 2.22271729, 14.6914673, 4.82037354, 13.6524048, 5.85943604, 9.49615479, 15.7305298,
+			1.70318604, 12.093811, 12.6133423, 8.97662354, 14.171936, 12.6133423, 4.30084229, 4.82037354,
+			54.806488, 26.7518005, 10.7205505, 58.8143005, 6.71273804, 40.7791443, 30.759613, 58.8143005,
+			32.7635193, 38.775238, 22.743988, 44.7869568, 62.822113, 12.7244568, 44.7869568, 54.806488,
+			16.7322693, 34.7674255, 32.7635193, 24.7478943, 24.7478943, 16.7322693, 4.70883179, 28.7557068,
+			32.7635193, 28.7557068, 0.701019287, 34.7674255, 50.7986755, 62.822113, 0.701019287, 48.7947693,
+			7.41845703, 31.3168945, 43.2661133, 24.4887695, 21.074707, 0.590332031, 41.559082, 26.1958008,
+			27.902832, 43.2661133, 36.4379883, 12.5395508, 4.00439453, 9.12548828, 5.71142578, 21.074707,
+			46.6801758, 12.5395508, 41.559082, 51.8012695, 10.8325195, 26.1958008, 17.6606445, 5.71142578,
+			44.9731445, 38.1450195, 14.246582, 53.5083008, 46.6801758, 9.12548828, 24.4887695, 44.9731445,
+			1.61624146, 5.17874146, 1.61624146, 2.95217896, 1.39358521, 6.29202271, 4.51077271, 3.17483521,
+			3.39749146, 3.39749146, 5.40139771, 7.62796021, 4.73342896, 5.17874146, 4.28811646, 4.51077271,
+			0.948272705, 7.40530396, 5.17874146, 3.84280396, 1.61624146, 2.28421021, 5.40139771, 1.39358521,
+			3.62014771, 2.06155396, 4.51077271, 1.61624146, 4.51077271, 5.40139771, 1.83889771, 2.72952271,
+		},
+	},
+	{
+		name: "high scale bits",
+		typ:  GgmlQ5_K,
+		data: "c0284ca2ffeeddccbbaa9988776655443add452513bfd2327176cfbfb99fb9a847a7f0471f0f911a62589272f4b20b64f2befa6ed7f60e878090258e67f29e44c305b15267064f048c9d5fb5ea564916bac81dd3950ddd38454da2daf378ab6f588b9a4bd0e65437758b6391ac65bff8b4d95cde3c45f0d30335be61fb6e69bf88130bc8c98a21ed02e607109af70788444249ae3c9018bfc0c955f72245031c1be748ee467f5a33da66184f1b555e9a",
+		want: []float32{
+			5.40139771, 70.8623352, 61.5107727, 70.8623352, 54.4971008, 52.1592102, 33.4560852, 17.0908508,
+			38.1318665, 0.725616455, 49.8213196, 70.8623352, 54.4971008, 42.8076477, 70.8623352, 10.077179,
+			45.1455383, 49.8213196, 3.06350708, 42.8076477, 54.4971008, 52.1592102, 73.2002258, 10.077179,
+			28.780304, 31.1181946, 35.7939758, 12.4150696, 24.1045227, 14.7529602, 59.1728821, 14.7529602,
+			53.4345093, 19.2938843, 26.1220093, 10.758728, 50.0204468, 53.4345093, 27.8290405, 41.4852905,
+			14.1727905, 43.1923218, 31.243103, 41.4852905, 10.758728, 53.4345093, 15.8798218, 7.34466553,
+			48.3134155, 27.8290405, 19.2938843, 36.3641968, 38.071228, 27.8290405, 7.34466553, 27.8290405,
+			41.4852905, 15.8798218, 36.3641968, 46.6063843, 24.414978, 36.3641968, 34.6571655, 2.22357178,
+			11.0691833, 26.1355896, 31.516449, 20.7547302, 5.68832397, 31.516449, 14.297699, 8.9168396,
+			5.68832397, 31.516449, 19.6785583, 28.2879333, 3.53598022, 26.1355896, 12.1453552, 16.4500427,
+			26.1355896, 29.3641052, 11.0691833, 29.3641052, 17.5262146, 23.9832458, 4.6121521, 7.84066772,
+			5.68832397, 12.1453552, 3.53598022, 1.38363647, 30.4402771, 5.68832397, 16.4500427, 26.1355896,
+			12.1218262, 12.5671387, 0.543701172, 5.88745117, 4.10620117, 7.22338867, 5.88745117, 1.43432617,
+			1.87963867, 1.87963867, 11.6765137, 13.0124512, 13.9030762, 10.3405762, 11.6765137, 9.89526367,
+			2.32495117, 3.66088867, 4.10620117, 1.87963867, 13.0124512, 13.4577637, 2.32495117, 8.55932617,
+			3.21557617, 10.7858887, 2.77026367, 4.10620117, 4.55151367, 2.77026367, 12.1218262, 6.77807617,
+			41.2999573, 51.5050354, 24.9718323, 29.0538635, 57.6280823, 43.3409729, 33.1358948, 39.2589417,
+			39.2589417, 43.3409729, 29.0538635, 35.1769104, 55.5870667, 61.7101135, 51.5050354, 31.0948792,
+			16.8077698, 6.60269165, 55.5870667, 16.8077698, 51.5050354, 20.889801, 35.1769104, 59.6690979,
+			4.56167603, 45.3819885, 47.4230042, 33.1358948, 53.546051, 47.4230042, 14.7667542, 16.8077698,
+			54.5728149, 26.5181274, 10.4868774, 58.5806274, 6.47906494, 40.5454712, 30.5259399, 58.5806274,
+			32.5298462, 38.5415649, 22.5103149, 44.5532837, 62.5884399, 12.4907837, 44.5532837, 54.5728149,
+			16.4985962, 34.5337524, 32.5298462, 24.5142212, 24.5142212, 16.4985962, 4.47515869, 28.5220337,
+			32.5298462, 28.5220337, 0.467346191, 34.5337524, 50.5650024, 62.5884399, 0.467346191, 48.5610962,
+			8.32223511, 35.8573914, 49.6249695, 27.9902039, 24.0566101, 0.455047607, 47.6581726, 29.9570007,
+			31.9237976, 49.6249695, 41.757782, 14.2226257, 4.38864136, 10.289032, 6.35543823, 24.0566101,
+			53.5585632, 14.2226257, 47.6581726, 59.4589539, 12.2558289, 29.9570007, 20.1230164, 6.35543823,
+			51.5917664, 43.7245789, 16.1894226, 61.4257507, 53.5585632, 10.289032, 27.9902039, 51.5917664,
+			8.16149902, 39.036499, 8.16149902, 19.739624, 6.23181152, 48.6849365, 33.2474365, 21.6693115,
+			23.598999, 23.598999, 40.9661865, 60.2630615, 35.177124, 39.036499, 31.317749, 33.2474365,
+			2.37243652, 58.333374, 39.036499, 27.458374, 8.16149902, 13.9505615, 40.9661865, 6.23181152,
+			25.5286865, 12.020874, 33.2474365, 8.16149902, 33.2474365, 40.9661865, 10.0911865, 17.8099365,
+		},
+	},
+	{
+		name: "quantized",
+		typ:  GgmlQ6_K,
+		data: "8bad17f3f122797ee28010c185f76dbc71665f4bba0b2fc9802a1c09052851154f592be2dd48ac6522d580d0e0b91341593ea1284011080c36a70a72bdbed9410d0f73e5f3abbf3c6aaa672b8c3ccc41387a13c716f186452e556028a2c93a04008ccfc91cac692ec0ebdc0207ef32388ec30d5d6062a533a03ec4d8bbfe4d08f855506b0be07081b69387704ebf0151acd15b1d83e21af5a548e8cca4bd428d90ccf1e98e4cfbce6b268b185e6ec9749f8f23a91087d78d4ba768f8926af88c657d957d8087777b837379859e5f79831e11",
+		want: []float32{
+			-1.32489681, -0.189270973, -1.57725811, 1.19871616, 1.07253551, -1.89270973, -1.45107746, -0.126180649,
+			0.126180649, 1.00944519, 1.00944519, -1.95580006, 0.315451622, 1.45107746, -0.189270973, -0.252361298,
+			-2.42054462, -0.780820847, 2.42054462, -0.390410423, 2.0301342, 0.858902931, 1.17123127, -0.546574593,
+			-1.24931335, -1.71780586, -1.56164169, -1.79588795, -2.10821629, -0.624656677, 0.0780820847, -0.858902931,
+			-1.00257397, 0.467867851, 1.40360355, -0.133676529, -0.868897438, 1.60411835, 1.33676529, 1.80463314,
+			0.935735703, 1.80463314, 1.06941223, 2.13882446, -1.06941223, -1.67095661, 1.93830967, 2.0719862,
+			1.95205212, -1.40547752, 0.0780820847, 1.87397003, -2.49862671, -2.42054462, 0.624656677, -0.312328339,
+			-0.780820847, 0.546574593, 0.780820847, 1.40547752, -0.234246254, 2.34246254, -1.79588795, 1.32739544,
+			-1.91894531, 0.479736328, 1.19934082, -1.19934082, 1.35925293, -0.159912109, -1.83898926, 1.99890137,
+			-2.39868164, 0.639648438, 2.4786377, -2.23876953, 1.91894531, -2.4786377, 2.07885742, 0.399780273,
+			-0.529084206, 0.755834579, 0.831418037, 0.907001495, 1.58725262, -0, 1.05816841, -2.11633682,
+			-0.604667664, 2.26750374, -0.0755834579, 2.41867065, -0, -1.36050224, 2.04075336, 2.3430872,
+			1.48668289, -0.817675591, -1.04067802, -0.148668289, -1.41234875, 1.48668289, -0.446004868, 0.446004868,
+			0.148668289, 0.96634388, 0.594673157, -0.223002434, -0.148668289, 0.817675591, -2.30435848, -0.892009735,
+			0.384163857, 1.45982265, -0.460996628, -2.30498314, 0.307331085, 1.30615711, -2.45864868, 1.22932434,
+			0.230498314, -0.460996628, 1.22932434, 1.76715374, 0.845160484, 0.845160484, -0.230498314, 0.307331085,
+			1.48355961, 1.32739544, 1.0150671, 0.858902931, -0.234246254, 1.63972378, -2.42054462, -0.936985016,
+			-2.0301342, -0.780820847, -1.79588795, 1.63972378, -0.936985016, -0.936985016, 0.312328339, 2.42054462,
+			1.72405243, 1.86772346, 1.36487484, -0.646519661, -1.86772346, 1.2212038, 1.58038139, -0.790190697,
+			2.15506554, 1.50854588, -2.29873657, -1.72405243, 0.143671036, 0.646519661, -1.58038139, -2.0113945,
+			-2.41867065, 2.11633682, -1.28491879, 0.680251122, 2.11633682, 2.11633682, 0.680251122, 2.26750374,
+			0, -0.37791729, 0.907001495, 0.151166916, 1.73841953, 2.3430872, 0.151166916, -0.604667664,
+			-2.30498314, -1.45982265, 1.45982265, -0.998826027, 2.45864868, 1.0756588, 0.845160484, -1.45982265,
+			-0, 0.153665543, -0.307331085, -0.61466217, 1.6134882, -1.0756588, -0.998826027, -1.84398651,
+			0.97946167, 1.95892334, -1.40797615, -0.857028961, 1.04067802, 1.3467598, -1.65284157, 1.77527428,
+			-0.367298126, -0.612163544, 1.59162521, 0.857028961, 0.489730835, -0.183649063, 1.22432709, -1.22432709,
+			-0.771450996, -1.48355961, 0.0593423843, 0.712108612, -0.890135765, -1.00882053, -0.474739075, -1.66158676,
+			-1.78027153, 0.296711922, 0.356054306, 1.06816292, -0.356054306, 0.712108612, 1.1275053, -1.8989563,
+			0, 1.81400299, 2.11633682, 2.11633682, 0.0755834579, -0.453500748, 1.66283607, 1.36050224,
+			-0.302333832, -1.36050224, 0.982584953, -2.41867065, -1.20933533, -0.151166916, 1.4360857, -0.982584953,
+			-0.624656677, -0.936985016, 2.49862671, -0.390410423, 2.0301342, -0.468492508, -2.0301342, -0.234246254,
+			0.468492508, -0.234246254, 0.312328339, -2.26438046, -0.858902931, 0.0780820847, -1.56164169, -0,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlQ6_K,
+		data: "a510db2b0ddcbd12d7eaf6d56e960eb63add452513bfd2327176cfbfb99fb9a847a7f0471f0f911a62589272f4b20b64f2befa6ed7f60e878090258e67f29e44c305b15267064f048c9d5fb5ea564916bac81dd3950ddd38454da2daf378ab6f588b9a4bd0e65437758b6391ac65bff8b4d95cde3c45f0d30335be61fb6e69bf88130bc8c98a21ed02e607109af70788444249ae3c9018bfc0c955f72245031c1be748ee467f5a33da66184f1b555e9a15c95af28c974d324679a5820ea348c41cc77b7bbe07c9ed68b8c77d95ffa755c028",
+		want: []float32{
+			-28.0546875, 16.625, 28.0546875, -21.8203125, -3.1171875, 12.46875, -3.1171875, -14.546875,
+			7.2734375, 10.390625, 22.859375, -28.0546875, 14.546875, 22.859375, 31.171875, -27.015625,
+			46.5351562, -27.4980469, 23.2675781, -10.5761719, 61.3417969, 35.9589844, 63.4570312, -38.0742188,
+			65.5722656, 21.1523438, 2.11523438, -65.5722656, -19.0371094, 2.11523438, -52.8808594, 50.765625,
+			31.9511719, -114.111328, 0, 31.9511719, 68.4667969, 68.4667969, -141.498047, 118.675781,
+			-136.933594, -36.515625, -63.9023438, -136.933594, 18.2578125, -63.9023438, -22.8222656, 18.2578125,
+			-63.9023438, -82.1601562, 45.6445312, 136.933594, 104.982422, -118.675781, 63.9023438, 104.982422,
+			-146.0625, 0, -50.2089844, -9.12890625, -114.111328, -63.9023438, -82.1601562, 91.2890625,
+			53.8828125, 36.7382812, 46.5351562, 73.4765625, 78.375, 46.5351562, -26.9414062, -2.44921875,
+			46.5351562, -34.2890625, 41.6367188, 7.34765625, 24.4921875, -61.2304688, 78.375, 51.4335938,
+			-7.53320312, -4.93554688, -7.2734375, 0.51953125, 4.41601562, -1.29882812, -0.779296875, 4.93554688,
+			-6.49414062, -6.49414062, -1.0390625, 7.01367188, 2.85742188, -5.97460938, -5.45507812, -1.55859375,
+			-8.1640625, 44.9023438, 34.6972656, -40.8203125, -34.6972656, -0, 46.9433594, -34.6972656,
+			53.0664062, -42.8613281, 46.9433594, 51.0253906, -30.6152344, -55.1074219, 65.3125, -12.2460938,
+			0.705078125, 3.52539062, 0.705078125, -4.23046875, 13.3964844, -10.5761719, 22.5625, -5.640625,
+			-16.921875, -17.6269531, 9.87109375, -16.921875, 18.3320312, 0.705078125, 16.2167969, 19.7421875,
+			73.328125, 81.046875, -119.640625, 7.71875, 27.015625, 84.90625, 57.890625, 77.1875,
+			46.3125, 50.171875, -65.609375, 81.046875, 100.34375, -38.59375, 34.734375, 23.15625,
+			16.03125, 21.375, -34.734375, -8.015625, 72.140625, -77.484375, 8.015625, -21.375,
+			-13.359375, 8.015625, 37.40625, -26.71875, -8.015625, -64.125, 56.109375, 45.421875,
+			-16.921875, 10.5761719, -21.1523438, -57.1113281, 33.84375, -46.5351562, -8.4609375, 52.8808594,
+			-10.5761719, 10.5761719, -6.34570312, -35.9589844, -25.3828125, 23.2675781, -65.5722656, -16.921875,
+			-55.6640625, 41.7480469, 55.6640625, -83.4960938, 129.882812, -51.0253906, 74.21875, -134.521484,
+			-60.3027344, 23.1933594, -9.27734375, -143.798828, 125.244141, -83.4960938, 41.7480469, -4.63867188,
+			15.8828125, -0, 83.3847656, -19.8535156, 103.238281, -63.53125, 47.6484375, -63.53125,
+			31.765625, -35.7363281, 43.6777344, 83.3847656, 7.94140625, 43.6777344, 47.6484375, 59.5605469,
+			0.185546875, 0.7421875, 0.556640625, -1.07617188, 0.853515625, 0.59375, 0.705078125, -0.705078125,
+			1.0390625, -0.7421875, -0.37109375, 0.705078125, 0.630859375, -0.259765625, 0.81640625, 0.96484375,
+			89.1738281, -79.265625, 23.1191406, -66.0546875, 9.90820312, 6.60546875, 36.3300781, 95.7792969,
+			-75.9628906, 26.421875, 85.8710938, 23.1191406, 72.6601562, 33.0273438, 16.5136719, -49.5410156,
+			-66.2402344, 91.4746094, -34.6972656, 91.4746094, 9.46289062, 12.6171875, -3.15429688, -59.9316406,
+			-50.46875, -41.0058594, 34.6972656, 18.9257812, -53.6230469, 18.9257812, -31.5429688, 85.1660156,
+		},
+	},
+	{
+		name: "quantized",
+		typ:  GgmlQ8_K,
+		data: "45dda0bc430a4fc2c9614b07facecc64efb6090e7c28851598d5c31c4059515a6d22fb2b34e9b7072dafbaa5d0a3c99335569d969d46fa9f7f7ce01026e4daba0b895abc61e9c33dbb065e9979df81719f7f98ed1ad8d6d2b1ffcb6d1e8d0484ff459889b62a360748b416ebf9cee20a08d8752fedb51775f0bc7dc2f418c0a7d6d60df2b4bbcdd40bad7a3068285aad2e31ef84a7a0bb2161c2b02993b47658f8df50667d9541de9395df8bff13d2f8a889f91e7749b63483c8d449fff9101ead37345fcf9d492ccbba55a5121eaed6e708c23e294bfedc2c3318545bf0eec914ddc66202a49694fd18abb91045ce7b3c06b730222f82129816680ae70bef732afc4f018e008301a3fdafffefff1afe510037ffdbffc1ffe7fdaf0003ff340110ffcf00",
+		want: []float32{
+			-1.31566286, -0.196367592, -1.55130398, 1.21747911, 1.08002174, -1.90476573, -1.47275698, -0.137457311,
+			0.117820561, 0.981837988, 1.02111149, -1.96367598, 0.333824903, 1.45312023, -0.176730841, -0.274914622,
+			-2.43495822, -0.785470366, 2.41532135, -0.412371963, 2.04222298, 0.844380677, 1.19784236, -0.549829245,
+			-1.25675261, -1.7476716, -1.59057748, -1.76730835, -2.14040685, -0.667649806, 0.0981837958, -0.844380677,
+			-1.02111149, 0.451645464, 1.43348348, -0.137457311, -0.883654177, 1.59057748, 1.37457311, 1.7869451,
+			0.942564487, 1.82621861, 1.08002174, 2.14040685, -1.04074824, -1.68876135, 1.94403923, 2.08149648,
+			1.94403923, -1.37457311, 0.117820561, 1.90476573, -2.49386835, -2.43495822, 0.628376305, -0.314188153,
+			-0.746196866, 0.549829245, 0.746196866, 1.37457311, -0.216004357, 2.33677435, -1.76730835, 1.33529961,
+			-1.90476573, 0.451645464, 1.19784236, -1.19784236, 1.35493636, -0.117820561, -1.84585536, 2.02258635,
+			-2.37604785, 0.648013055, 2.49386835, -2.21895385, 1.90476573, -2.49386835, 2.04222298, 0.373098433,
+			-0.510555744, 0.785470366, 0.824743927, 0.903290927, 1.55130398, 0.0196367595, 1.04074824, -2.14040685,
+			-0.589102805, 2.25822735, -0.0785470381, 2.43495822, 0.0196367595, -1.35493636, 2.04222298, 2.33677435,
+			1.45312023, -0.824743927, -1.06038499, -0.137457311, -1.41384673, 1.49239373, -0.432008713, 0.412371963,
+			0.137457311, 0.981837988, 0.589102805, -0.196367592, -0.157094076, 0.785470366, -2.29750085, -0.922927678,
+			0.373098433, 1.47275698, -0.451645464, -2.29750085, 0.314188153, 1.33529961, -2.45459485, 1.21747911,
+			0.235641122, -0.471282244, 1.25675261, 1.7476716, 0.824743927, 0.824743927, -0.255277872, 0.274914622,
+			1.49239373, 1.35493636, 1.00147474, 0.864017427, -0.216004357, 1.62985098, -2.39568472, -0.942564487,
+			-2.04222298, -0.785470366, -1.76730835, 1.62985098, -0.903290927, -0.962201238, 0.333824903, 2.43495822,
+			1.7476716, 1.88512897, 1.35493636, -0.648013055, -1.90476573, 1.21747911, 1.57094073, -0.805107117,
+			2.14040685, 1.49239373, -2.31713772, -1.72803485, 0.157094076, 0.648013055, -1.57094073, -2.00294948,
+			-2.45459485, 2.10113335, -1.27638936, 0.667649806, 2.14040685, 2.10113335, 0.648013055, 2.29750085,
+			0.0196367595, -0.373098433, 0.903290927, 0.157094076, 1.72803485, 2.33677435, 0.137457311, -0.589102805,
+			-2.33677435, -1.43348348, 1.45312023, -1.02111149, 2.45459485, 1.09965849, 0.864017427, -1.43348348,
+			0.0196367595, 0.137457311, -0.314188153, -0.589102805, 1.62985098, -1.08002174, -1.02111149, -1.86549211,
+			0.962201238, 1.94403923, -1.43348348, -0.864017427, 1.04074824, 1.37457311, -1.6691246, 1.7869451,
+			-0.353461683, -0.589102805, 1.61021423, 0.824743927, 0.490918994, -0.157094076, 1.21747911, -1.21747911,
+			-0.805107117, -1.47275698, 0.0392735191, 0.706923366, -0.864017427, -1.00147474, -0.471282244, -1.64948785,
+			-1.7869451, 0.314188153, 0.353461683, 1.08002174, -0.392735183, 0.687286556, 1.13893211, -1.92440248,
+			-0.0392735191, 1.80658185, 2.08149648, 2.12076998, 0.0589102805, -0.471282244, 1.6691246, 1.39420998,
+			-0.314188153, -1.35493636, 0.981837988, -2.41532135, -1.17820561, -0.117820561, 1.43348348, -0.942564487,
+			-0.667649806, -0.922927678, 2.47423172, -0.353461683, 2.04222298, -0.432008713, -2.04222298, -0.196367592,
+			0.490918994, -0.216004357, 0.333824903, -2.25822735, -0.824743927, 0.0785470381, -1.55130398, -0.0196367595,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlQ8_K,
+		data: "f085493c0ddcbd12d7eaf6d56e960eb63add452513bfd2327176cfbfb99fb9a847a7f0471f0f911a62589272f4b20b64f2befa6ed7f60e878090258e67f29e44c305b15267064f048c9d5fb5ea564916bac81dd3950ddd38454da2daf378ab6f588b9a4bd0e65437758b6391ac65bff8b4d95cde3c45f0d30335be61fb6e69bf88130bc8c98a21ed02e607109af70788444249ae3c9018bfc0c955f72245031c1be748ee467f5a33da66184f1b555e9a15c95af28c974d324679a5820ea348c41cc77b7bbe07c9ed68b8c77d95ffa75512a6e9f2a4b6a93822ac51fd695fad48dae5aebc2e195ad1de7caae076a866336d88358783fd6bb3a7be6176e386498ee8ebd397145d1e673046bbd02d37c7059b90601ea92ff655eafb41102e5bd9cd1cf2be83",
+		want: []float32{
+			0.159899995, -0.442799985, -0.824099958, 0.221399993, -0.504299998, -0.270599991, -0.122999996, -0.528899968,
+			1.35299993, -1.30379999, 0.172199994, -0.9102, 0.713400006, -0.430499971, 0.848699987, 0.4551,
+			0.233699992, -0.799499989, -0.565799952, 0.61500001, 1.38989997, 1.45139992, -0.602699995, -0.799499989,
+			-0.873299956, -1.19309998, -0.873299956, -1.08239996, 0.873299956, -1.09469998, -0.196799994, 0.873299956,
+			0.381299973, 0.184499994, -1.36529994, 0.319799989, 1.20539999, 1.08239996, -1.35299993, 1.40219998,
+			-0.147599995, -0.959399939, 0.135299996, 1.23000002, -0.172199994, -0.811800003, -0.0737999976, 1.35299993,
+			-0.504299998, -0.122999996, 0.172199994, -1.48829997, -1.57439995, -1.37759995, 0.4551, -1.40219998,
+			1.26689994, -0.172199994, -1.20539999, 0.836399972, -0.75029999, 0.061499998, -0.971699953, 1.0086,
+			1.26689994, 0.0737999976, 0.971699953, 0.0491999984, -1.42680001, -1.2177, 1.16849995, -0.922499955,
+			-0.270599991, 1.05779994, 0.897899985, 0.270599991, -0.860999942, -0.688799977, 0.356700003, -0.553499997,
+			-1.3161, 0.159899995, -0.430499971, 0.688799977, 0.848699987, 0.947099984, -1.15619993, -0.467399985,
+			-0.159899995, 1.47599995, -1.04549992, 1.36529994, 1.08239996, -1.43909991, -1.25459993, 0.922499955,
+			-0.590399981, -0.319799989, 1.03320003, 0.676499963, 1.43909991, -1.43909991, 1.2177, -1.36529994,
+			-1.03320003, 1.24229991, -0.799499989, -0.0983999968, -0.934799969, -0.479699969, 1.1315999, -0.418199986,
+			0.737999976, 0.848699987, -0.196799994, -0.553499997, 0.0368999988, 0.651899993, -0.811800003, 1.19309998,
+			-0.061499998, 1.35299993, 1.29149997, -0.799499989, -1.47599995, 0.233699992, 0.135299996, -0.688799977,
+			-0.676499963, -1.45139992, 0.405900002, -0.233699992, 0.0245999992, -0.319799989, 0.0860999972, 0.196799994,
+			-1.25459993, -0.110699996, 0.0860999972, -1.47599995, 0.836399972, 0.811800003, 0.897899985, -1.0086,
+			0.737999976, -1.37759995, 0.29519999, -0.799499989, -0.787199974, -0.676499963, 1.04549992, -0.110699996,
+			0.418199986, 0.848699987, 0.0368999988, 0.344399989, 0.332099974, -0.307500005, 0.885599971, -0.221399993,
+			0.860999942, 1.56209993, 1.10699999, 0.627299964, -0.467399985, 1.25459993, 0.29519999, 0.971699953,
+			0.332099974, 1.04549992, 1.15619993, -1.25459993, 0.258300006, -0.676499963, 1.10699999, -0.172199994,
+			-1.42680001, -1.29149997, 0.947099984, 0.61500001, 0.860999942, 1.48829997, -1.11930001, -1.54979992,
+			0.172199994, -1.14389992, 0.885599971, -0.737999976, 0.344399989, -0.701099992, 1.51289999, 1.51289999,
+			-0.811800003, 0.0860999972, -0.676499963, -0.233699992, 1.27919996, -0.885599971, -0.701099992, 1.5374999,
+			-1.3161, -0.0122999996, -1.09469998, 1.04549992, 0.221399993, -1.10699999, -0.282899976, -0.172199994,
+			-1.1315999, -0.9102, -1.07009995, 0.688799977, 0.418199986, -1.03320003, 0.996299982, -0.0368999988,
+			1.29149997, 1.16849995, -1.02090001, 0.885599971, -0.467399985, -0.332099974, -1.0086, -0.836399972,
+			0.565799952, 0.307500005, 1.10699999, -0.578099966, -0.418199986, 1.52519989, -1.05779994, -0.393599987,
+			1.45139992, -1.08239996, 1.25459993, 0.627299964, 1.34069991, -1.47599995, 0.651899993, -1.48829997,
+			-1.5374999, -0.0368999988, 1.3161, -0.947099984, -1.09469998, -0.811800003, 1.19309998, 1.45139992,
+			-0.356700003, -1.50059998, 0.897899985, -1.40219998, -0.29519999, -0.258300006, -0.553499997, -1.29149997,
+		},
+	},
+}
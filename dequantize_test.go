@@ -0,0 +1,262 @@
+package gguf
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"testing"
+)
+
+// dequantizeCase is a block of data and its values as decoded by
+// ggml's dequantize_row_* functions. "quantized" blocks come from
+// quantize_row_*_ref on a deterministic input; "random" blocks are
+// random bytes with sane scales.
+type dequantizeCase struct {
+	name string
+	typ  GGML
+	data string
+	want []float32
+}
+
+// testDequantize checks Dequantize against the golden values.
+func testDequantize(t *testing.T, cases []dequantizeCase) {
+	t.Helper()
+
+	for _, c := range cases {
+		data, err := hex.DecodeString(c.data)
+		if err != nil {
+			t.Fatalf("%s/%s: %s", c.typ, c.name, err)
+		}
+
+		got, err := Dequantize(c.typ, data, binary.LittleEndian)
+		if err != nil {
+			t.Errorf("%s/%s: %s", c.typ, c.name, err)
+			continue
+		}
+
+		if len(got) != len(c.want) {
+			t.Errorf("%s/%s: got %d values, want %d", c.typ, c.name, len(got), len(c.want))
+			continue
+		}
+
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("%s/%s: value %d is %v, want %v", c.typ, c.name, i, got[i], c.want[i])
+				break
+			}
+		}
+	}
+}
+
+func TestDequantize(t *testing.T) {
+	testDequantize(t, dequantizeCases)
+}
+
+// Q8_1 has no dequantize_row in ggml. Its want values use the Q8_0
+// formula, which is what ggml's dot products do with the block.
+var dequantizeCases = []dequantizeCase{
+	{
+		name: "quantized",
+		typ:  GgmlQ4_0,
+		data: "d634e456b89b31161d5920ba94674acef43f",
+		want: []float32{
+			-1.20898438, -0.604492188, 0, 0.906738281, -2.11572266, -0.604492188, 1.51123047, 0.302246094,
+			-2.41796875, 0.604492188, -1.20898438, -0.302246094, 0.604492188, 1.81347656, -1.20898438, 2.11572266,
+			1.81347656, -0.906738281, 0.906738281, 0.302246094, -1.51123047, -2.11572266, -2.11572266, -0.906738281,
+			-1.81347656, 0.906738281, 0.302246094, -0.604492188, -1.20898438, 1.20898438, 2.11572266, -1.51123047,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlQ4_0,
+		data: "c028e8470c1b08d25d1653e25d71be1cb0e8",
+		want: []float32{
+			0, -0.037109375, 0.1484375, 0.111328125, 0, -0.22265625, 0.185546875, -0.07421875,
+			-0.185546875, -0.22265625, 0.185546875, -0.259765625, 0.22265625, 0.1484375, -0.296875, 0,
+			0.22265625, -0.1484375, -0.296875, -0.259765625, -0.296875, 0.185546875, -0.111328125, -0.259765625,
+			-0.111328125, 0.22265625, -0.111328125, -0.037109375, 0.111328125, -0.259765625, 0.111328125, 0.22265625,
+		},
+	},
+	{
+		name: "quantized",
+		typ:  GgmlQ4_1,
+		data: "2535d6c0d355a88b31151c5820ba946649bde33f",
+		want: []float32{
+			-1.45336914, -0.810302734, 0.154296875, 1.11889648, -2.09643555, -0.810302734, 1.44042969, 0.154296875,
+			-2.41796875, 0.797363281, -1.13183594, -0.488769531, 0.475830078, 1.76196289, -1.45336914, 2.4050293,
+			1.76196289, -0.810302734, 0.797363281, 0.154296875, -1.45336914, -2.09643555, -2.09643555, -0.810302734,
+			-1.77490234, 1.11889648, 0.475830078, -0.488769531, -1.13183594, 1.11889648, 2.08349609, -1.45336914,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlQ4_1,
+		data: "c0284ca20c1b08d25d1653e25d71be1cb0e82ace",
+		want: []float32{
+			0.433013916, 0.395904541, 0.284576416, 0.061920166, 0.470123291, 0.210357666, 0.099029541, 0.061920166,
+			0.470123291, 0.024810791, 0.507232666, 0.433013916, -0.012298584, 0.284576416, 0.358795166, 0.507232666,
+			-0.012298584, 0.024810791, -0.012298584, 0.470123291, 0.173248291, 0.024810791, 0.173248291, 0.507232666,
+			0.173248291, 0.247467041, 0.395904541, 0.024810791, 0.395904541, 0.507232666, 0.061920166, 0.433013916,
+		},
+	},
+	{
+		name: "quantized",
+		typ:  GgmlQ5_0,
+		data: "d630ccb20d66c7ac6017713c29a2407538cd838be75f",
+		want: []float32{
+			-1.36010742, -0.604492188, 0, 1.05786133, -2.2668457, -0.604492188, 1.36010742, 0.302246094,
+			-2.41796875, 0.755615234, -1.20898438, -0.453369141, 0.453369141, 1.66235352, -1.36010742, 2.2668457,
+			1.81347656, -0.906738281, 0.906738281, 0.151123047, -1.36010742, -1.96459961, -2.11572266, -0.906738281,
+			-1.81347656, 1.05786133, 0.453369141, -0.604492188, -1.20898438, 1.20898438, 2.11572266, -1.66235352,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlQ5_0,
+		data: "c028e8470c1b08d25d1653e25d71be1cb0e82ace266f",
+		want: []float32{
+			-0.296875, -0.51953125, -0.111328125, 0.22265625, -0.482421875, 0.07421875, 0.482421875, 0.037109375,
+			0.51953125, 0.4453125, 0, -0.296875, -0.22265625, -0.07421875, 0.22265625, -0.037109375,
+			-0.59375, -0.111328125, 0.185546875, 0.037109375, -0.408203125, -0.07421875, -0.408203125, -0.333984375,
+			0.408203125, 0.037109375, -0.185546875, 0.51953125, 0.07421875, -0.1484375, -0.51953125, -0.37109375,
+		},
+	},
+	{
+		name: "quantized",
+		typ:  GgmlQ5_1,
+		data: "fa30d6c0ccb20d66b7ab5016613b28a1406428cd828ad75f",
+		want: []float32{
+			-1.3293457, -0.707275391, 0.0703125, 1.00341797, -2.26245117, -0.707275391, 1.31445312, 0.225830078,
+			-2.41796875, 0.692382812, -1.17382812, -0.396240234, 0.381347656, 1.62548828, -1.3293457, 2.40307617,
+			1.78100586, -0.862792969, 0.847900391, 0.225830078, -1.48486328, -1.95141602, -2.10693359, -0.862792969,
+			-1.79589844, 1.00341797, 0.381347656, -0.551757812, -1.17382812, 1.31445312, 2.09204102, -1.64038086,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlQ5_1,
+		data: "c0284ca20c1b08d25d1653e25d71be1cb0e82ace266fd569",
+		want: []float32{
+			0.470123291, 0.210357666, 0.692779541, 0.655670166, 0.470123291, 0.024810791, 0.507232666, 0.433013916,
+			0.581451416, 0.878326416, 0.358795166, 1.10098267, 0.804107666, 0.544342041, 0.173248291, 0.321685791,
+			0.173248291, 0.024810791, 0.173248291, 1.10098267, 0.173248291, 0.247467041, 0.395904541, 0.024810791,
+			0.395904541, 1.10098267, 0.061920166, 0.433013916, 0.655670166, 0.210357666, 1.06387329, 0.804107666,
+		},
+	},
+	{
+		name: "quantized",
+		typ:  GgmlQ8_0,
+		data: "e024bbde01368ade480f8125beec1859ba7e5fd33008b5988fd09f3616e0c24271ab",
+		want: []float32{
+			-1.31396484, -0.647460938, 0.0190429688, 1.02832031, -2.24707031, -0.647460938, 1.37109375, 0.285644531,
+			-2.41845703, 0.704589844, -1.25683594, -0.380859375, 0.45703125, 1.69482422, -1.33300781, 2.39941406,
+			1.80908203, -0.856933594, 0.9140625, 0.15234375, -1.42822266, -1.98046875, -2.15185547, -0.9140625,
+			-1.84716797, 1.02832031, 0.418945312, -0.609375, -1.18066406, 1.25683594, 2.15185547, -1.61865234,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlQ8_0,
+		data: "c028e8470c1b08d25d1653e25d71be1cb0e82ace266fd569cbc99b0c27797c1f2b79",
+		want: []float32{
+			-0.890625, 2.63476562, 0.4453125, 1.00195312, 0.296875, -1.70703125, 3.45117188, 0.81640625,
+			3.08007812, -1.11328125, 3.45117188, 4.19335938, -2.44921875, 1.0390625, -2.96875, -0.890625,
+			1.55859375, -1.85546875, 1.41015625, 4.11914062, -1.59570312, 3.89648438, -1.96679688, -2.04101562,
+			-3.74804688, 0.4453125, 1.44726562, 4.49023438, 4.6015625, 1.15039062, 1.59570312, 4.49023438,
+		},
+	},
+	{
+		name: "quantized",
+		typ:  GgmlQ8_1,
+		data: "e02423c7bbde01368ade480f8125beec1859ba7e5fd33008b5988fd09f3616e0c24271ab",
+		want: []float32{
+			-1.31396484, -0.647460938, 0.0190429688, 1.02832031, -2.24707031, -0.647460938, 1.37109375, 0.285644531,
+			-2.41845703, 0.704589844, -1.25683594, -0.380859375, 0.45703125, 1.69482422, -1.33300781, 2.39941406,
+			1.80908203, -0.856933594, 0.9140625, 0.15234375, -1.42822266, -1.98046875, -2.15185547, -0.9140625,
+			-1.84716797, 1.02832031, 0.418945312, -0.609375, -1.18066406, 1.25683594, 2.15185547, -1.61865234,
+		},
+	},
+	{
+		name: "random",
+		typ:  GgmlQ8_1,
+		data: "c0284ca20c1b08d25d1653e25d71be1cb0e82ace266fd569cbc99b0c27797c1f2b79210b",
+		want: []float32{
+			0.4453125, 1.00195312, 0.296875, -1.70703125, 3.45117188, 0.81640625, 3.08007812, -1.11328125,
+			3.45117188, 4.19335938, -2.44921875, 1.0390625, -2.96875, -0.890625, 1.55859375, -1.85546875,
+			1.41015625, 4.11914062, -1.59570312, 3.89648438, -1.96679688, -2.04101562, -3.74804688, 0.4453125,
+			1.44726562, 4.49023438, 4.6015625, 1.15039062, 1.59570312, 4.49023438, 1.22460938, 0.408203125,
+		},
+	},
+	{
+		name: "special",
+		typ:  GgmlFloat16,
+		data: "00000080003c00bc01000180ff030004ff7b007c00fca4273b3d9933a93d9421",
+		want: []float32{
+			0, -0, 1, -1, 5.96046448e-08, -5.96046448e-08, 6.09755516e-05, 6.10351562e-05,
+			65504, float32(math.Inf(1)), float32(math.Inf(-1)), 0.0298461914, 1.30761719, 0.237426758, 1.41503906, 0.0108947754,
+		},
+	},
+	{
+		name: "special",
+		typ:  GgmlBFloat16,
+		data: "00000080803f80bf010001807f0080007f7f807f80ffd2339d3ecc39d43eca30",
+		want: []float32{
+			0, -0, 1, -1, 9.18354962e-41, -9.18354962e-41, 1.1663108e-38, 1.17549435e-38,
+			3.38953139e+38, float32(math.Inf(1)), float32(math.Inf(-1)), 9.77888703e-08, 0.306640625, 0.000389099121, 0.4140625, 1.46974344e-09,
+		},
+	},
+}
+
+func TestBFloat16ToFloat32(t *testing.T) {
+	cases := []struct {
+		bits uint16
+		want float32
+	}{
+		{0x0000, 0},
+		{0x3f80, 1},
+		{0xbf80, -1},
+		{0x4049, 3.140625},
+		{0x7f80, float32(math.Inf(1))},
+		{0xff80, float32(math.Inf(-1))},
+		{0x7f7f, 3.38953139e+38},
+		{0x0080, 1.17549435e-38},
+		{0x0001, 9.18354962e-41},
+	}
+
+	for _, c := range cases {
+		got := BFloat16ToFloat32(c.bits)
+		if got != c.want {
+			t.Errorf("%#04x: got %v, want %v", c.bits, got, c.want)
+		}
+
+		// The conversion is exact, so converting back gives the bits.
+		if Float32ToBFloat16(got) != c.bits {
+			t.Errorf("%#04x: converted back to %#04x", c.bits, Float32ToBFloat16(got))
+		}
+	}
+
+	// The payload of NaN is kept.
+	nan := math.Float32bits(BFloat16ToFloat32(0x7fc1))
+	if nan != 0x7fc10000 {
+		t.Errorf("NaN: got %#08x, want 0x7fc10000", nan)
+	}
+
+	// Dequantize reads the values in the byte order of the file.
+	for _, byteOrder := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		data := make([]byte, 2*len(cases))
+		for i, c := range cases {
+			byteOrder.PutUint16(data[2*i:], c.bits)
+		}
+
+		got, err := Dequantize(GgmlBFloat16, data, byteOrder)
+		if err != nil {
+			t.Fatalf("%s: %s", byteOrder, err)
+		}
+
+		for i, c := range cases {
+			if got[i] != c.want {
+				t.Errorf("%s: %#04x: got %v, want %v", byteOrder, c.bits, got[i], c.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package gguf
+
+import (
+	"math"
+)
+
+// Float16ToFloat32 converts an IEEE 754 half precision value to
+// float32. Subnormals, infinities and NaN are preserved.
+func Float16ToFloat32(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h) & 0x3ff
+
+	switch {
+	case exp == 0 && mant == 0:
+		return math.Float32frombits(sign)
+
+	case exp == 0:
+		// Subnormal, normalize it.
+		e := uint32(127 - 15 + 1)
+
+		for mant&0x400 == 0 {
+			mant <<= 1
+			e--
+		}
+
+		mant &= 0x3ff
+
+		return math.Float32frombits(sign | e<<23 | mant<<13)
+
+	case exp == 0x1f:
+		return math.Float32frombits(sign | 0xff<<23 | mant<<13)
+
+	default:
+		return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+	}
+}
+
+// Float32ToFloat16 converts a float32 value to IEEE 754 half
+// precision, rounding to nearest even. Values too large become
+// infinite, NaN is kept as NaN.
+func Float32ToFloat16(f float32) uint16 {
+	b := math.Float32bits(f)
+
+	sign := uint16(b>>16) & 0x8000
+	exp := int(b>>23) & 0xff
+	mant := b & 0x7fffff
+
+	if exp == 0xff {
+		if mant != 0 {
+			return sign | 0x7e00 | uint16(mant>>13)
+		}
+
+		return sign | 0x7c00
+	}
+
+	e := exp - 127 + 15
+
+	if e >= 0x1f {
+		return sign | 0x7c00
+	}
+
+	if e <= 0 {
+		// Subnormal or zero.
+		if e < -10 {
+			return sign
+		}
+
+		mant |= 0x800000
+
+		shift := uint(14 - e)
+		r := mant >> shift
+		rem := mant & (1<<shift - 1)
+		halfway := uint32(1) << (shift - 1)
+
+		if rem > halfway || (rem == halfway && r&1 == 1) {
+			r++
+		}
+
+		return sign | uint16(r)
+	}
+
+	r := uint32(e)<<10 | mant>>13
+	rem := mant & 0x1fff
+
+	// A carry out of the mantissa correctly increments the
+	// exponent, possibly all the way to infinity.
+	if rem > 0x1000 || (rem == 0x1000 && r&1 == 1) {
+		r++
+	}
+
+	return sign | uint16(r)
+}
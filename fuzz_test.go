@@ -0,0 +1,150 @@
+package gguf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+// addFuzzSeeds adds small files of every version in both byte orders
+// to the seed corpus.
+func addFuzzSeeds(f *testing.F) {
+	f.Helper()
+
+	for _, byteOrder := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for version := 1; version <= 3; version++ {
+			w := NewWriter(byteOrder)
+			w.Version = version
+
+			add := func(err error) {
+				if err != nil {
+					f.Fatal(err)
+				}
+			}
+
+			add(w.AddMetadata("general.architecture", String, "llama"))
+			add(w.AddMetadata("general.alignment", Uint32, uint32(32)))
+			add(w.AddMetadata("general.file_type", Uint32, uint32(MostlyQ8_0)))
+			add(w.AddMetadata("llama.context_length", Uint32, uint32(4096)))
+			add(w.AddMetadata("llama.embedding_length", Uint32, uint32(64)))
+			add(w.AddMetadata("llama.block_count", Uint32, uint32(2)))
+			add(w.AddMetadata("llama.attention.head_count", Uint32, uint32(4)))
+			add(w.AddMetadata("llama.rope.freq_base", Float32, float32(10000)))
+			add(w.AddArray("tokenizer.ggml.tokens", String, []string{"<s>", " the"}))
+			add(w.AddArray("tokenizer.ggml.scores", Float32, []float32{0, -1}))
+			add(w.AddArray("llama.attention.head_count_kv", Int32, []int32{4, 2}))
+			add(w.AddArray("x.empty", Int32, []int32{}))
+			add(w.AddArray("x.nested", Array, []MetaArray{{Type: Uint8, Value: []uint8{1}}}))
+			add(w.AddTensor("blk.0.attn_q.weight", []uint64{32, 2}, GgmlQ8_0, bytes.NewReader(make([]byte, 68))))
+			add(w.AddTensor("blk.0.attn_norm.weight", []uint64{4}, GgmlFloat32, bytes.NewReader(make([]byte, 16))))
+			add(w.AddTensor("blk.1.ffn_down.weight", []uint64{256}, GgmlQ4_K, bytes.NewReader(make([]byte, 144))))
+
+			var b bytes.Buffer
+
+			_, err := w.WriteTo(&b)
+			if err != nil {
+				f.Fatal(err)
+			}
+
+			f.Add(b.Bytes())
+		}
+	}
+}
+
+func FuzzOpen(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r, err := Open(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		_, _ = r.TensorSizeErr()
+		_ = r.Verify()
+		_, _ = r.InferFiletype()
+
+		// Tensor data is always within the file, so rewriting it
+		// produces at most a little more than the input.
+		_, _ = NewWriterFrom(r).WriteTo(io.Discard)
+	})
+}
+
+func FuzzMetadata(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r, err := Open(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		m := r.Metadata
+
+		for key := range m {
+			_, _ = m.Int(key)
+			_, _ = m.Int64(key)
+			_, _ = m.Uint64(key)
+			_, _ = m.Float64(key)
+			_, _ = m.Bool(key)
+			_, _ = m.Any(key)
+			_, _ = m.String(key)
+			_, _ = m.Strings(key)
+			_, _ = m.Arrays(key)
+			_, _ = m.Ints(key)
+			_, _ = m.Int64s(key)
+			_, _ = m.Uint64s(key)
+			_, _ = m.Float64s(key)
+			_, _ = MetaValue[[]string](m, key)
+			_, _ = MetaValueNumber[uint8](m, key)
+			_, _ = MetaValueNumbers[int16](m, key)
+		}
+
+		_ = m.Check()
+		_, _ = m.ArchConfig()
+
+		var params struct {
+			ContextLength int       `gguf:"{arch}.context_length"`
+			HeadCountKV   []int     `gguf:"{arch}.attention.head_count_kv"`
+			Tokens        []string  `gguf:"tokenizer.ggml.tokens"`
+			Scores        []float32 `gguf:"tokenizer.ggml.scores"`
+		}
+
+		_ = m.Unmarshal(&params)
+
+		for _, e := range r.Entries {
+			_, _ = json.Marshal(e)
+		}
+	})
+}
+
+func FuzzTensor(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r, err := Open(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		for i := range r.Tensors {
+			tensor := &r.Tensors[i]
+
+			_, _ = tensor.SizeErr()
+
+			sr, err := tensor.SectionReader()
+			if err != nil {
+				continue
+			}
+
+			n, err := io.Copy(io.Discard, sr)
+			if err != nil || n != sr.Size() {
+				t.Fatalf("tensor %q: read %d of %d bytes: %v", tensor.Name, n, sr.Size(), err)
+			}
+
+			_, _ = tensor.Dequantize()
+		}
+	})
+}
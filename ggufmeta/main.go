@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
@@ -14,12 +15,93 @@ func print[T any](name string, val T) {
 }
 
 func printArray[T any](name string, val []T) {
-	fmt.Printf("Metadata: %s: [\033[32m%d\033[0m]\033[36m%T\033[0m\n", name, len(val), val[0])
+	var zero T
+
+	fmt.Printf("Metadata: %s: [\033[32m%d\033[0m]\033[36m%T\033[0m\n", name, len(val), zero)
+}
+
+// describeArray returns a short description of the length and element
+// types of a (possibly nested) array.
+func describeArray(a gguf.MetaArray) string {
+	if a.Type != gguf.Array {
+		return fmt.Sprintf("[\033[32m%d\033[0m]\033[36m%s\033[0m", a.Len(), a.Type)
+	}
+
+	nested, _ := gguf.MetaArrayValues[gguf.MetaArray](a)
+
+	elems := make([]string, len(nested))
+	for i, n := range nested {
+		elems[i] = describeArray(n)
+	}
+
+	return fmt.Sprintf("[\033[32m%d\033[0m]{%s}", len(nested), strings.Join(elems, ", "))
+}
+
+// verify prints all problems found in the file, and exits with a
+// non-zero status if any of them are errors.
+func verify(filename string) {
+	g, err := gguf.OpenFile(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+
+	defer g.Close()
+
+	findings := append(g.Verify(), g.Metadata.Check()...)
+
+	for _, f := range findings {
+		fmt.Println(f)
+	}
+
+	if len(findings) == 0 {
+		fmt.Println("No problems found")
+	}
+
+	if gguf.HasErrors(findings) {
+		g.Close()
+		os.Exit(1)
+	}
+}
+
+// printJSON prints the header of the file as JSON.
+func printJSON(filename string) {
+	g, err := gguf.OpenFile(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+
+	defer g.Close()
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	err = enc.Encode(g.Header())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		g.Close()
+		os.Exit(1)
+	}
 }
 
 func main() {
+	if len(os.Args) == 3 && os.Args[1] == "verify" {
+		verify(os.Args[2])
+
+		return
+	}
+
+	if len(os.Args) == 3 && os.Args[1] == "--json" {
+		printJSON(os.Args[2])
+
+		return
+	}
+
 	if len(os.Args) != 2 {
 		fmt.Printf("Usage: %s <file>\n", os.Args[0])
+		fmt.Printf("       %s --json <file>\n", os.Args[0])
+		fmt.Printf("       %s verify <file>\n", os.Args[0])
 		os.Exit(1)
 	}
 
@@ -28,6 +110,8 @@ func main() {
 		panic(err)
 	}
 
+	defer g.Close()
+
 	keys := make([]string, 0, len(g.Metadata))
 	for k := range g.Metadata {
 		keys = append(keys, k)
@@ -83,11 +167,26 @@ func main() {
 		case []float64:
 			printArray(k, vv)
 
+		case []gguf.MetaArray:
+			fmt.Printf("Metadata: %s: %s\n", k, describeArray(gguf.MetaArray{Type: gguf.Array, Value: vv}))
+
 		default:
 			fmt.Printf("%s: %T\n", k, v)
 		}
 	}
 
+	if _, found := g.Metadata["general.file_type"]; !found {
+		ftype, err := g.InferFiletype()
+		if err == nil {
+			fmt.Printf("Metadata: general.file_type: \033[33m%v\033[0m (inferred from the tensors)\n", ftype)
+		}
+	}
+
+	// Unknown keys and values of the wrong type are pointed out.
+	for _, f := range g.Metadata.Check() {
+		fmt.Printf("\033[33m%s\033[0m\n", f)
+	}
+
 	for _, t := range g.Tensors {
 		dims := make([]string, len(t.Dimensions))
 
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureStdout returns what f writes to os.Stdout.
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	f()
+
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(out)
+}
+
+func TestPrintArrayEmpty(t *testing.T) {
+	out := captureStdout(t, func() {
+		printArray("x.empty", []int32{})
+		printArray("x.strings", []string{})
+	})
+
+	for _, want := range []string{"x.empty: [\033[32m0\033[0m]\033[36mint32", "x.strings: [\033[32m0\033[0m]\033[36mstring"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output %q does not contain %q", out, want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/abrander/gguf"
+)
+
+// overrides collects -override flags.
+type overrides []gguf.RequantizeOverride
+
+func (o *overrides) String() string {
+	return fmt.Sprintf("%d overrides", len(*o))
+}
+
+func (o *overrides) Set(value string) error {
+	pattern, typ, found := strings.Cut(value, "=")
+	if !found {
+		return fmt.Errorf("override must be of the form <regex>=<type>, got %q", value)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+
+	g, err := gguf.ParseGGML(typ)
+	if err != nil {
+		return err
+	}
+
+	*o = append(*o, gguf.RequantizeOverride{Pattern: re, Type: g})
+
+	return nil
+}
+
+func main() {
+	var policy gguf.RequantizePolicy
+	var o overrides
+
+	imatrix := flag.String("imatrix", "", "importance matrix `file` used to weigh quantization")
+	flag.Var(&o, "override", "use `regex=type` for tensors with matching names, can be repeated")
+	flag.BoolVar(&policy.KeepNormsF32, "keep-norms-f32", true, "store one-dimensional float and quantized tensors as F32")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] <input> <output> <type>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if flag.NArg() != 3 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	typ, err := gguf.ParseGGML(flag.Arg(2))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	policy.Type = typ
+	policy.Overrides = o
+
+	if *imatrix != "" {
+		policy.Imatrix, err = gguf.OpenImatrixFile(*imatrix)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	g, err := gguf.OpenFile(flag.Arg(0))
+	if err != nil {
+		panic(err)
+	}
+
+	defer g.Close()
+
+	if policy.Imatrix != nil {
+		err = policy.Imatrix.Validate(g)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	for i := range g.Tensors {
+		t := &g.Tensors[i]
+
+		fmt.Printf("Tensor: %s: \033[36m%s\033[0m → \033[36m%s\033[0m\n", t.Name, t.Type, policy.TargetType(t))
+	}
+
+	out, err := os.Create(flag.Arg(1))
+	if err != nil {
+		panic(err)
+	}
+
+	err = gguf.Requantize(g, out, policy)
+	if err != nil {
+		out.Close()
+		os.Remove(flag.Arg(1))
+		panic(err)
+	}
+
+	err = out.Close()
+	if err != nil {
+		panic(err)
+	}
+}
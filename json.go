@@ -0,0 +1,405 @@
+package gguf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// MarshalText implements encoding.TextMarshaler.
+func (t Type) MarshalText() ([]byte, error) {
+	if t > Float64 {
+		return nil, fmt.Errorf("invalid type: %d", t)
+	}
+
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *Type) UnmarshalText(text []byte) error {
+	if string(text) == Array.String() {
+		*t = Array
+
+		return nil
+	}
+
+	typ, err := parseType(string(text))
+	if err != nil {
+		return err
+	}
+
+	*t = typ
+
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (g GGML) MarshalText() ([]byte, error) {
+	return []byte(g.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Both the names
+// accepted by ParseGGML() and the GGML(n) form used by String() for
+// unknown types are accepted.
+func (g *GGML) UnmarshalText(text []byte) error {
+	var n int
+
+	_, err := fmt.Sscanf(string(text), "GGML(%d)", &n)
+	if err == nil {
+		*g = GGML(n)
+
+		return nil
+	}
+
+	parsed, err := ParseGGML(string(text))
+	if err != nil {
+		return err
+	}
+
+	*g = parsed
+
+	return nil
+}
+
+// metaEntryJSON is the JSON representation of a MetaEntry.
+type metaEntryJSON struct {
+	Key       string          `json:"key"`
+	Type      Type            `json:"type"`
+	ArrayType *Type           `json:"array_type,omitempty"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// metaArrayJSON is the JSON representation of a MetaArray.
+type metaArrayJSON struct {
+	Type  Type            `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// MarshalJSON implements json.Marshaler. The value is stored with its
+// GGUF type, so it can be decoded to the exact same entry.
+func (e MetaEntry) MarshalJSON() ([]byte, error) {
+	j := metaEntryJSON{Key: e.Key, Type: e.Type}
+
+	var err error
+
+	if e.Type == Array {
+		arrayType := e.ArrayType
+		j.ArrayType = &arrayType
+
+		j.Value, err = marshalJSONArray(e.ArrayType, e.Value)
+	} else {
+		j.Value, err = marshalJSONScalar(e.Type, e.Value)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("metadata value %q: %w", e.Key, err)
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *MetaEntry) UnmarshalJSON(data []byte) error {
+	var j metaEntryJSON
+
+	err := json.Unmarshal(data, &j)
+	if err != nil {
+		return err
+	}
+
+	entry := MetaEntry{Key: j.Key, Type: j.Type}
+
+	if j.Type == Array {
+		if j.ArrayType == nil {
+			return fmt.Errorf("metadata value %q: array without array_type", j.Key)
+		}
+
+		entry.ArrayType = *j.ArrayType
+		entry.Value, err = unmarshalJSONArray(entry.ArrayType, j.Value)
+	} else {
+		entry.Value, err = unmarshalJSONScalar(j.Type, j.Value)
+	}
+
+	if err != nil {
+		return fmt.Errorf("metadata value %q: %w", j.Key, err)
+	}
+
+	*e = entry
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a MetaArray) MarshalJSON() ([]byte, error) {
+	value, err := marshalJSONArray(a.Type, a.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(metaArrayJSON{Type: a.Type, Value: value})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *MetaArray) UnmarshalJSON(data []byte) error {
+	var j metaArrayJSON
+
+	err := json.Unmarshal(data, &j)
+	if err != nil {
+		return err
+	}
+
+	value, err := unmarshalJSONArray(j.Type, j.Value)
+	if err != nil {
+		return err
+	}
+
+	*a = MetaArray{Type: j.Type, Value: value}
+
+	return nil
+}
+
+// jsonBytes is the JSON representation of a string that is not valid
+// UTF-8, and would be altered by encoding/json.
+type jsonBytes struct {
+	Base64 string `json:"base64"`
+}
+
+// marshalJSONScalar returns the JSON encoding of a scalar value of
+// type typ. Floats are encoded with the shortest representation that
+// parses back to the same value, and NaN and infinities as strings.
+// NaN includes the bits, as the sign and payload would be lost
+// otherwise.
+func marshalJSONScalar(typ Type, value interface{}) (json.RawMessage, error) {
+	v, err := normalizeScalar(typ, value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch vv := v.(type) {
+	case float32:
+		// The bits are taken before converting, as the conversion
+		// may alter a NaN.
+		return marshalJSONFloat(float64(vv), uint64(math.Float32bits(vv)), 32)
+
+	case float64:
+		return marshalJSONFloat(vv, math.Float64bits(vv), 64)
+
+	case string:
+		if !utf8.ValidString(vv) {
+			return json.Marshal(jsonBytes{Base64: base64.StdEncoding.EncodeToString([]byte(vv))})
+		}
+
+		return json.Marshal(vv)
+
+	default:
+		return json.Marshal(vv)
+	}
+}
+
+// nanPrefix starts the JSON representation of NaN, followed by the
+// bits in hexadecimal and ")", like "NaN(0x7fc00000)".
+const nanPrefix = "NaN("
+
+// marshalJSONFloat encodes f with the precision of bitSize. bits are
+// the bits of the original bitSize float.
+func marshalJSONFloat(f float64, bits uint64, bitSize int) (json.RawMessage, error) {
+	if math.IsNaN(f) {
+		return json.Marshal(fmt.Sprintf("%s0x%0*x)", nanPrefix, bitSize/4, bits))
+	}
+
+	if math.IsInf(f, 0) {
+		return json.Marshal(strconv.FormatFloat(f, 'g', -1, bitSize))
+	}
+
+	return json.RawMessage(strconv.FormatFloat(f, 'g', -1, bitSize)), nil
+}
+
+// marshalJSONArray returns the JSON encoding of an array with elements
+// of type elemType.
+func marshalJSONArray(elemType Type, value interface{}) (json.RawMessage, error) {
+	v, err := normalizeArray(elemType, value)
+	if err != nil {
+		return nil, err
+	}
+
+	if elemType == Array {
+		return json.Marshal(v)
+	}
+
+	rv := reflect.ValueOf(v)
+
+	var b bytes.Buffer
+
+	b.WriteByte('[')
+
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		e, err := marshalJSONScalar(elemType, rv.Index(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+
+		b.Write(e)
+	}
+
+	b.WriteByte(']')
+
+	return b.Bytes(), nil
+}
+
+// unmarshalJSONScalar decodes a scalar value of type typ. Numbers are
+// parsed directly from their JSON representation, so 64-bit integers
+// keep their exact value.
+func unmarshalJSONScalar(typ Type, data json.RawMessage) (interface{}, error) {
+	switch typ {
+	case Bool:
+		var b bool
+
+		err := json.Unmarshal(data, &b)
+
+		return b, err
+
+	case String:
+		var s string
+
+		err := json.Unmarshal(data, &s)
+		if err == nil {
+			return s, nil
+		}
+
+		var raw jsonBytes
+
+		err = json.Unmarshal(data, &raw)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := base64.StdEncoding.DecodeString(raw.Base64)
+
+		return string(b), err
+
+	case Float32, Float64:
+		bitSize := 64
+		if typ == Float32 {
+			bitSize = 32
+		}
+
+		var s string
+
+		// NaN and infinities are stored as strings.
+		if json.Unmarshal(data, &s) != nil {
+			s = string(data)
+		}
+
+		// Plain "NaN" is accepted as well, and parsed below.
+		if strings.HasPrefix(s, nanPrefix) && strings.HasSuffix(s, ")") {
+			return parseJSONNaN(s[len(nanPrefix):len(s)-1], bitSize)
+		}
+
+		f, err := strconv.ParseFloat(s, bitSize)
+		if err != nil {
+			return nil, err
+		}
+
+		if typ == Float32 {
+			return float32(f), nil
+		}
+
+		return f, nil
+
+	case Uint8, Uint16, Uint32, Uint64:
+		u, err := strconv.ParseUint(string(data), 10, int(goTypes[typ].Size())*8)
+		if err != nil {
+			return nil, err
+		}
+
+		return reflect.ValueOf(u).Convert(goTypes[typ]).Interface(), nil
+
+	case Int8, Int16, Int32, Int64:
+		i, err := strconv.ParseInt(string(data), 10, int(goTypes[typ].Size())*8)
+		if err != nil {
+			return nil, err
+		}
+
+		return reflect.ValueOf(i).Convert(goTypes[typ]).Interface(), nil
+
+	default:
+		return nil, fmt.Errorf("invalid scalar type: %s", typ)
+	}
+}
+
+// parseJSONNaN returns the NaN with the hexadecimal bits.
+func parseJSONNaN(bits string, bitSize int) (interface{}, error) {
+	u, err := strconv.ParseUint(bits, 0, bitSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if bitSize == 32 {
+		f := math.Float32frombits(uint32(u))
+		if !math.IsNaN(float64(f)) {
+			return nil, fmt.Errorf("%s is not a NaN", bits)
+		}
+
+		return f, nil
+	}
+
+	f := math.Float64frombits(u)
+	if !math.IsNaN(f) {
+		return nil, fmt.Errorf("%s is not a NaN", bits)
+	}
+
+	return f, nil
+}
+
+// unmarshalJSONArray decodes an array with elements of type elemType.
+func unmarshalJSONArray(elemType Type, data json.RawMessage) (interface{}, error) {
+	if elemType == Array {
+		var a []MetaArray
+
+		err := json.Unmarshal(data, &a)
+		if err != nil {
+			return nil, err
+		}
+
+		if a == nil {
+			a = []MetaArray{}
+		}
+
+		return a, nil
+	}
+
+	goType, found := goTypes[elemType]
+	if !found {
+		return nil, fmt.Errorf("unsupported array type: %s", elemType)
+	}
+
+	var elems []json.RawMessage
+
+	err := json.Unmarshal(data, &elems)
+	if err != nil {
+		return nil, err
+	}
+
+	a := reflect.MakeSlice(reflect.SliceOf(goType), len(elems), len(elems))
+
+	for i, e := range elems {
+		v, err := unmarshalJSONScalar(elemType, e)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+
+		a.Index(i).Set(reflect.ValueOf(v))
+	}
+
+	return a.Interface(), nil
+}
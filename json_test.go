@@ -0,0 +1,169 @@
+package gguf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// jsonTestWriter returns a writer with a value of every type, including
+// values JSON can't hold as plain numbers.
+func jsonTestWriter(t *testing.T, byteOrder binary.ByteOrder) *Writer {
+	t.Helper()
+
+	w := NewWriter(byteOrder)
+
+	scalars := []struct {
+		typ   Type
+		value interface{}
+	}{
+		{Uint8, uint8(255)},
+		{Int8, int8(-128)},
+		{Uint16, uint16(65535)},
+		{Int16, int16(-32768)},
+		{Uint32, uint32(math.MaxUint32)},
+		{Int32, int32(math.MinInt32)},
+		{Float32, float32(0.1)},
+		{Bool, true},
+		{String, "llama"},
+		{Uint64, uint64(1<<53 + 1)},
+		{Uint64, uint64(math.MaxUint64)},
+		{Int64, int64(math.MinInt64)},
+		{Int64, int64(-(1<<53 + 1))},
+		{Float64, 0.1},
+		{Float64, math.Copysign(0, -1)},
+		{Float32, float32(math.Inf(-1))},
+		{Float64, math.Inf(1)},
+		{Float32, math.Float32frombits(0x7fc00123)},
+		{Float32, math.Float32frombits(0xffc00001)},
+		{Float64, math.Float64frombits(0xfff8000000000abc)},
+		{Float64, math.NaN()},
+		{String, "invalid \xff UTF-8"},
+	}
+
+	for i, s := range scalars {
+		key := "general.architecture"
+		if i > 0 {
+			key = "test.scalar." + s.typ.String() + "." + string(rune('a'+i))
+		}
+
+		err := w.AddMetadata(key, s.typ, s.value)
+		if err != nil {
+			t.Fatalf("%s: %s", key, err)
+		}
+	}
+
+	arrays := []struct {
+		typ   Type
+		value interface{}
+	}{
+		{Uint64, []uint64{0, 1<<63 + 1}},
+		{Float32, []float32{1.5, float32(math.Inf(1)), math.Float32frombits(0x7f800001)}},
+		{String, []string{}},
+		{Array, []MetaArray{
+			{Type: Int16, Value: []int16{-1, 2}},
+			{Type: String, Value: []string{}},
+			{Type: Array, Value: []MetaArray{
+				{Type: Float64, Value: []float64{math.Float64frombits(0x7ff0000000000001)}},
+				{Type: Bool, Value: []bool{true, false}},
+			}},
+		}},
+	}
+
+	for i, a := range arrays {
+		key := "test.array." + string(rune('a'+i))
+
+		err := w.AddArray(key, a.typ, a.value)
+		if err != nil {
+			t.Fatalf("%s: %s", key, err)
+		}
+	}
+
+	err := w.AddTensor("a.weight", []uint64{4, 2}, GgmlFloat32, bytes.NewReader(make([]byte, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return w
+}
+
+func TestHeaderJSONRoundTrip(t *testing.T) {
+	for _, byteOrder := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		w := jsonTestWriter(t, byteOrder)
+
+		var original bytes.Buffer
+
+		_, err := w.WriteHeaderTo(&original)
+		if err != nil {
+			t.Fatalf("%s: WriteHeaderTo: %s", byteOrder, err)
+		}
+
+		r, _ := encode(t, w)
+
+		data, err := json.Marshal(r.Header())
+		if err != nil {
+			t.Fatalf("%s: Marshal: %s", byteOrder, err)
+		}
+
+		var h Header
+
+		err = json.Unmarshal(data, &h)
+		if err != nil {
+			t.Fatalf("%s: Unmarshal: %s", byteOrder, err)
+		}
+
+		w, err = NewWriterFromHeader(&h)
+		if err != nil {
+			t.Fatalf("%s: NewWriterFromHeader: %s", byteOrder, err)
+		}
+
+		var b bytes.Buffer
+
+		_, err = w.WriteHeaderTo(&b)
+		if err != nil {
+			t.Fatalf("%s: WriteHeaderTo: %s", byteOrder, err)
+		}
+
+		if !bytes.Equal(b.Bytes(), original.Bytes()) {
+			t.Errorf("%s: header differs after a JSON round trip", byteOrder)
+		}
+	}
+}
+
+func TestJSONNaN(t *testing.T) {
+	cases := []struct {
+		typ   Type
+		value interface{}
+		json  string
+	}{
+		{Float32, math.Float32frombits(0xffc00001), `"NaN(0xffc00001)"`},
+		{Float64, math.Float64frombits(0x7ff8000000000abc), `"NaN(0x7ff8000000000abc)"`},
+		{Float32, float32(math.Inf(-1)), `"-Inf"`},
+	}
+
+	for _, c := range cases {
+		data, err := marshalJSONScalar(c.typ, c.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != c.json {
+			t.Errorf("expected %s, got %s", c.json, data)
+		}
+	}
+
+	// Plain NaN is accepted.
+	v, err := unmarshalJSONScalar(Float64, json.RawMessage(`"NaN"`))
+	if err != nil || !math.IsNaN(v.(float64)) {
+		t.Errorf("expected NaN, got %v, %v", v, err)
+	}
+
+	for _, invalid := range []string{`"NaN(0x3f800000)"`, `"NaN(0x1ffffffff)"`, `"NaN(x)"`} {
+		_, err = unmarshalJSONScalar(Float32, json.RawMessage(invalid))
+		if err == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
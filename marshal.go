@@ -0,0 +1,212 @@
+package gguf
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// kindTypes maps Go kinds to the GGUF type they are stored as. int and
+// uint are missing on purpose, their size depends on the platform and
+// the GGUF type must be given with the type tag option.
+var kindTypes = map[reflect.Kind]Type{
+	reflect.Uint8:   Uint8,
+	reflect.Int8:    Int8,
+	reflect.Uint16:  Uint16,
+	reflect.Int16:   Int16,
+	reflect.Uint32:  Uint32,
+	reflect.Int32:   Int32,
+	reflect.Float32: Float32,
+	reflect.Bool:    Bool,
+	reflect.String:  String,
+	reflect.Uint64:  Uint64,
+	reflect.Int64:   Int64,
+	reflect.Float64: Float64,
+}
+
+// MarshalMetadata returns metadata entries for the tagged fields of the
+// struct v, in field order. The tags are the same as for
+// Metadata.Unmarshal(). The GGUF type of a value follows the Go type of
+// the field, so a uint32 is stored as Uint32 and a []string as an
+// array of String. The type option sets the type explicitly, or the
+// element type for arrays:
+//
+//	BlockCount int     `gguf:"{arch}.block_count,type=uint32"`
+//	HeadCount  []int   `gguf:"{arch}.attention.head_count,type=uint32"`
+//	FreqBase   float32 `gguf:"{arch}.rope.freq_base,omitempty"`
+//
+// The type option is required for int and uint, and for interface
+// values of those. Values are only converted if they fit the type
+// exactly. Nil pointers and interfaces are skipped, and so are zero
+// values and empty arrays of fields with the omitempty option. Slices
+// of slices are stored as arrays of arrays.
+//
+// {arch} is replaced by the value of the field tagged
+// general.architecture.
+func MarshalMetadata(v interface{}) ([]MetaEntry, error) {
+	return marshalMetadata(v, "")
+}
+
+// marshalMetadata implements MarshalMetadata(). arch is used for
+// {arch} if v has no general.architecture field.
+func marshalMetadata(v interface{}, arch string) ([]MetaEntry, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("marshal needs a struct, got %T", v)
+	}
+
+	fields, err := taggedFields(rv)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range fields {
+		if f.tag.key == "general.architecture" && f.value.Kind() == reflect.String && f.value.String() != "" {
+			arch = f.value.String()
+		}
+	}
+
+	entries := make([]MetaEntry, 0, len(fields))
+	keys := make(map[string]bool)
+
+	for _, f := range fields {
+		key, err := expandKey(f.tag.key, arch)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.field.Name, err)
+		}
+
+		if keys[key] {
+			return nil, fmt.Errorf("field %s: duplicate key %q", f.field.Name, key)
+		}
+
+		keys[key] = true
+
+		value := f.value
+		for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				break
+			}
+
+			value = value.Elem()
+		}
+
+		// Nil pointers and interfaces are treated as missing values.
+		if (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) && value.IsNil() {
+			continue
+		}
+
+		if f.tag.omitempty && (value.IsZero() || (value.Kind() == reflect.Slice && value.Len() == 0)) {
+			continue
+		}
+
+		e, err := marshalEntry(key, value, f.tag)
+		if err != nil {
+			return nil, fmt.Errorf("metadata value %q: field %s: %w", key, f.field.Name, err)
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// marshalEntry returns the metadata entry for value.
+func marshalEntry(key string, value reflect.Value, tag fieldTag) (MetaEntry, error) {
+	if isArray(value) {
+		elemType, a, err := marshalArray(value, tag)
+		if err != nil {
+			return MetaEntry{}, err
+		}
+
+		return MetaEntry{Key: key, Type: Array, ArrayType: elemType, Value: a}, nil
+	}
+
+	typ, err := valueType(value.Type(), tag)
+	if err != nil {
+		return MetaEntry{}, err
+	}
+
+	v, err := marshalScalar(value, typ)
+	if err != nil {
+		return MetaEntry{}, err
+	}
+
+	return MetaEntry{Key: key, Type: typ, Value: v}, nil
+}
+
+// isArray returns true if value is stored as a GGUF array.
+func isArray(value reflect.Value) bool {
+	return value.Kind() == reflect.Slice || value.Kind() == reflect.Array
+}
+
+// valueType returns the GGUF type of a scalar of type t.
+func valueType(t reflect.Type, tag fieldTag) (Type, error) {
+	if tag.hasTyp {
+		return tag.typ, nil
+	}
+
+	typ, found := kindTypes[t.Kind()]
+	if !found {
+		return 0, fmt.Errorf("the GGUF type of %s must be given with the type tag option", t)
+	}
+
+	return typ, nil
+}
+
+// marshalScalar converts value to the Go type used for typ.
+func marshalScalar(value reflect.Value, typ Type) (interface{}, error) {
+	goType, found := goTypes[typ]
+	if !found {
+		return nil, fmt.Errorf("invalid scalar type: %s", typ)
+	}
+
+	dst := reflect.New(goType).Elem()
+
+	err := assignValue(dst, value.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	return dst.Interface(), nil
+}
+
+// marshalArray returns the element type and the value of the array
+// value. The type tag option sets the type of the innermost elements.
+func marshalArray(value reflect.Value, tag fieldTag) (Type, interface{}, error) {
+	elem := value.Type().Elem()
+
+	if elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array {
+		a := make([]MetaArray, value.Len())
+
+		for i := range a {
+			typ, v, err := marshalArray(value.Index(i), tag)
+			if err != nil {
+				return 0, nil, fmt.Errorf("element %d: %w", i, err)
+			}
+
+			a[i] = MetaArray{Type: typ, Value: v}
+		}
+
+		return Array, a, nil
+	}
+
+	typ, err := valueType(elem, tag)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	goType := goTypes[typ]
+	a := reflect.MakeSlice(reflect.SliceOf(goType), value.Len(), value.Len())
+
+	for i := 0; i < value.Len(); i++ {
+		err = assignValue(a.Index(i), value.Index(i).Interface())
+		if err != nil {
+			return 0, nil, fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+
+	return typ, a.Interface(), nil
+}
@@ -0,0 +1,215 @@
+package gguf
+
+import (
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type marshalTest struct {
+	Arch       string      `gguf:"general.architecture"`
+	BlockCount int         `gguf:"{arch}.block_count,type=uint32"`
+	HeadCount  []int       `gguf:"{arch}.attention.head_count,type=uint32"`
+	FreqBase   float32     `gguf:"{arch}.rope.freq_base,omitempty"`
+	Scale      *float64    `gguf:"{arch}.rope.scale"`
+	Tokens     []string    `gguf:"tokenizer.ggml.tokens,omitempty"`
+	Nested     [][]int32   `gguf:"test.nested"`
+	Any        interface{} `gguf:"test.any,type=int64"`
+	Flag       bool        `gguf:"test.flag,omitempty"`
+}
+
+// marshalRoundTrip writes v with Writer.SetMetadataFrom(), reads the
+// file back and unmarshals the metadata into a new value of the same
+// type.
+func marshalRoundTrip(t *testing.T, v interface{}) (*Reader, interface{}) {
+	t.Helper()
+
+	w := NewWriter(binary.LittleEndian)
+
+	err := w.SetMetadataFrom(v)
+	if err != nil {
+		t.Fatalf("SetMetadataFrom: %s", err)
+	}
+
+	r, _ := encode(t, w)
+
+	out := reflect.New(reflect.TypeOf(v).Elem())
+
+	err = r.Metadata.Unmarshal(out.Interface())
+	if err != nil {
+		t.Fatalf("Unmarshal: %s", err)
+	}
+
+	return r, out.Interface()
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	scale := 0.25
+
+	in := &marshalTest{
+		Arch:       "llama",
+		BlockCount: 32,
+		HeadCount:  []int{32, 32, 8},
+		FreqBase:   10000,
+		Scale:      &scale,
+		Tokens:     []string{"<s>", " the"},
+		Nested:     [][]int32{{1, -2}, {}, {3}},
+		Any:        int64(-7),
+		Flag:       true,
+	}
+
+	r, out := marshalRoundTrip(t, in)
+
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("expected %+v, got %+v", in, out)
+	}
+
+	types := map[string][2]Type{
+		"general.architecture":       {String, 0},
+		"llama.block_count":          {Uint32, 0},
+		"llama.attention.head_count": {Array, Uint32},
+		"llama.rope.freq_base":       {Float32, 0},
+		"llama.rope.scale":           {Float64, 0},
+		"tokenizer.ggml.tokens":      {Array, String},
+		"test.nested":                {Array, Array},
+		"test.any":                   {Int64, 0},
+		"test.flag":                  {Bool, 0},
+	}
+
+	if len(r.Entries) != len(types) {
+		t.Fatalf("expected %d entries, got %d", len(types), len(r.Entries))
+	}
+
+	for _, e := range r.Entries {
+		expected, found := types[e.Key]
+		if !found {
+			t.Errorf("unexpected key %q", e.Key)
+
+			continue
+		}
+
+		if e.Type != expected[0] || (e.Type == Array && e.ArrayType != expected[1]) {
+			t.Errorf("%s: expected %s of %s, got %s of %s", e.Key, expected[0], expected[1], e.Type, e.ArrayType)
+		}
+	}
+}
+
+func TestMarshalOmitted(t *testing.T) {
+	in := &marshalTest{
+		Arch:   "llama",
+		Nested: [][]int32{},
+	}
+
+	r, out := marshalRoundTrip(t, in)
+
+	// Zero values without omitempty are kept, nil pointers and nil
+	// interfaces are always left out.
+	expected := []string{"general.architecture", "llama.block_count", "llama.attention.head_count", "test.nested"}
+
+	keys := make([]string, len(r.Entries))
+	for i, e := range r.Entries {
+		keys[i] = e.Key
+	}
+
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected keys %v, got %v", expected, keys)
+	}
+
+	o := out.(*marshalTest)
+
+	if o.Scale != nil || o.Any != nil || o.Tokens != nil {
+		t.Errorf("omitted values were set: %+v", o)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		v        interface{}
+		notExact bool
+		message  string
+	}{
+		{
+			name: "negative to unsigned",
+			v: struct {
+				A int `gguf:"a,type=uint32"`
+			}{-1},
+			notExact: true,
+		},
+		{
+			name: "overflow",
+			v: struct {
+				A int `gguf:"a,type=uint32"`
+			}{1 << 40},
+			message: "overflows uint32",
+		},
+		{
+			name: "fraction to integer",
+			v: struct {
+				A float64 `gguf:"a,type=int32"`
+			}{3.5},
+			notExact: true,
+		},
+		{
+			name: "integer to float",
+			v: struct {
+				A uint64 `gguf:"a,type=float32"`
+			}{1<<60 + 1},
+			notExact: true,
+		},
+		{
+			name: "array element",
+			v: struct {
+				A []int64 `gguf:"a,type=uint8"`
+			}{[]int64{1, 256}},
+			message: "element 1",
+		},
+		{
+			name: "nil element",
+			v: struct {
+				A []interface{} `gguf:"a,type=uint32"`
+			}{[]interface{}{uint32(1), nil}},
+			message: "element 1: nil cannot be stored in uint32",
+		},
+		{
+			name: "nested nil element",
+			v: struct {
+				A [][]interface{} `gguf:"a,type=string"`
+			}{[][]interface{}{{"a"}, {nil}}},
+			message: "element 1: element 0: nil cannot be stored in string",
+		},
+		{
+			name: "int without type",
+			v: struct {
+				A int `gguf:"a"`
+			}{1},
+			message: "must be given with the type tag option",
+		},
+		{
+			name: "missing architecture",
+			v: struct {
+				A uint32 `gguf:"{arch}.a"`
+			}{1},
+			message: "needs general.architecture",
+		},
+	}
+
+	for _, c := range cases {
+		_, err := MarshalMetadata(c.v)
+		if err == nil {
+			t.Errorf("%s: expected an error", c.name)
+
+			continue
+		}
+
+		if c.notExact && !errors.Is(err, errNotExact) {
+			t.Errorf("%s: expected errNotExact, got %v", c.name, err)
+		}
+
+		if !strings.Contains(err.Error(), c.message) {
+			t.Errorf("%s: expected %q in the error, got %v", c.name, c.message, err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package gguf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"unsafe"
+)
+
+// ErrNotMapped is returned when accessing tensor data as a slice on a
+// Reader not opened by OpenMmap().
+var ErrNotMapped = errors.New("file is not memory mapped")
+
+// mmapReaderAt reads from a memory mapped file. Reads hold a read lock
+// while copying, and Close() takes the write lock before unmapping, so
+// reads racing with or following Close() fail with os.ErrClosed
+// instead of touching unmapped memory.
+type mmapReaderAt struct {
+	sync.RWMutex
+
+	data  []byte
+	unmap func([]byte) error
+}
+
+// ReadAt implements io.ReaderAt.
+func (m *mmapReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	if m.data == nil {
+		return 0, os.ErrClosed
+	}
+
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset %d", off)
+	}
+
+	if off >= int64(len(m.data)) {
+		return 0, io.EOF
+	}
+
+	n := copy(p, m.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// slice returns data[start:end], or ErrNotMapped after Close().
+func (m *mmapReaderAt) slice(start, end int64) ([]byte, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	if m.data == nil {
+		return nil, ErrNotMapped
+	}
+
+	return m.data[start:end:end], nil
+}
+
+// Close unmaps the file. Calling it again does nothing.
+func (m *mmapReaderAt) Close() error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.data == nil {
+		return nil
+	}
+
+	err := m.unmap(m.data)
+	m.data = nil
+
+	return err
+}
+
+// nativeByteOrder is the byte order of the machine we're running on.
+var nativeByteOrder = func() binary.ByteOrder {
+	i := uint16(1)
+
+	if *(*byte)(unsafe.Pointer(&i)) == 1 {
+		return binary.LittleEndian
+	}
+
+	return binary.BigEndian
+}()
+
+// Bytes returns the tensor data as a slice of the memory mapped file.
+// The slice must not be modified, and must not be used after Close()
+// is called on the Reader. If the file was not opened by OpenMmap(),
+// ErrNotMapped is returned.
+func (t *TensorInfo) Bytes() ([]byte, error) {
+	if t.g == nil || t.g.mapping == nil {
+		return nil, ErrNotMapped
+	}
+
+	start, size, err := t.dataRange()
+	if err != nil {
+		return nil, err
+	}
+
+	return t.g.mapping.slice(start, start+size)
+}
+
+// Float32View returns the data of a float32 tensor as a []float32
+// backed by the memory mapped file. The same restrictions as for
+// Bytes() apply.
+func (t *TensorInfo) Float32View() ([]float32, error) {
+	return view[float32](t, GgmlFloat32)
+}
+
+// Float16View returns the data of a float16 tensor as a []uint16 of
+// raw IEEE 754 half precision values backed by the memory mapped
+// file. The same restrictions as for Bytes() apply.
+func (t *TensorInfo) Float16View() ([]uint16, error) {
+	return view[uint16](t, GgmlFloat16)
+}
+
+// Int8View returns the data of an int8 tensor as an []int8 backed by
+// the memory mapped file. The same restrictions as for Bytes() apply.
+func (t *TensorInfo) Int8View() ([]int8, error) {
+	return view[int8](t, GgmlInt8)
+}
+
+// Int16View returns the data of an int16 tensor as an []int16 backed
+// by the memory mapped file. The same restrictions as for Bytes()
+// apply.
+func (t *TensorInfo) Int16View() ([]int16, error) {
+	return view[int16](t, GgmlInt16)
+}
+
+// Int32View returns the data of an int32 tensor as an []int32 backed
+// by the memory mapped file. The same restrictions as for Bytes()
+// apply.
+func (t *TensorInfo) Int32View() ([]int32, error) {
+	return view[int32](t, GgmlInt32)
+}
+
+// view returns the data of t as a []T, if t is of type typ and stored
+// in native byte order.
+func view[T float32 | uint16 | int8 | int16 | int32](t *TensorInfo, typ GGML) ([]T, error) {
+	if t.Type != typ {
+		return nil, fmt.Errorf("tensor %q is of type %s, not %s", t.Name, t.Type, typ)
+	}
+
+	data, err := t.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var zero T
+
+	size := int(unsafe.Sizeof(zero))
+
+	if size > 1 && t.g.ByteOrder != nativeByteOrder {
+		return nil, fmt.Errorf("tensor %q is stored in %s, not native byte order", t.Name, t.g.ByteOrder)
+	}
+
+	if len(data) == 0 {
+		return []T{}, nil
+	}
+
+	if uintptr(unsafe.Pointer(&data[0]))%uintptr(size) != 0 {
+		return nil, fmt.Errorf("tensor %q data is not aligned for %T", t.Name, zero)
+	}
+
+	return unsafe.Slice((*T)(unsafe.Pointer(&data[0])), len(data)/size), nil
+}
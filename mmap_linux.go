@@ -0,0 +1,53 @@
+package gguf
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+)
+
+// OpenMmap opens a GGUF file by mapping it into memory. Tensor data
+// can then be accessed without copying using Bytes() and the view
+// functions on TensorInfo. The file must be unmapped by calling
+// Close() on the returned Reader.
+func OpenMmap(filename string, opts ...Option) (*Reader, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// The mapping stays valid after the file is closed.
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	size := stat.Size()
+	if size <= 0 || int64(int(size)) != size {
+		return nil, fmt.Errorf("cannot map file of size %d", size)
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("mmap %s: %w", filename, err)
+	}
+
+	m := &mmapReaderAt{data: data, unmap: syscall.Munmap}
+
+	// Tensor data is read through m also after the Reader is
+	// closed, so the reads fail instead of faulting.
+	r, err := Open(io.NewSectionReader(m, 0, size), opts...)
+	if err != nil {
+		_ = m.Close()
+
+		return nil, err
+	}
+
+	r.mapping = m
+	r.closer = m.Close
+
+	return r, nil
+}
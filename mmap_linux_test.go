@@ -0,0 +1,128 @@
+package gguf
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestReadAfterClose(t *testing.T) {
+	_, data := encode(t, testWriter(t, binary.LittleEndian))
+
+	filename := filepath.Join(t.TempDir(), "test.gguf")
+
+	err := os.WriteFile(filename, data, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	open := map[string]func(string, ...Option) (*Reader, error){
+		"OpenFile": OpenFile,
+		"OpenMmap": OpenMmap,
+	}
+
+	for name, openFunc := range open {
+		r, err := openFunc(filename)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		tensor := &r.Tensors[0]
+
+		// Readers created before Close must fail afterwards instead
+		// of reading from the closed file or unmapped memory.
+		sr, err := tensor.SectionReader()
+		if err != nil {
+			t.Fatalf("%s: SectionReader: %s", name, err)
+		}
+
+		w := NewWriterFrom(r)
+
+		err = r.Close()
+		if err != nil {
+			t.Fatalf("%s: Close: %s", name, err)
+		}
+
+		err = r.Close()
+		if err != nil {
+			t.Fatalf("%s: second Close: %s", name, err)
+		}
+
+		_, err = io.ReadAll(sr)
+		if !errors.Is(err, os.ErrClosed) {
+			t.Errorf("%s: SectionReader after Close: expected os.ErrClosed, got %v", name, err)
+		}
+
+		_, err = w.WriteTo(io.Discard)
+		if !errors.Is(err, os.ErrClosed) {
+			t.Errorf("%s: NewWriterFrom after Close: expected os.ErrClosed, got %v", name, err)
+		}
+
+		_, err = tensor.Dequantize()
+		if !errors.Is(err, os.ErrClosed) {
+			t.Errorf("%s: Dequantize after Close: expected os.ErrClosed, got %v", name, err)
+		}
+
+		_, err = tensor.Bytes()
+		if !errors.Is(err, ErrNotMapped) {
+			t.Errorf("%s: Bytes after Close: expected ErrNotMapped, got %v", name, err)
+		}
+	}
+}
+
+func TestCloseDuringRead(t *testing.T) {
+	_, data := encode(t, testWriter(t, binary.LittleEndian))
+
+	filename := filepath.Join(t.TempDir(), "test.gguf")
+
+	err := os.WriteFile(filename, data, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := OpenMmap(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				sr, err := r.Tensors[0].SectionReader()
+				if err != nil {
+					t.Error(err)
+
+					return
+				}
+
+				_, err = io.ReadAll(sr)
+				if errors.Is(err, os.ErrClosed) {
+					return
+				}
+
+				if err != nil {
+					t.Error(err)
+
+					return
+				}
+			}
+		}()
+	}
+
+	err = r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package gguf
+
+import (
+	"errors"
+)
+
+// OpenMmap opens a GGUF file by mapping it into memory. Memory mapping
+// is only supported on Linux, on other platforms an error is returned.
+func OpenMmap(filename string, opts ...Option) (*Reader, error) {
+	return nil, errors.New("memory mapping is not supported on this platform")
+}
@@ -0,0 +1,305 @@
+package gguf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// quantizer encodes whole blocks from src into dst. len(src) is a
+// multiple of the number of values in a block, and dst has room for
+// all the blocks.
+type quantizer func(dst []byte, src []float32, byteOrder binary.ByteOrder)
+
+// quantizers is a map of GGML to the function encoding it. It's used
+// by Quantize().
+var quantizers = map[GGML]quantizer{
+	GgmlFloat32:  quantizeF32,
+	GgmlFloat16:  quantizeF16,
+	GgmlBFloat16: quantizeBF16,
+	GgmlQ4_0:     quantizeQ4_0,
+	GgmlQ4_1:     quantizeQ4_1,
+	GgmlQ5_0:     quantizeQ5_0,
+	GgmlQ5_1:     quantizeQ5_1,
+	GgmlQ8_0:     quantizeQ8_0,
+	GgmlQ2_K:     unweighted(quantizeQ2_K),
+	GgmlQ3_K:     unweighted(quantizeQ3_K),
+	GgmlQ4_K:     unweighted(quantizeQ4_K),
+	GgmlQ5_K:     unweighted(quantizeQ5_K),
+	GgmlQ6_K:     unweighted(quantizeQ6_K),
+}
+
+// Quantize encodes float32 values as typ, using the same rounding as
+// the ggml reference implementation. Multi-byte fields are written in
+// byteOrder. The number of values must be a multiple of the block size
+// of typ. The result can be passed directly to Writer.AddTensor().
+func Quantize(typ GGML, src []float32, byteOrder binary.ByteOrder) ([]byte, error) {
+	s, found := sizes[typ]
+	if !found {
+		return nil, fmt.Errorf("unknown type: %s", typ)
+	}
+
+	dst := make([]byte, uint64(len(src))/s.valuesinblock*s.blocksize)
+
+	err := QuantizeInto(dst, typ, src, byteOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	return dst, nil
+}
+
+// QuantizeInto encodes float32 values as typ into dst. dst must have
+// room for all the blocks.
+func QuantizeInto(dst []byte, typ GGML, src []float32, byteOrder binary.ByteOrder) error {
+	s, found := sizes[typ]
+	if !found {
+		return fmt.Errorf("unknown type: %s", typ)
+	}
+
+	f, found := quantizers[typ]
+	if !found {
+		return fmt.Errorf("quantization to %s is not supported", typ)
+	}
+
+	if uint64(len(src))%s.valuesinblock != 0 {
+		return fmt.Errorf("%d values is not a whole number of %s blocks", len(src), typ)
+	}
+
+	size := uint64(len(src)) / s.valuesinblock * s.blocksize
+	if uint64(len(dst)) < size {
+		return fmt.Errorf("destination has room for %d bytes, need %d", len(dst), size)
+	}
+
+	f(dst[:size], src, byteOrder)
+
+	return nil
+}
+
+// QuantizeWeighted encodes rows of float32 values as typ, using the
+// importance of each column to weigh the search for scales and mins.
+// The row length is len(importance), and len(src) must be a multiple
+// of it. Importance weighting is supported for Q2_K to Q6_K, other
+// types are quantized as by Quantize() and the importance is ignored.
+func QuantizeWeighted(typ GGML, src []float32, importance []float32, byteOrder binary.ByteOrder) ([]byte, error) {
+	f, found := weightedQuantizers[typ]
+	if !found {
+		return Quantize(typ, src, byteOrder)
+	}
+
+	s := sizes[typ]
+	rowLength := uint64(len(importance))
+
+	if rowLength == 0 || rowLength%s.valuesinblock != 0 {
+		return nil, fmt.Errorf("row length %d is not a whole number of %s blocks", rowLength, typ)
+	}
+
+	if uint64(len(src))%rowLength != 0 {
+		return nil, fmt.Errorf("%d values is not a whole number of rows of length %d", len(src), rowLength)
+	}
+
+	rowSize := rowLength / s.valuesinblock * s.blocksize
+	dst := make([]byte, uint64(len(src))/rowLength*rowSize)
+
+	for row := uint64(0); row < uint64(len(src))/rowLength; row++ {
+		f(dst[row*rowSize:(row+1)*rowSize], src[row*rowLength:(row+1)*rowLength], importance, byteOrder)
+	}
+
+	return dst, nil
+}
+
+func quantizeF32(dst []byte, src []float32, byteOrder binary.ByteOrder) {
+	for i, v := range src {
+		byteOrder.PutUint32(dst[i*4:], math.Float32bits(v))
+	}
+}
+
+func quantizeF16(dst []byte, src []float32, byteOrder binary.ByteOrder) {
+	for i, v := range src {
+		byteOrder.PutUint16(dst[i*2:], Float32ToFloat16(v))
+	}
+}
+
+func quantizeBF16(dst []byte, src []float32, byteOrder binary.ByteOrder) {
+	for i, v := range src {
+		byteOrder.PutUint16(dst[i*2:], Float32ToBFloat16(v))
+	}
+}
+
+// putHalf writes f as a float16 value to b.
+func putHalf(b []byte, f float32, byteOrder binary.ByteOrder) {
+	byteOrder.PutUint16(b, Float32ToFloat16(f))
+}
+
+// absMax returns the value with the largest magnitude in x, keeping
+// the sign.
+func absMax(x []float32) float32 {
+	amax := float32(0)
+	max := float32(0)
+
+	for _, v := range x {
+		if a := float32(math.Abs(float64(v))); amax < a {
+			amax = a
+			max = v
+		}
+	}
+
+	return max
+}
+
+// minMax returns the smallest and largest value in x.
+func minMax(x []float32) (float32, float32) {
+	min := float32(math.MaxFloat32)
+	max := float32(-math.MaxFloat32)
+
+	for _, v := range x {
+		if v < min {
+			min = v
+		}
+
+		if v > max {
+			max = v
+		}
+	}
+
+	return min, max
+}
+
+// inverse returns 1/d, or 0 if d is 0.
+func inverse(d float32) float32 {
+	if d == 0 {
+		return 0
+	}
+
+	return 1 / d
+}
+
+func quantizeQ4_0(dst []byte, src []float32, byteOrder binary.ByteOrder) {
+	const blocksize = 2 + qK4_0/2
+
+	for i := 0; i < len(src)/qK4_0; i++ {
+		x := src[i*qK4_0 : (i+1)*qK4_0]
+		b := dst[i*blocksize : (i+1)*blocksize]
+
+		d := absMax(x) / -8
+		id := inverse(d)
+
+		putHalf(b, d, byteOrder)
+
+		for j := 0; j < qK4_0/2; j++ {
+			xi0 := minInt8(15, int8(float32(x[j]*id)+8.5))
+			xi1 := minInt8(15, int8(float32(x[j+qK4_0/2]*id)+8.5))
+
+			b[2+j] = uint8(xi0) | uint8(xi1)<<4
+		}
+	}
+}
+
+func quantizeQ4_1(dst []byte, src []float32, byteOrder binary.ByteOrder) {
+	const blocksize = 4 + qK4_1/2
+
+	for i := 0; i < len(src)/qK4_1; i++ {
+		x := src[i*qK4_1 : (i+1)*qK4_1]
+		b := dst[i*blocksize : (i+1)*blocksize]
+
+		min, max := minMax(x)
+		d := (max - min) / (1<<4 - 1)
+		id := inverse(d)
+
+		putHalf(b, d, byteOrder)
+		putHalf(b[2:], min, byteOrder)
+
+		for j := 0; j < qK4_1/2; j++ {
+			xi0 := minInt8(15, int8(float32((x[j]-min)*id)+0.5))
+			xi1 := minInt8(15, int8(float32((x[j+qK4_1/2]-min)*id)+0.5))
+
+			b[4+j] = uint8(xi0) | uint8(xi1)<<4
+		}
+	}
+}
+
+func quantizeQ5_0(dst []byte, src []float32, byteOrder binary.ByteOrder) {
+	const blocksize = 2 + 4 + qK5_0/2
+
+	for i := 0; i < len(src)/qK5_0; i++ {
+		x := src[i*qK5_0 : (i+1)*qK5_0]
+		b := dst[i*blocksize : (i+1)*blocksize]
+
+		d := absMax(x) / -16
+		id := inverse(d)
+
+		putHalf(b, d, byteOrder)
+
+		qh := uint32(0)
+
+		for j := 0; j < qK5_0/2; j++ {
+			xi0 := uint8(minInt8(31, int8(float32(x[j]*id)+16.5)))
+			xi1 := uint8(minInt8(31, int8(float32(x[j+qK5_0/2]*id)+16.5)))
+
+			b[6+j] = xi0&0x0f | (xi1&0x0f)<<4
+
+			qh |= uint32(xi0&0x10) >> 4 << j
+			qh |= uint32(xi1&0x10) >> 4 << (j + qK5_0/2)
+		}
+
+		binary.LittleEndian.PutUint32(b[2:], qh)
+	}
+}
+
+func quantizeQ5_1(dst []byte, src []float32, byteOrder binary.ByteOrder) {
+	const blocksize = 4 + 4 + qK5_1/2
+
+	for i := 0; i < len(src)/qK5_1; i++ {
+		x := src[i*qK5_1 : (i+1)*qK5_1]
+		b := dst[i*blocksize : (i+1)*blocksize]
+
+		min, max := minMax(x)
+		d := (max - min) / (1<<5 - 1)
+		id := inverse(d)
+
+		putHalf(b, d, byteOrder)
+		putHalf(b[2:], min, byteOrder)
+
+		qh := uint32(0)
+
+		for j := 0; j < qK5_1/2; j++ {
+			xi0 := uint8(float32((x[j]-min)*id) + 0.5)
+			xi1 := uint8(float32((x[j+qK5_1/2]-min)*id) + 0.5)
+
+			b[8+j] = xi0&0x0f | (xi1&0x0f)<<4
+
+			qh |= uint32(xi0&0x10) >> 4 << j
+			qh |= uint32(xi1&0x10) >> 4 << (j + qK5_1/2)
+		}
+
+		binary.LittleEndian.PutUint32(b[4:], qh)
+	}
+}
+
+func quantizeQ8_0(dst []byte, src []float32, byteOrder binary.ByteOrder) {
+	const blocksize = 2 + qK8_0
+
+	for i := 0; i < len(src)/qK8_0; i++ {
+		x := src[i*qK8_0 : (i+1)*qK8_0]
+		b := dst[i*blocksize : (i+1)*blocksize]
+
+		amax := float32(math.Abs(float64(absMax(x))))
+		d := amax / (1<<7 - 1)
+		id := inverse(d)
+
+		putHalf(b, d, byteOrder)
+
+		for j := 0; j < qK8_0; j++ {
+			b[2+j] = uint8(int8(math.Round(float64(x[j] * id))))
+		}
+	}
+}
+
+// minInt8 returns the smaller of a and b.
+func minInt8(a, b int8) int8 {
+	if a < b {
+		return a
+	}
+
+	return b
+}
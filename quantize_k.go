@@ -0,0 +1,999 @@
+package gguf
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// groupMaxEps is the magnitude below which a group is considered to be
+// all zeros.
+const groupMaxEps = 1e-15
+
+// weightedQuantizer encodes whole blocks of a single row from src into
+// dst. importance holds one weight per column of the row, or is nil
+// for unweighted quantization.
+type weightedQuantizer func(dst []byte, src []float32, importance []float32, byteOrder binary.ByteOrder)
+
+// weightedQuantizers is a map of GGML to the function encoding it
+// using importance weights. It's used by QuantizeWeighted().
+var weightedQuantizers = map[GGML]weightedQuantizer{
+	GgmlQ2_K: quantizeQ2_K,
+	GgmlQ3_K: quantizeQ3_K,
+	GgmlQ4_K: quantizeQ4_K,
+	GgmlQ5_K: quantizeQ5_K,
+	GgmlQ6_K: quantizeQ6_K,
+}
+
+// unweighted adapts a weightedQuantizer for use without importance
+// weights.
+func unweighted(f weightedQuantizer) quantizer {
+	return func(dst []byte, src []float32, byteOrder binary.ByteOrder) {
+		f(dst, src, nil, byteOrder)
+	}
+}
+
+// nearestInt rounds f to the nearest integer, ties to even.
+func nearestInt(f float32) int {
+	return int(math.RoundToEven(float64(f)))
+}
+
+// clamp limits v to the range [lo, hi].
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+
+	if v > hi {
+		return hi
+	}
+
+	return v
+}
+
+// sqrt32 returns the square root of f.
+func sqrt32(f float32) float32 {
+	return float32(math.Sqrt(float64(f)))
+}
+
+// abs32 returns the absolute value of f.
+func abs32(f float32) float32 {
+	return float32(math.Abs(float64(f)))
+}
+
+// makeQXQuants finds a scale and symmetric quantization of x to the
+// range [-nmax, nmax-1], stored offset by nmax in l. If qw is nil the
+// weights are chosen by rmseType.
+func makeQXQuants(nmax int, x []float32, l []uint8, rmseType int, qw []float32) float32 {
+	max := float32(0)
+	amax := float32(0)
+
+	for _, v := range x {
+		if ax := abs32(v); ax > amax {
+			amax = ax
+			max = v
+		}
+	}
+
+	if amax < groupMaxEps {
+		for i := range l[:len(x)] {
+			l[i] = 0
+		}
+
+		return 0
+	}
+
+	iscale := -float32(nmax) / max
+
+	if rmseType == 0 {
+		for i, v := range x {
+			l[i] = uint8(nmax + clamp(nearestInt(iscale*v), -nmax, nmax-1))
+		}
+
+		return 1 / iscale
+	}
+
+	weight := func(i int) float32 {
+		if qw != nil {
+			return qw[i]
+		}
+
+		switch rmseType {
+		case 1:
+			return x[i] * x[i]
+		case 2:
+			return 1
+		case 3:
+			return abs32(x[i])
+		default:
+			return sqrt32(abs32(x[i]))
+		}
+	}
+
+	sumlx := float32(0)
+	suml2 := float32(0)
+
+	for i, v := range x {
+		q := clamp(nearestInt(iscale*v), -nmax, nmax-1)
+		l[i] = uint8(q + nmax)
+
+		w := weight(i)
+		sumlx += w * v * float32(q)
+		suml2 += w * float32(q) * float32(q)
+	}
+
+	scale := float32(0)
+	if suml2 != 0 {
+		scale = sumlx / suml2
+	}
+
+	best := scale * sumlx
+
+	for is := -9; is <= 9; is++ {
+		if is == 0 {
+			continue
+		}
+
+		iscale = -(float32(nmax) + 0.1*float32(is)) / max
+		sumlx = 0
+		suml2 = 0
+
+		for i, v := range x {
+			q := clamp(nearestInt(iscale*v), -nmax, nmax-1)
+
+			w := weight(i)
+			sumlx += w * v * float32(q)
+			suml2 += w * float32(q) * float32(q)
+		}
+
+		if suml2 > 0 && sumlx*sumlx > best*suml2 {
+			for i, v := range x {
+				l[i] = uint8(nmax + clamp(nearestInt(iscale*v), -nmax, nmax-1))
+			}
+
+			scale = sumlx / suml2
+			best = scale * sumlx
+		}
+	}
+
+	return scale
+}
+
+// makeQ3Quants finds a scale and symmetric quantization of x to the
+// range [-nmax, nmax-1], stored offset by nmax in l. If doRMSE is set,
+// the quantization is refined iteratively.
+func makeQ3Quants(nmax int, x []float32, l []uint8, doRMSE bool) float32 {
+	max := float32(0)
+	amax := float32(0)
+
+	for _, v := range x {
+		if ax := abs32(v); ax > amax {
+			amax = ax
+			max = v
+		}
+	}
+
+	if amax < groupMaxEps {
+		for i := range l[:len(x)] {
+			l[i] = 0
+		}
+
+		return 0
+	}
+
+	iscale := -float32(nmax) / max
+
+	if !doRMSE {
+		for i, v := range x {
+			l[i] = uint8(nmax + clamp(nearestInt(iscale*v), -nmax, nmax-1))
+		}
+
+		return 1 / iscale
+	}
+
+	q := make([]int, len(x))
+	sumlx := float32(0)
+	suml2 := float32(0)
+
+	for i, v := range x {
+		q[i] = clamp(nearestInt(iscale*v), -nmax, nmax-1)
+
+		w := v * v
+		sumlx += w * v * float32(q[i])
+		suml2 += w * float32(q[i]) * float32(q[i])
+	}
+
+	for itry := 0; itry < 5; itry++ {
+		changed := 0
+
+		for i, v := range x {
+			w := v * v
+
+			slx := sumlx - w*v*float32(q[i])
+			if slx <= 0 {
+				continue
+			}
+
+			sl2 := suml2 - w*float32(q[i])*float32(q[i])
+
+			newL := clamp(nearestInt(v*sl2/slx), -nmax, nmax-1)
+			if newL == q[i] {
+				continue
+			}
+
+			slx += w * v * float32(newL)
+			sl2 += w * float32(newL) * float32(newL)
+
+			if sl2 > 0 && slx*slx*suml2 > sumlx*sumlx*sl2 {
+				q[i] = newL
+				sumlx = slx
+				suml2 = sl2
+				changed++
+			}
+		}
+
+		if changed == 0 {
+			break
+		}
+	}
+
+	for i := range x {
+		l[i] = uint8(q[i] + nmax)
+	}
+
+	return sumlx / suml2
+}
+
+// makeQKXQuants finds a scale and min for an asymmetric quantization
+// of x to the range [0, nmax], stored in l. It searches nstep scales
+// starting at rmin in steps of rdelta, minimizing the weighted squared
+// (or absolute if useMAD is set) error. If weights is nil, x² is used.
+// The returned min is to be subtracted.
+func makeQKXQuants(nmax int, x []float32, weights []float32, l []uint8, rmin float32, rdelta float32, nstep int, useMAD bool) (float32, float32) {
+	weight := func(i int) float32 {
+		if weights != nil {
+			return weights[i]
+		}
+
+		return x[i] * x[i]
+	}
+
+	min := x[0]
+	max := x[0]
+	sumW := weight(0)
+	sumX := sumW * x[0]
+
+	for i := 1; i < len(x); i++ {
+		if x[i] < min {
+			min = x[i]
+		}
+
+		if x[i] > max {
+			max = x[i]
+		}
+
+		w := weight(i)
+		sumW += w
+		sumX += w * x[i]
+	}
+
+	if min > 0 {
+		min = 0
+	}
+
+	if max <= min {
+		for i := range l[:len(x)] {
+			l[i] = 0
+		}
+
+		return 0, -min
+	}
+
+	iscale := float32(nmax) / (max - min)
+	scale := 1 / iscale
+
+	errorOf := func(diff float32) float32 {
+		if useMAD {
+			return abs32(diff)
+		}
+
+		return diff * diff
+	}
+
+	bestMAD := float32(0)
+
+	for i, v := range x {
+		l[i] = uint8(clamp(nearestInt(iscale*(v-min)), 0, nmax))
+
+		bestMAD += weight(i) * errorOf(scale*float32(l[i])+min-v)
+	}
+
+	if nstep < 1 {
+		return scale, -min
+	}
+
+	laux := make([]uint8, len(x))
+
+	for is := 0; is <= nstep; is++ {
+		iscale = (rmin + rdelta*float32(is) + float32(nmax)) / (max - min)
+
+		sumL := float32(0)
+		sumL2 := float32(0)
+		sumXL := float32(0)
+
+		for i, v := range x {
+			q := clamp(nearestInt(iscale*(v-min)), 0, nmax)
+			laux[i] = uint8(q)
+
+			w := weight(i)
+			sumL += w * float32(q)
+			sumL2 += w * float32(q) * float32(q)
+			sumXL += w * float32(q) * v
+		}
+
+		d := sumW*sumL2 - sumL*sumL
+		if d <= 0 {
+			continue
+		}
+
+		thisScale := (sumW*sumXL - sumX*sumL) / d
+		thisMin := (sumL2*sumX - sumL*sumXL) / d
+
+		if thisMin > 0 {
+			thisMin = 0
+			thisScale = sumXL / sumL2
+		}
+
+		mad := float32(0)
+
+		for i, v := range x {
+			mad += weight(i) * errorOf(thisScale*float32(laux[i])+thisMin-v)
+		}
+
+		if mad < bestMAD {
+			copy(l, laux)
+
+			bestMAD = mad
+			scale = thisScale
+			min = thisMin
+		}
+	}
+
+	return scale, -min
+}
+
+// makeQPQuants quantizes the non-negative values in x to the range
+// [0, nmax] using the weights in qw, and returns the scale.
+func makeQPQuants(nmax int, x []float32, l []uint8, qw []float32) float32 {
+	max := float32(0)
+
+	for _, v := range x {
+		if v > max {
+			max = v
+		}
+	}
+
+	if max == 0 {
+		for i := range l[:len(x)] {
+			l[i] = 0
+		}
+
+		return 0
+	}
+
+	iscale := float32(nmax) / max
+
+	for i, v := range x {
+		l[i] = uint8(nearestInt(iscale * v))
+	}
+
+	scale := 1 / iscale
+	bestMSE := float32(0)
+
+	for i, v := range x {
+		diff := v - scale*float32(l[i])
+		bestMSE += qw[i] * diff * diff
+	}
+
+	for is := -4; is <= 4; is++ {
+		if is == 0 {
+			continue
+		}
+
+		iscaleIs := (0.1*float32(is) + float32(nmax)) / max
+		scaleIs := 1 / iscaleIs
+
+		mse := float32(0)
+
+		for i, v := range x {
+			q := nearestInt(iscaleIs * v)
+			if q > nmax {
+				q = nmax
+			}
+
+			diff := v - scaleIs*float32(q)
+			mse += qw[i] * diff * diff
+		}
+
+		if mse < bestMSE {
+			bestMSE = mse
+			iscale = iscaleIs
+		}
+	}
+
+	sumlx := float32(0)
+	suml2 := float32(0)
+
+	for i, v := range x {
+		q := nearestInt(iscale * v)
+		if q > nmax {
+			q = nmax
+		}
+
+		l[i] = uint8(q)
+
+		sumlx += qw[i] * v * float32(q)
+		suml2 += qw[i] * float32(q) * float32(q)
+	}
+
+	for itry := 0; itry < 5; itry++ {
+		changed := 0
+
+		for i, v := range x {
+			w := qw[i]
+			slx := sumlx - w*v*float32(l[i])
+			sl2 := suml2 - w*float32(l[i])*float32(l[i])
+
+			if slx <= 0 || sl2 <= 0 {
+				continue
+			}
+
+			newL := nearestInt(v * sl2 / slx)
+			if newL > nmax {
+				newL = nmax
+			}
+
+			if newL == int(l[i]) {
+				continue
+			}
+
+			slx += w * v * float32(newL)
+			sl2 += w * float32(newL) * float32(newL)
+
+			if slx*slx*suml2 > sumlx*sumlx*sl2 {
+				l[i] = uint8(newL)
+				sumlx = slx
+				suml2 = sl2
+				changed++
+			}
+		}
+
+		if changed == 0 {
+			break
+		}
+	}
+
+	return sumlx / suml2
+}
+
+// sumSquares returns the sum of the squares of x.
+func sumSquares(x []float32) float32 {
+	sum := float32(0)
+
+	for _, v := range x {
+		sum += v * v
+	}
+
+	return sum
+}
+
+// importanceWeights fills weights with the importance of x scaled by
+// the magnitude of each value, as done by ggml.
+func importanceWeights(weights []float32, qw []float32, x []float32, sigma2 float32) float32 {
+	sumW := float32(0)
+
+	for l, v := range x {
+		weights[l] = qw[l] * sqrt32(sigma2+v*v)
+		sumW += weights[l]
+	}
+
+	return sumW
+}
+
+// packScalesK4 packs 6-bit scales and mins into the kScaleSize bytes
+// of dst, the inverse of scaleMinK4().
+func packScalesK4(dst []byte, ls []uint8, lm []uint8) {
+	for j := 0; j < qK_K/32; j++ {
+		if j < 4 {
+			dst[j] = ls[j]
+			dst[j+4] = lm[j]
+		} else {
+			dst[j+4] = (ls[j] & 0x0f) | ((lm[j] & 0x0f) << 4)
+			dst[j-4] |= (ls[j] >> 4) << 6
+			dst[j] |= (lm[j] >> 4) << 6
+		}
+	}
+}
+
+func quantizeQ2_K(dst []byte, src []float32, importance []float32, byteOrder binary.ByteOrder) {
+	const q4scale = 15
+	blocksize := int(sizes[GgmlQ2_K].blocksize)
+
+	var l [qK_K]uint8
+	var ls [qK_K / 16]uint8
+	var lm [qK_K / 16]uint8
+	var scales [qK_K / 16]float32
+	var mins [qK_K / 16]float32
+	var sw [qK_K / 16]float32
+	var weights [16]float32
+
+	for i := 0; i < len(src)/qK_K; i++ {
+		x := src[i*qK_K : (i+1)*qK_K]
+		b := dst[i*blocksize : (i+1)*blocksize]
+
+		bScales := b[:qK_K/16]
+		qs := b[qK_K/16 : qK_K/16+qK_K/4]
+
+		if importance != nil {
+			qw := importance[i*qK_K : (i+1)*qK_K]
+			sigma2 := sumSquares(x) / qK_K
+
+			for j := 0; j < qK_K/16; j++ {
+				sw[j] = importanceWeights(weights[:], qw[16*j:], x[16*j:16*(j+1)], sigma2)
+				scales[j], mins[j] = makeQKXQuants(3, x[16*j:16*(j+1)], weights[:], l[16*j:], -0.9, 0.05, 36, false)
+			}
+
+			dm := makeQPQuants(15, scales[:], ls[:], sw[:])
+			mm := makeQPQuants(15, mins[:], lm[:], sw[:])
+
+			putHalf(b[qK_K/16+qK_K/4:], dm, byteOrder)
+			putHalf(b[qK_K/16+qK_K/4+2:], mm, byteOrder)
+
+			for j := range bScales {
+				bScales[j] = ls[j] | lm[j]<<4
+			}
+		} else {
+			maxScale := float32(0)
+			maxMin := float32(0)
+
+			for j := 0; j < qK_K/16; j++ {
+				for k := range weights {
+					weights[k] = abs32(x[16*j+k])
+				}
+
+				scales[j], mins[j] = makeQKXQuants(3, x[16*j:16*(j+1)], weights[:], l[16*j:], -0.5, 0.1, 15, true)
+
+				if scales[j] > maxScale {
+					maxScale = scales[j]
+				}
+
+				if mins[j] > maxMin {
+					maxMin = mins[j]
+				}
+			}
+
+			for j := range bScales {
+				bScales[j] = 0
+			}
+
+			if maxScale > 0 {
+				iscale := q4scale / maxScale
+
+				for j := range bScales {
+					bScales[j] = uint8(nearestInt(iscale * scales[j]))
+				}
+
+				putHalf(b[qK_K/16+qK_K/4:], maxScale/q4scale, byteOrder)
+			} else {
+				putHalf(b[qK_K/16+qK_K/4:], 0, byteOrder)
+			}
+
+			if maxMin > 0 {
+				iscale := q4scale / maxMin
+
+				for j := range bScales {
+					bScales[j] |= uint8(nearestInt(iscale*mins[j])) << 4
+				}
+
+				putHalf(b[qK_K/16+qK_K/4+2:], maxMin/q4scale, byteOrder)
+			} else {
+				putHalf(b[qK_K/16+qK_K/4+2:], 0, byteOrder)
+			}
+		}
+
+		dAll := half(b[qK_K/16+qK_K/4:], byteOrder)
+		mAll := half(b[qK_K/16+qK_K/4+2:], byteOrder)
+
+		for j := 0; j < qK_K/16; j++ {
+			d := dAll * float32(bScales[j]&0x0f)
+			if d == 0 {
+				continue
+			}
+
+			dm := mAll * float32(bScales[j]>>4)
+
+			for k := 0; k < 16; k++ {
+				l[16*j+k] = uint8(clamp(nearestInt((x[16*j+k]+dm)/d), 0, 3))
+			}
+		}
+
+		for j := 0; j < qK_K; j += 128 {
+			for k := 0; k < 32; k++ {
+				qs[j/4+k] = l[j+k] | l[j+k+32]<<2 | l[j+k+64]<<4 | l[j+k+96]<<6
+			}
+		}
+	}
+}
+
+func quantizeQ3_K(dst []byte, src []float32, importance []float32, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlQ3_K].blocksize)
+
+	var l [qK_K]uint8
+	var ls [qK_K / 16]uint8
+	var scales [qK_K / 16]float32
+	var sw [qK_K / 16]float32
+	var weights [16]float32
+
+	for i := 0; i < len(src)/qK_K; i++ {
+		x := src[i*qK_K : (i+1)*qK_K]
+		b := dst[i*blocksize : (i+1)*blocksize]
+
+		hmask := b[:qK_K/8]
+		qs := b[qK_K/8 : qK_K/8+qK_K/4]
+		bScales := b[qK_K/8+qK_K/4 : qK_K/8+qK_K/4+kScaleSize]
+
+		for j := range bScales {
+			bScales[j] = 0
+		}
+
+		// packScale stores the 6-bit scale number j.
+		packScale := func(j int, v uint8) {
+			if j < 8 {
+				bScales[j] = v & 0x0f
+			} else {
+				bScales[j-8] |= (v & 0x0f) << 4
+			}
+
+			bScales[j%4+8] |= (v >> 4) << (2 * (j / 4))
+		}
+
+		if importance != nil {
+			qw := importance[i*qK_K : (i+1)*qK_K]
+			sigma2 := 2 * sumSquares(x) / qK_K
+
+			for j := 0; j < qK_K/16; j++ {
+				sw[j] = importanceWeights(weights[:], qw[16*j:], x[16*j:16*(j+1)], sigma2)
+				scales[j] = makeQXQuants(4, x[16*j:16*(j+1)], l[16*j:], 1, weights[:])
+			}
+
+			dBlock := makeQXQuants(32, scales[:], ls[:], 1, sw[:])
+
+			for j := 0; j < qK_K/16; j++ {
+				packScale(j, ls[j])
+			}
+
+			putHalf(b[qK_K/8+qK_K/4+kScaleSize:], dBlock, byteOrder)
+		} else {
+			maxScale := float32(0)
+			amax := float32(0)
+
+			for j := 0; j < qK_K/16; j++ {
+				scales[j] = makeQ3Quants(4, x[16*j:16*(j+1)], l[16*j:], true)
+
+				if scale := abs32(scales[j]); scale > amax {
+					amax = scale
+					maxScale = scales[j]
+				}
+			}
+
+			if maxScale != 0 {
+				iscale := -32 / maxScale
+
+				for j := 0; j < qK_K/16; j++ {
+					packScale(j, uint8(clamp(nearestInt(iscale*scales[j]), -32, 31)+32))
+				}
+
+				putHalf(b[qK_K/8+qK_K/4+kScaleSize:], 1/iscale, byteOrder)
+			} else {
+				putHalf(b[qK_K/8+qK_K/4+kScaleSize:], 0, byteOrder)
+			}
+		}
+
+		dAll := half(b[qK_K/8+qK_K/4+kScaleSize:], byteOrder)
+
+		for j := 0; j < qK_K/16; j++ {
+			var sc uint8
+			if j < 8 {
+				sc = bScales[j] & 0x0f
+			} else {
+				sc = bScales[j-8] >> 4
+			}
+
+			sc |= ((bScales[8+j%4] >> (2 * (j / 4))) & 3) << 4
+
+			d := dAll * float32(int(sc)-32)
+			if d == 0 {
+				continue
+			}
+
+			for k := 0; k < 16; k++ {
+				l[16*j+k] = uint8(clamp(nearestInt(x[16*j+k]/d), -4, 3) + 4)
+			}
+		}
+
+		for j := range hmask {
+			hmask[j] = 0
+		}
+
+		m := 0
+		hm := uint8(1)
+
+		for j := 0; j < qK_K; j++ {
+			if l[j] > 3 {
+				hmask[m] |= hm
+				l[j] -= 4
+			}
+
+			m++
+			if m == qK_K/8 {
+				m = 0
+				hm <<= 1
+			}
+		}
+
+		for j := 0; j < qK_K; j += 128 {
+			for k := 0; k < 32; k++ {
+				qs[j/4+k] = l[j+k] | l[j+k+32]<<2 | l[j+k+64]<<4 | l[j+k+96]<<6
+			}
+		}
+	}
+}
+
+// quantizeQ45_K is the common implementation of Q4_K and Q5_K, which
+// only differ in the number of bits per value and the packing of the
+// quantized values.
+func quantizeQ45_K(x []float32, qw []float32, nmax int, l []uint8, scales []byte, byteOrder binary.ByteOrder, d []byte) {
+	var ls [qK_K / 32]uint8
+	var lm [qK_K / 32]uint8
+	var subScales [qK_K / 32]float32
+	var mins [qK_K / 32]float32
+	var sw [qK_K / 32]float32
+	var weights [32]float32
+
+	if qw != nil {
+		sigma2 := 2 * sumSquares(x) / qK_K
+
+		for j := 0; j < qK_K/32; j++ {
+			sw[j] = importanceWeights(weights[:], qw[32*j:], x[32*j:32*(j+1)], sigma2)
+			subScales[j], mins[j] = makeQKXQuants(nmax, x[32*j:32*(j+1)], weights[:], l[32*j:], -0.9, 0.05, 36, false)
+		}
+
+		dBlock := makeQPQuants(63, subScales[:], ls[:], sw[:])
+		mBlock := makeQPQuants(63, mins[:], lm[:], sw[:])
+
+		putHalf(d, dBlock, byteOrder)
+		putHalf(d[2:], mBlock, byteOrder)
+	} else {
+		maxScale := float32(0)
+		maxMin := float32(0)
+
+		rmin, rdelta, nstep := float32(-1), float32(0.1), 20
+		if nmax == 31 {
+			rmin, rdelta, nstep = -0.5, 0.1, 15
+		}
+
+		for j := 0; j < qK_K/32; j++ {
+			avX := sqrt32(sumSquares(x[32*j:32*(j+1)]) / 32)
+
+			for k := range weights {
+				weights[k] = avX + abs32(x[32*j+k])
+			}
+
+			subScales[j], mins[j] = makeQKXQuants(nmax, x[32*j:32*(j+1)], weights[:], l[32*j:], rmin, rdelta, nstep, false)
+
+			if subScales[j] > maxScale {
+				maxScale = subScales[j]
+			}
+
+			if mins[j] > maxMin {
+				maxMin = mins[j]
+			}
+		}
+
+		invScale := float32(0)
+		if maxScale > 0 {
+			invScale = 63 / maxScale
+		}
+
+		invMin := float32(0)
+		if maxMin > 0 {
+			invMin = 63 / maxMin
+		}
+
+		for j := 0; j < qK_K/32; j++ {
+			ls[j] = uint8(clamp(nearestInt(invScale*subScales[j]), math.MinInt, 63))
+			lm[j] = uint8(clamp(nearestInt(invMin*mins[j]), math.MinInt, 63))
+		}
+
+		putHalf(d, maxScale/63, byteOrder)
+		putHalf(d[2:], maxMin/63, byteOrder)
+	}
+
+	packScalesK4(scales, ls[:], lm[:])
+
+	dAll := half(d, byteOrder)
+	mAll := half(d[2:], byteOrder)
+
+	for j := 0; j < qK_K/32; j++ {
+		sc, m := scaleMinK4(j, scales)
+
+		dj := dAll * float32(sc)
+		if dj == 0 {
+			continue
+		}
+
+		dm := mAll * float32(m)
+
+		for k := 0; k < 32; k++ {
+			l[32*j+k] = uint8(clamp(nearestInt((x[32*j+k]+dm)/dj), 0, nmax))
+		}
+	}
+}
+
+func quantizeQ4_K(dst []byte, src []float32, importance []float32, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlQ4_K].blocksize)
+
+	var l [qK_K]uint8
+
+	for i := 0; i < len(src)/qK_K; i++ {
+		x := src[i*qK_K : (i+1)*qK_K]
+		b := dst[i*blocksize : (i+1)*blocksize]
+
+		var qw []float32
+		if importance != nil {
+			qw = importance[i*qK_K : (i+1)*qK_K]
+		}
+
+		quantizeQ45_K(x, qw, 15, l[:], b[4:4+kScaleSize], byteOrder, b[:4])
+
+		q := b[4+kScaleSize:]
+
+		for j := 0; j < qK_K; j += 64 {
+			for k := 0; k < 32; k++ {
+				q[k] = l[j+k] | l[j+k+32]<<4
+			}
+
+			q = q[32:]
+		}
+	}
+}
+
+func quantizeQ5_K(dst []byte, src []float32, importance []float32, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlQ5_K].blocksize)
+
+	var l [qK_K]uint8
+
+	for i := 0; i < len(src)/qK_K; i++ {
+		x := src[i*qK_K : (i+1)*qK_K]
+		b := dst[i*blocksize : (i+1)*blocksize]
+
+		var qw []float32
+		if importance != nil {
+			qw = importance[i*qK_K : (i+1)*qK_K]
+		}
+
+		quantizeQ45_K(x, qw, 31, l[:], b[4:4+kScaleSize], byteOrder, b[:4])
+
+		qh := b[4+kScaleSize : 4+kScaleSize+qK_K/8]
+		ql := b[4+kScaleSize+qK_K/8:]
+
+		for j := range qh {
+			qh[j] = 0
+		}
+
+		m1 := uint8(1)
+		m2 := uint8(2)
+
+		for n := 0; n < qK_K; n += 64 {
+			for j := 0; j < 32; j++ {
+				l1 := l[n+j]
+				if l1 > 15 {
+					l1 -= 16
+					qh[j] |= m1
+				}
+
+				l2 := l[n+j+32]
+				if l2 > 15 {
+					l2 -= 16
+					qh[j] |= m2
+				}
+
+				ql[j] = l1 | l2<<4
+			}
+
+			m1 <<= 2
+			m2 <<= 2
+			ql = ql[32:]
+		}
+	}
+}
+
+func quantizeQ6_K(dst []byte, src []float32, importance []float32, byteOrder binary.ByteOrder) {
+	blocksize := int(sizes[GgmlQ6_K].blocksize)
+
+	var l [qK_K]uint8
+	var scales [qK_K / 16]float32
+
+	for i := 0; i < len(src)/qK_K; i++ {
+		x := src[i*qK_K : (i+1)*qK_K]
+		b := dst[i*blocksize : (i+1)*blocksize]
+
+		ql := b[:qK_K/2]
+		qh := b[qK_K/2 : qK_K/2+qK_K/4]
+		bScales := b[qK_K/2+qK_K/4 : qK_K/2+qK_K/4+qK_K/16]
+
+		maxScale := float32(0)
+		maxAbsScale := float32(0)
+
+		for ib := 0; ib < qK_K/16; ib++ {
+			var w []float32
+
+			// Unlike the other K-quants, ggml uses the importance
+			// as-is for Q6_K, without scaling by the values.
+			if importance != nil {
+				w = importance[i*qK_K+16*ib : i*qK_K+16*(ib+1)]
+			}
+
+			scales[ib] = makeQXQuants(32, x[16*ib:16*(ib+1)], l[16*ib:], 1, w)
+
+			if abs := abs32(scales[ib]); abs > maxAbsScale {
+				maxAbsScale = abs
+				maxScale = scales[ib]
+			}
+		}
+
+		if maxAbsScale < groupMaxEps {
+			for j := range b {
+				b[j] = 0
+			}
+
+			continue
+		}
+
+		iscale := -128 / maxScale
+
+		putHalf(b[qK_K/2+qK_K/4+qK_K/16:], 1/iscale, byteOrder)
+
+		for ib := range bScales {
+			bScales[ib] = uint8(int8(clamp(nearestInt(iscale*scales[ib]), math.MinInt, 127)))
+		}
+
+		dAll := half(b[qK_K/2+qK_K/4+qK_K/16:], byteOrder)
+
+		for j := 0; j < qK_K/16; j++ {
+			d := dAll * float32(int8(bScales[j]))
+			if d == 0 {
+				continue
+			}
+
+			for k := 0; k < 16; k++ {
+				l[16*j+k] = uint8(clamp(nearestInt(x[16*j+k]/d), -32, 31) + 32)
+			}
+		}
+
+		for j := 0; j < qK_K; j += 128 {
+			for k := 0; k < 32; k++ {
+				q1 := l[j+k] & 0x0f
+				q2 := l[j+k+32] & 0x0f
+				q3 := l[j+k+64] & 0x0f
+				q4 := l[j+k+96] & 0x0f
+
+				ql[k] = q1 | q3<<4
+				ql[k+32] = q2 | q4<<4
+				qh[k] = l[j+k]>>4 | (l[j+k+32]>>4)<<2 | (l[j+k+64]>>4)<<4 | (l[j+k+96]>>4)<<6
+			}
+
+			ql = ql[64:]
+			qh = qh[32:]
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package gguf
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"testing"
+)
+
+// testValues returns n values in [-scale, scale) from a linear
+// congruential generator. The same generator produced the inputs for
+// the ggml reference output below.
+func testValues(seed uint32, n int, scale float32) []float32 {
+	values := make([]float32, n)
+
+	for i := range values {
+		seed = seed*1664525 + 1013904223
+		values[i] = float32(int32(seed>>8)-1<<23) / (1 << 23) * scale
+	}
+
+	return values
+}
+
+// TestQuantizeReference checks that Quantize is bit-exact with ggml's
+// quantize_row_*_ref on the same input.
+func TestQuantizeReference(t *testing.T) {
+	cases := []struct {
+		typ   GGML
+		seed  uint32
+		n     int
+		scale float32
+		want  string
+	}{
+		{GgmlFloat32, 1, 32, 2.5, "22aba8bf675527bfa0c0ad3c1120833f78d30fc03b0427bf28d9af3fb6d78f3ee0b81ac0203c323f18b69fbf82b6c6beba2ae83e34a3d73f2a8da9bfe6de1940bcf7e63f74995dbf41bb693f1012213e12ccb5bf1e19fdbff26c09c02ed567bf1aebecbf3ea3843fdc30d43eea7a1abf9ab697bf3994a03f04d309406c8bcebf"},
+		{GgmlFloat32, 2, 32, 1000, "179403c4351aa0c25d861bc44de6f1438502d943ab623fc49c1913c4a7445dc2b6aa4942cab1c443422dcc43b26744c4561a074329601044c56392c2463cd4c2b7fa72c49bec9dc3ed977044358523c3b5834b44f15ca7431dc7f043e2d15ac388c9fcc3a6782fc499e41ec464ce30c4799955c47bc883c3ea6f12428a9aa7c3"},
+		{GgmlFloat32, 3, 32, 0, "0000008000000000000000000000000000000080000000000000000000000080000000800000000000000080000000000000000000000000000000000000000000000080000000800000008000000080000000000000008000000080000000800000008000000000000000000000000000000000000000000000008000000080"},
+		{GgmlFloat16, 1, 32, 2.5, "45bd3bb96e25193c7fc038b97f3d7f34d6c09239febc36b64137bd3e4cbdcf40383fedba4e3b0931aebde9bf4bc03fbb67bf253ca236d4b8bebc053d4f4074be"},
+		{GgmlFloat16, 2, 32, 1000, "1de001d5dce08f5fc85efbe199e0ead24d52265e615e23e23958836093d4a2d698e3efdc85631cd95c623b5d865fd7dae6df7ce1f7e086e1ade21edc93503ddd"},
+		{GgmlFloat16, 3, 32, 0, "00800000000000000080000000000080008000000080000000000000000000000080008000800080000000800080008000800000000000000000000000800080"},
+		{GgmlBFloat16, 1, 32, 2.5, "a9bf27bfae3c833f10c027bfb03f903e1bc0323fa0bfc7bee83ed83faabf1a40e73f5ebf6a3f213eb6bffdbf09c068bfedbf853fd43e1abf98bfa13f0a40cfbf"},
+		{GgmlBFloat16, 2, 32, 1000, "04c4a0c21cc4f243d9433fc413c45dc24a42c543cc4344c40743104492c2d4c273c49ec3714424c34c44a743f1435bc3fdc32fc41fc431c456c484c31242a8c3"},
+		{GgmlBFloat16, 3, 32, 0, "00800000000000000080000000000080008000000080000000000000000000000080008000800080000000800080008000800000000000000000000000800080"},
+		{GgmlQ4_0, 1, 64, 2.5, "d634e456b89b31161d5920ba94674acef43ff2b47b20baf719c032f5a1f0aeea359de86d"},
+		{GgmlQ4_0, 2, 64, 1000, "98570457f37cfcb2c368482b3b22196d8757ca57e5498de8050dac7e6baeabcf75f32ecf"},
+		{GgmlQ4_0, 3, 64, 0, "008088888888888888888888888888888888008088888888888888888888888888888888"},
+		{GgmlQ4_1, 1, 64, 2.5, "2535d6c0d355a88b31151c5820ba946649bde33f3335cfc095df5608e73fcd0b6e0f5225ca622792"},
+		{GgmlQ4_1, 2, 64, 1000, "085898e30357f36beba2b367482b3b21195c87570558cae3e5398ce7050dac7d5b9dabce74f32ece"},
+		{GgmlQ4_1, 3, 64, 0, "00000080000000000000000000000000000000000000008000000000000000000000000000000000"},
+		{GgmlQ5_0, 1, 64, 2.5, "d630ccb20d66c7ac6017713c29a2407538cd838be75ff2b015ecac6fd54054fe119054f941f15bc46a3bb0cb"},
+		{GgmlQ5_0, 2, 64, 1000, "98531837744007bff6d8d76386cf8146674322ca1faeca53e6cf4daed97319cf0a0a49ebb64c579ef9f55c9d"},
+		{GgmlQ5_0, 3, 64, 0, "0080ffffffff000000000000000000000000000000000080ffffffff00000000000000000000000000000000"},
+		{GgmlQ5_1, 1, 64, 2.5, "fa30d6c0ccb20d66b7ab5016613b28a1406428cd828ad75f0831cfc0ea1353902abfac01ee7fab06ce1ea44b95c54f35"},
+		{GgmlQ5_1, 2, 64, 1000, "cd5398e31837744007bef6d7d75376cf7046564322b90eaec853cae3e6cf4daeda7319cf0a0a49ecb64c579ef9f55d9d"},
+		{GgmlQ5_1, 3, 64, 0, "000000800000000000000000000000000000000000000000000000800000000000000000000000000000000000000000"},
+		{GgmlQ8_0, 1, 64, 2.5, "e024bbde01368ade480f8125beec1859ba7e5fd33008b5988fd09f3616e0c24271abfb24d57ce30cf77f6036767aa7df30aafeac1860d48474bb5586e28cd8a150e8a51d"},
+		{GgmlQ8_0, 2, 64, 1000, "a747bbf6af3f399cb3f907333599124bf6f281d77eeb6a2c3fe3bea4ada490de05d4da47cc1749f9d351465b305d376dcbaa636a63ba0661818420f0da1c2646f577a644"},
+		{GgmlQ8_0, 3, 64, 0, "0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"},
+		{GgmlQ2_K, 1, 256, 2.5, "ffccbcdcdeeeebffffeceeccdccdcb5ae5fd868af46d1e5aec3e61555ac3b5a3abed6642dca4bc51a412f6f11d5693087a5979156442ebd22eca89867bd56aa61618788e1fac545fa1b9d1a2c3294538f32e3b31"},
+		{GgmlQ2_K, 2, 256, 1000, "a9ce7aeeeebdecfcdcccffed8ab9fecec06a6c9773ec88be8aefbb8cb2c33e6d9ce5ab2db3d22ac594b8d8fcd4cdb1bda3ffc7caae7fc8fd5855b82b6d9dea475347ef75dc5f1b458b6ba4346e76344019511354"},
+		{GgmlQ2_K, 3, 256, 0, "000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"},
+		{GgmlQ3_K, 1, 256, 2.5, "fab8ffbfca907515f83122b7f50ffadbfecf0c51db0d6f592d006c2d7784e88366933431c1a306d87151ae8499be126f15dd0ed0fe4b7f326fa2ffb54e8e345778b4b0f09d411ba6cc96c7117b7b560da54dc8f963a9c4c7ae9f0e7f3a7b368d117778a88b8367700cc3c3f071a6"},
+		{GgmlQ3_K, 2, 256, 1000, "8ea1c0efb32cf4b97d79b92c3bffa140beb853e2099d93ae3cf2eeea9efee9aaf9bca5226ac4f55438b24a30197f10b35cbbf33f538126b31ec586c9416f140b26cefae72cddf78dd3919721b90da3ec6f5b0a5b616aff1bc31210e57419a90dbb6406b55dba59d6fc0c03f0b6c8"},
+		{GgmlQ3_K, 3, 256, 0, "0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"},
+		{GgmlQ4_K, 1, 256, 2.5, "ad1d1529fefbfafffcfcfdfebccafc2453f5678a70f5dbb8e0f92356a82c732e9cd45a07e331c104620a5825c36b2d929cbe6070cf4c07569a1f4b3736b27e9b8ae97841e6a99e659c24dece06368500a8a78676600abe1bd7ac9579be34ab586892b0c8fcc140de13a404163bb443a14d363c062951e9b517b290824ec6597825579ea154bc9797cbcdf7caf349823f"},
+		{GgmlQ4_K, 2, 256, 1000, "5c40044cfbfabbf5fdfcbdffabcffeae4497c37b5bd2c3d7b8dbbbe2492ce7e7e0358fe60e0aab765492a3c271f628c5e2695b844ce772ae90cabf818eb01e69a6ea7a1aacd80bd1a67fd7ffc8c38eaf0cdb3a9ad7dc90e47165a27cc4e4785f0d3dbb45e1abac357e7c6052c84942217bcfd2d47c5dc1bf6635ae0b3977bc435442e86ad46416618069890c467a2c70"},
+		{GgmlQ4_K, 3, 256, 0, "000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"},
+		{GgmlQ5_K, 1, 256, 2.5, "2a191d29fefefffffdfcfdffccdfff287c2e610b4c16f3933eb7a49073897c5d1f6ee5b13aecceb0cce18dcc92618962a7fbc016e1fbb861e0f448bd525af75f2bbaa501e773a20ac416a24c98c84d35598de001bf990ebc553e977f8d840c3724f2f191fd714dea3848ecad0d6d2b10525f0efec0066f28b04a3bf46f7959b3c2347092fb9291af374a192e687a97539b7d5a0d54a2d37a2f7430049d8ca3f05aa02e43a969303f888cfe95e693045f"},
+		{GgmlQ5_K, 2, 256, 1000, "223cfe4bfcfbfff5fcfdbffdbcedf0ce58f696b9657aa2ee2b0fef7a6d29f6161a1cf746ad3d3708b876ca4e3c5a4d0e973e95f7a6a396cf60c676e39259deeed07a1fcd0d0547ecb7445594f2fb409ac4c2a71878dff45c31936f021b801cb25cd4e51549b006b24cedbffe80870c4e09d88645deda40e902da541ab9f911cf1a7b989bf377597a0d19e0c4a0939542f7afc4c8f8ca928ddc6a5c0683ee8995b993e0d5c8c72be221e21218acf558f0"},
+		{GgmlQ5_K, 3, 256, 0, "0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"},
+		{GgmlQ6_K, 1, 256, 2.5, "df48f0ed03c832745129101b56766f0f965d333eb54ddf5d2b11bad9c1ce6117ab01c70d52201872a26136e80416017556f8f531fdef85d16883c65814ca491764be5dac814c6d4ff0840679ae937205d50821b4f6d5121e0e84425c40958f06adfb22ef49901dd598da67dfbdbf8cf60888af5c702c8d89ebe61fcd35a0dd0588c17ab6c841236690025d2966ff889fa8ee6541dfa3bf52b711f5f21f14900bba99f9d5e4822b12de0b4946bb11ab66eaa44072a34068631c052d1d3f9578c4846e7b857c86808384868677847a6c7c2391"},
+		{GgmlQ6_K, 2, 256, 1000, "8bad17f3f122797ee28010c185f76dbc71665f4bba0b2fc9802a1c09052851154f592be2dd48ac6522d580d0e0b91341593ea1284011080c36a70a72bdbed9410d0f73e5f3abbf3c6aaa672b8c3ccc41387a13c716f186452e556028a2c93a04008ccfc91cac692ec0ebdc0207ef32388ec30d5d6062a533a03ec4d8bbfe4d08f855506b0be07081b69387704ebf0151acd15b1d83e21af5a548e8cca4bd428d90ccf1e98e4cfbce6b268b185e6ec9749f8f23a91087d78d4ba768f8926af88c657d957d8087777b837379859e5f7983ff33"},
+		{GgmlQ6_K, 3, 256, 0, "000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"},
+	}
+
+	for _, c := range cases {
+		got, err := Quantize(c.typ, testValues(c.seed, c.n, c.scale), binary.LittleEndian)
+		if err != nil {
+			t.Errorf("%s/%d: %s", c.typ, c.seed, err)
+			continue
+		}
+
+		if hex.EncodeToString(got) != c.want {
+			t.Errorf("%s/%d: got %x, want %s", c.typ, c.seed, got, c.want)
+		}
+	}
+}
+
+// TestQuantizeRoundTrip checks the error of quantizing and
+// dequantizing, relative to the RMS of the input, in both byte orders.
+func TestQuantizeRoundTrip(t *testing.T) {
+	maxError := map[GGML]float64{
+		GgmlFloat32:  0,
+		GgmlFloat16:  0.0005,
+		GgmlBFloat16: 0.004,
+		GgmlQ4_0:     0.1,
+		GgmlQ4_1:     0.1,
+		GgmlQ5_0:     0.05,
+		GgmlQ5_1:     0.05,
+		GgmlQ8_0:     0.008,
+		GgmlQ2_K:     0.35,
+		GgmlQ3_K:     0.2,
+		GgmlQ4_K:     0.1,
+		GgmlQ5_K:     0.05,
+		GgmlQ6_K:     0.025,
+	}
+
+	src := testValues(4, 1024, 3)
+
+	for typ := range quantizers {
+		limit, found := maxError[typ]
+		if !found {
+			t.Errorf("%s: no error limit", typ)
+			continue
+		}
+
+		var want []float32
+
+		for _, byteOrder := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+			data, err := Quantize(typ, src, byteOrder)
+			if err != nil {
+				t.Fatalf("%s: Quantize: %s", typ, err)
+			}
+
+			got, err := Dequantize(typ, data, byteOrder)
+			if err != nil {
+				t.Fatalf("%s: Dequantize: %s", typ, err)
+			}
+
+			if want == nil {
+				want = got
+			} else {
+				for i := range got {
+					if got[i] != want[i] {
+						t.Errorf("%s: %s value %d is %v, little-endian gave %v", typ, byteOrder, i, got[i], want[i])
+						break
+					}
+				}
+			}
+
+			var sum, sumErr float64
+			for i := range src {
+				diff := float64(got[i] - src[i])
+				sum += float64(src[i]) * float64(src[i])
+				sumErr += diff * diff
+			}
+
+			e := math.Sqrt(sumErr / sum)
+			if e > limit {
+				t.Errorf("%s: %s relative error %g exceeds %g", typ, byteOrder, e, limit)
+			}
+		}
+	}
+}
+
+// testImportance returns importance weights for testValues(seed, n, _)
+// as used for the ggml reference output below.
+func testImportance(seed uint32, n int) []float32 {
+	importance := testValues(seed^0x1234, n, 1)
+
+	for i, v := range importance {
+		importance[i] = v*v + 0.25
+	}
+
+	return importance
+}
+
+// TestQuantizeWeightedReference checks that QuantizeWeighted is
+// bit-exact with ggml's quantize_q*_K with importance weights, which
+// use quantize_row_q*_K_impl, on the same input.
+func TestQuantizeWeightedReference(t *testing.T) {
+	cases := []struct {
+		typ  GGML
+		seed uint32
+		want string
+	}{
+		{GgmlQ2_K, 1, "ffccbdddceddfdffceeedadddc9c996ba5fd868af46d1e5aac3e61555ac3b5a3abeda642dca4bc51b412f6f11d5793087e5d39192442efd22dce8d8a7fd56ea606187c8e0fac545fa1b9d1a1c2294538942e2b31cadbcfbbfca8bbbb9accdcdd58daaafc58156582baf9fb94b7181391b80b11f77af1e9677f6c4a255bbc05b2e3065b3ab18ac427eaaefd89a1fc2ffe11ef6efac49708aa92b85880f8cdf1fe969f98b7802ff230"},
+		{GgmlQ2_K, 2, "9dbebcddddaddbdbcfbbefbc7aa9fdbdc4697c9676ec8cbd89eeba8cb5c23d6d9ce5ab2da3d22ac594b8d8fcd4cdb1bda2fec6caadaec8fd9855f82a6d9dea475343ef75dc5b1b418b6ba4345e763440a82e8131ddccec7add9bdf7b69eacdfebdfdcced813caefbb68c2c13b73a2ff0bead88b82c0079b7e0492ce3f6ddf09b0e821cee995ed87f6f03622ace6a3157ccd8244247ef6919b823552be4ff8db8bb3b3ee49f2e1a31"},
+		{GgmlQ3_K, 1, "fab8ffbfca907515f83122b6f50ffadbfecf0c51db0d6f592d006c3d7784e88366933431d1a306d87151ae8799ae126f15d90fd4fe4b7b356fa6ffb94a8e395678b8b0f09d415ba2cc96c7117bbb5a0da54ec9f866a9c5cbae9f0f7c3a7b368d433ab6d29c7488710cc3c3f0b1a63b4d7fa007071653341b8801f70a8906bca715ceacdd18bff895fbc6a68a98ed3fcd5187f83e3cf3fdebe5a1fb8ee9727f7d5d4566432f902af6d5fb9dd33a68ccdf66a82ee751a8ec07d0868d54614f87b54e5a7b6bfb4be2d5bcf732757f7598217816c87677d10ccc03c04626"},
+		{GgmlQ3_K, 2, "8ea1c0efb32cf4b97d59b92c3bffb140beb853e2099d93ae3cf26eea9efee9aaf9bca5236ac4f55038b24a3019bf10b35cbbf33f638136b31ed586d9416f141b26cefbe72cdef78dd39d8622b90d90bd6f5b0a67656efe0bc312d0e57825b6092299cd18f614737703f3fc0fc826f16ef72725420e69e5af1f44f7f712463ddb4d06e7792d56e7d587e4d87259e4d4ada972b9dad5a7fff0db65a998e1b293ef7da11e6d932630016b45974b761354cbd6dde864fa06fe36089dfac92ef685cade3d601ee60dd4ea05207f7234c8af53ed297c9961890ffcf3c04926"},
+		{GgmlQ4_K, 1, "a91d1229fdfbfdfffafcfebebcbafbe453f5678a70f5dbb8e0f92356a82c732e9cd45a07e331c004610a5825c36b2d929cbe6070cf4c07569a1e4b3736b27d9b89e87841e6a89e659b24ddce06368500a8a78676600aae1bd7ac9579ae34ab585892b0c8fcc140de13a404162bb443a14d362d062a41e9b507b290724eb6497825488f9144ac9887bbbdf7cae349722f6f1d3429fcfbf8faf6fcfcf7bffed889d2768529c8d3ee816cc12c05b1eb348baa25b55decf09a76cde2643a2e89bcb786176ac19efefe968f2424b49e3004ee5dfdfa596d58743a75ad03aeda02764c37a9544eaae9f5b728e2feea06defba8515e93cb3ac0d13193f616f97bffb16e9e97e63cfbabdeb59bff1dcf48ed5cefd1b6009aa79c56a1ece2cefe7796a7ae"},
+		{GgmlQ4_K, 2, "961d2829fcf9faf5fefbfdff9bcf10ae4497c37b5bd2c3d7b8ebbbf2492ce7e7e0358fe60e0aab765492a3c271f628c5e2695c844ce772ae90cabf818eb01e69a6ea7a1badd80bd1a67fd8ffc8c38eaf0cdb3a9ad6dc90e47165a28cc4e4785f0d3dbb45e1abac357e7c6052c84942217bcfd2d47b6dc1be6635ae0b3977bc435442e86ad46416618169890c467a2c70351d2b29fefffefefafafaf9ca9dcdfe07e1ca8c4bb2a11f6f88bf01cac77083e121957e2295f15c78e531cfdb49d2eaa02cbbffcd911924be0e0afcccd992ce49246eacea634aebfdf8dcc732d055e9a9caa4fecf0f2b9dfc9c386ed5b6550c6acb9493800c45aa42ccf371ad8ce741a767e67e7b336a29f27a1f57d1d71b7240f869159c1a7418d9ec809d9e3e0df8"},
+		{GgmlQ5_K, 1, "3f192f29fdfdfefdfcfbfcfdbbcdef2a7c2e610b4c16f3933eb7a41073897c5d1f2ee5b13aec8eb0cce18dcc92610962a7fbc016e1fbb861e0e448bd525af75f2bbaa501e673a20ac416a23c98c84d35598de001bf990ebc552e977f8d940c3724f2f191fd714dea3848ecad0d6d2b00625f0efec0066f28b04a3bf46f7959b3c2348092fb9291bf374a192e687a97539a7d5a0d5392c26a2f6420f49c7ca3e05aaf1e33a9582f2f777bee85d683f45f39190e29fafefafff8fefffccdceeb9bdab08659fffeefb8dde271f84ef370fd879c2ef597e62384e3aec0fd1db3a3d6a4dd0b538197bef2b9724b0c63c87af8454b5b9dc9f035ed8bc4b9664f046a7f0d3fc5722ceded2c1f5849792c7018cdabebd5b2caa1f975eb4a065c9415ed894043988d36b3eb6031b5eeb50cafe842a19d26775590934116dd1de3d7ff61af3c2dbb77f6469b6a25ee2a9d70c9b7cea26b00233d28ac32c7c49beced3b4e4b"},
+		{GgmlQ5_K, 2, "57191929fcfbfff4fcfefffdbbfd01cd58f696b9657aa2ee2b0fef7a6d29f6161a1cb746ad393708b876ca4e38da4d0e973e95f7a6a396ce60c676e39159deeed07a1fcd0c0547ecb7445594f2fb509ad4c2a71878dff45c31936e021b801cb25cd4f41549cf06b24cedbffe8f870c4e09d88645deda40e902da541ab9e911cf1b7b989bf377597a0d19e0c4a0939542f6aec4c8f7ca928ddc6a5c0583ee8895b993ffd4c8c72be221e11217ac0458f05d190329fcfcfafefffdfffef8fefffeb836af7f7d1a5671bd77571cafae481e16f0662dec52063dcdfe2ccff37962cf0ed3a5198665532ecf016e02959ff016d3532aec553ae2a9e1db728fa692b5d5414a68ef7c2323595f0e17da89a4359e9449de5ac5c896d7ecd1aa807590bcc4528448dd9e0f572ad92970dcab6caa18c497293700189a45a488e6f35b19cf833dbdbccad557c331c3e31e8d829e25d581e0c11a1613d82f92a7f0182b5a09d0"},
+		{GgmlQ6_K, 1, "df48f0ed03c832745129101b56766f0f965d332eb54def5d3b11cae9b1be6107abf1c70d52201872a26136e80416017556f9f530feee86d06882c65715ca481764be5dac804c6e40f0850679af926306d50821b4f6d5121e0e84425c40958f06adeb22df49901dd598da67dfbdbf8cf60888af5c702c8d89ebe61fcd35a0dd0588817ab6c841236690025d2966ff889fa8ee6541dfa3bf52b711f5f21f14900bba99f9d5e4822b13de0b4946bb11ab66eaa44072a34068631c052d1d3f9578c4846e7b897c8a80838986867784796d7c249196d578485c60617babfb2816591e0b7ed4b8b8358b0ea4b418a51be380055a6fe68c61eaa114189fb7505900ae09ce6f9617348c58a109031f66e833a7cf22e25f77bf12324789df5d16b13407d02d2b4b540250a4ff793ce3855579a160bc5196b68b0e1868c121b83891d7ff6a91756bfb0a70947d6b95bcb44cf2f570a2b95fd6aa017d3f3c5774cfd0527bccd234b53e26a8b0a385ea9073ca7d2cc594b54d652ad8155102655d03c001ae009005cb9807a59da3538fe7c2fef1599097b87479847f80aa7f7f767e7f7f6d857d8b1691"},
+		{GgmlQ6_K, 2, "8bad17f3f122796ee28010c185f76dbc70665f4bbb0b20c98f291b09042751154f592be2dd48ac6522d580d0e0b91341593ea1284011080c36a70a72bdbed9410d0f73e5f3abbf3c6aaa672b8c3ccc41387a13c716f186452e556028a2c93a04008ccfc91cac693ec0ebdc0207ef32389ec20d5d5062b532903ec4e8bbfe4d08f855506b0be07081b69387704ebf0151acd15b1d83e21bf5a448e8cca4bd428d90ccf1e98e4cfbce6b268b185e6ec9749f8f23a91087d78d4ba768f8926af88c667b947d8086777c82747a839d60798615110e37fbe39d5b662ddeb3dc452b7fa1ce7b2bcc25a6fcbddc7e2a2d00a20b7775606d1614f19fbc5439f2df31d4a581c4b46449425567c14b1233a0ed0abd87544d487384322073cb9d8d0156ce3c3060363c425ef18d1317681d0c36a3804d278b73bb9eb4908b45fdd60e0b577f15b217ec9a395f3ebd563c6e013154bc15cf41fc6e3b3648e8d377faeb303e2d4838a3dfb6782f86a32839223f20c1099321a96deb4c5c305118fc580264feea17714ae26514a42e5926e8f280b8b73731e881758a7a89857e7f6781867b7b8491802491"},
+	}
+
+	for _, c := range cases {
+		got, err := QuantizeWeighted(c.typ, testValues(c.seed, 512, 2.5), testImportance(c.seed, 512), binary.LittleEndian)
+		if err != nil {
+			t.Errorf("%s/%d: %s", c.typ, c.seed, err)
+			continue
+		}
+
+		if hex.EncodeToString(got) != c.want {
+			t.Errorf("%s/%d: got %x, want %s", c.typ, c.seed, got, c.want)
+		}
+	}
+}
+
+// TestQuantizeWeightedError checks that importance weights lower the
+// weighted error compared to quantizing without them.
+func TestQuantizeWeightedError(t *testing.T) {
+	const rowLength = 1024
+
+	src := testValues(5, 4*rowLength, 3)
+
+	// A few columns dominate, as in real activations.
+	importance := testValues(6, rowLength, 1)
+	for i, v := range importance {
+		importance[i] = v * v * v * v * 100
+	}
+
+	weightedError := func(data []byte, typ GGML) float64 {
+		got, err := Dequantize(typ, data, binary.LittleEndian)
+		if err != nil {
+			t.Fatalf("%s: Dequantize: %s", typ, err)
+		}
+
+		var sum float64
+
+		for i, v := range got {
+			d := float64(v - src[i])
+			sum += float64(importance[i%rowLength]) * d * d
+		}
+
+		return sum
+	}
+
+	for typ := range weightedQuantizers {
+		plain, err := Quantize(typ, src, binary.LittleEndian)
+		if err != nil {
+			t.Fatalf("%s: Quantize: %s", typ, err)
+		}
+
+		weighted, err := QuantizeWeighted(typ, src, importance, binary.LittleEndian)
+		if err != nil {
+			t.Fatalf("%s: QuantizeWeighted: %s", typ, err)
+		}
+
+		plainErr, weightedErr := weightedError(plain, typ), weightedError(weighted, typ)
+
+		if weightedErr >= plainErr {
+			t.Errorf("%s: weighted error %g is not below the unweighted %g", typ, weightedErr, plainErr)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package gguf
+
+import (
+	"io"
+	"sync"
+)
+
+// seekerReaderAt implements io.ReaderAt on top of an io.ReadSeeker by
+// serializing access to it.
+type seekerReaderAt struct {
+	sync.Mutex
+
+	rs io.ReadSeeker
+}
+
+// ReadAt implements io.ReaderAt.
+func (s *seekerReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	_, err := s.rs.Seek(off, io.SeekStart)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := io.ReadFull(s.rs, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+
+	return n, err
+}
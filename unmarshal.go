@@ -0,0 +1,440 @@
+package gguf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// archPlaceholder is replaced by the value of general.architecture in
+// gguf struct tags.
+const archPlaceholder = "{arch}"
+
+// fieldTag is a parsed gguf struct tag.
+type fieldTag struct {
+	key       string
+	required  bool
+	omitempty bool
+
+	// typ is the GGUF type given by the type option, used by Marshal.
+	// For arrays it's the type of the elements.
+	typ    Type
+	hasTyp bool
+}
+
+// parseTag parses a gguf struct tag of the form "key,option,...".
+func parseTag(tag string) (fieldTag, error) {
+	parts := strings.Split(tag, ",")
+
+	t := fieldTag{key: parts[0]}
+
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "required":
+			t.required = true
+
+		case "omitempty":
+			t.omitempty = true
+
+		default:
+			name, found := strings.CutPrefix(opt, "type=")
+			if found {
+				typ, err := parseType(name)
+				if err != nil {
+					return t, err
+				}
+
+				t.typ, t.hasTyp = typ, true
+
+				continue
+			}
+
+			return t, fmt.Errorf("unknown gguf tag option %q", opt)
+		}
+	}
+
+	if t.required && t.omitempty {
+		return t, fmt.Errorf("gguf tag %q is both required and omitempty", tag)
+	}
+
+	return t, nil
+}
+
+// taggedField is a struct field with a gguf tag.
+type taggedField struct {
+	tag   fieldTag
+	value reflect.Value
+	field reflect.StructField
+}
+
+// taggedFields returns all fields of the struct v with a gguf tag,
+// including those of embedded structs. Fields tagged "-" are skipped.
+func taggedFields(v reflect.Value) ([]taggedField, error) {
+	var fields []taggedField
+
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag, found := f.Tag.Lookup("gguf")
+
+		if !found && f.Anonymous && f.Type.Kind() == reflect.Struct {
+			embedded, err := taggedFields(v.Field(i))
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, embedded...)
+
+			continue
+		}
+
+		if !found || tag == "-" {
+			continue
+		}
+
+		if !f.IsExported() {
+			return nil, fmt.Errorf("field %s: gguf tag on unexported field", f.Name)
+		}
+
+		parsed, err := parseTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+
+		fields = append(fields, taggedField{tag: parsed, value: v.Field(i), field: f})
+	}
+
+	return fields, nil
+}
+
+// expandKey replaces {arch} in key with arch.
+func expandKey(key string, arch string) (string, error) {
+	if !strings.Contains(key, archPlaceholder) {
+		return key, nil
+	}
+
+	if arch == "" {
+		return "", fmt.Errorf("key %q needs general.architecture, which is not set", key)
+	}
+
+	return strings.ReplaceAll(key, archPlaceholder, arch), nil
+}
+
+// Unmarshal stores metadata values in the fields of the struct pointed
+// to by v. Fields are matched by their gguf struct tag, the name of
+// the key, optionally followed by options:
+//
+//	ContextLength int     `gguf:"{arch}.context_length,required"`
+//	FreqBase      float64 `gguf:"llama.rope.freq_base"`
+//	Tokens        []string `gguf:"tokenizer.ggml.tokens,omitempty"`
+//
+// {arch} is replaced by the value of general.architecture. Fields
+// without a tag are ignored, except embedded structs whose fields are
+// matched as well. Fields of keys not found are left unchanged, unless
+// the "required" option is given; all missing required keys are
+// reported in a *MissingKeysError. The "omitempty" and "type" options
+// only affect MarshalMetadata.
+//
+// Numbers are converted to the type of the field if the value can be
+// represented exactly, so a negative value is never stored in an
+// unsigned field and 3.5 is never stored in an int. Arrays are stored
+// in slices, arrays of arrays in slices of slices. Pointer fields are
+// allocated when the key is found, which makes it possible to tell
+// missing keys from zero values.
+func (m Metadata) Unmarshal(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("unmarshal needs a non-nil pointer to a struct, got %T", v)
+	}
+
+	fields, err := taggedFields(rv.Elem())
+	if err != nil {
+		return err
+	}
+
+	arch, _ := m["general.architecture"].(string)
+
+	var missing []string
+
+	for _, f := range fields {
+		key, err := expandKey(f.tag.key, arch)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.field.Name, err)
+		}
+
+		value, found := m[key]
+		if !found {
+			if f.tag.required {
+				missing = append(missing, key)
+			}
+
+			continue
+		}
+
+		err = assignValue(f.value, value)
+		if err != nil {
+			return fmt.Errorf("metadata value %q: field %s: %w", key, f.field.Name, err)
+		}
+	}
+
+	if len(missing) > 0 {
+		return &MissingKeysError{Architecture: arch, Keys: missing}
+	}
+
+	return nil
+}
+
+// errNotExact is returned when a number cannot be represented exactly
+// in the destination type.
+var errNotExact = errors.New("cannot be represented exactly")
+
+// assignValue stores the metadata value src in dst, converting numbers
+// and arrays as needed.
+func assignValue(dst reflect.Value, src interface{}) error {
+	sv := reflect.ValueOf(src)
+
+	// A nil interface, like a nil element of an []interface{}, has no
+	// value to convert.
+	if !sv.IsValid() {
+		return fmt.Errorf("nil cannot be stored in %s", dst.Type())
+	}
+
+	switch dst.Kind() {
+	case reflect.Pointer:
+		p := reflect.New(dst.Type().Elem())
+
+		err := assignValue(p.Elem(), src)
+		if err != nil {
+			return err
+		}
+
+		dst.Set(p)
+
+		return nil
+
+	case reflect.Interface:
+		if !sv.Type().AssignableTo(dst.Type()) {
+			return fmt.Errorf("%T cannot be stored in %s", src, dst.Type())
+		}
+
+		dst.Set(sv)
+
+		return nil
+
+	case reflect.Bool, reflect.String:
+		if sv.Kind() != dst.Kind() {
+			return fmt.Errorf("%T cannot be stored in %s", src, dst.Type())
+		}
+
+		dst.Set(sv.Convert(dst.Type()))
+
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := exactInt(sv)
+		if err != nil {
+			return err
+		}
+
+		if dst.OverflowInt(i) {
+			return fmt.Errorf("%v overflows %s", src, dst.Type())
+		}
+
+		dst.SetInt(i)
+
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := exactUint(sv)
+		if err != nil {
+			return err
+		}
+
+		if dst.OverflowUint(u) {
+			return fmt.Errorf("%v overflows %s", src, dst.Type())
+		}
+
+		dst.SetUint(u)
+
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		f, err := exactFloat(sv, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		if dst.OverflowFloat(f) {
+			return fmt.Errorf("%v overflows %s", src, dst.Type())
+		}
+
+		dst.SetFloat(f)
+
+		return nil
+
+	case reflect.Slice:
+		elems, err := arrayElements(src)
+		if err != nil {
+			return err
+		}
+
+		s := reflect.MakeSlice(dst.Type(), len(elems), len(elems))
+
+		for i, e := range elems {
+			err = assignValue(s.Index(i), e)
+			if err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+
+		dst.Set(s)
+
+		return nil
+
+	case reflect.Array:
+		elems, err := arrayElements(src)
+		if err != nil {
+			return err
+		}
+
+		if len(elems) != dst.Len() {
+			return fmt.Errorf("%d elements cannot be stored in %s", len(elems), dst.Type())
+		}
+
+		for i, e := range elems {
+			err = assignValue(dst.Index(i), e)
+			if err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported field type %s", dst.Type())
+	}
+}
+
+// arrayElements returns the elements of a metadata array. The
+// elements of arrays of arrays are the values of the nested arrays.
+func arrayElements(src interface{}) ([]interface{}, error) {
+	if nested, ok := src.([]MetaArray); ok {
+		elems := make([]interface{}, len(nested))
+
+		for i, n := range nested {
+			elems[i] = n.Value
+		}
+
+		return elems, nil
+	}
+
+	sv := reflect.ValueOf(src)
+	if sv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("%T is not an array", src)
+	}
+
+	elems := make([]interface{}, sv.Len())
+
+	for i := range elems {
+		elems[i] = sv.Index(i).Interface()
+	}
+
+	return elems, nil
+}
+
+// exactInt returns the numeric value sv as an int64, if it can be
+// represented exactly.
+func exactInt(sv reflect.Value) (int64, error) {
+	switch sv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return sv.Int(), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if sv.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("%d %w as an integer", sv.Uint(), errNotExact)
+		}
+
+		return int64(sv.Uint()), nil
+
+	case reflect.Float32, reflect.Float64:
+		f := sv.Float()
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, fmt.Errorf("%v %w as an integer", f, errNotExact)
+		}
+
+		return int64(f), nil
+
+	default:
+		return 0, fmt.Errorf("%s is not a number", sv.Type())
+	}
+}
+
+// exactUint returns the numeric value sv as a uint64, if it can be
+// represented exactly.
+func exactUint(sv reflect.Value) (uint64, error) {
+	switch sv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if sv.Int() < 0 {
+			return 0, fmt.Errorf("%d %w as an unsigned integer", sv.Int(), errNotExact)
+		}
+
+		return uint64(sv.Int()), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return sv.Uint(), nil
+
+	case reflect.Float32, reflect.Float64:
+		f := sv.Float()
+		if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+			return 0, fmt.Errorf("%v %w as an unsigned integer", f, errNotExact)
+		}
+
+		return uint64(f), nil
+
+	default:
+		return 0, fmt.Errorf("%s is not a number", sv.Type())
+	}
+}
+
+// exactFloat returns the numeric value sv as a float64. Integers must be
+// represented exactly by a float of bitSize bits, while floats are only
+// rounded.
+func exactFloat(sv reflect.Value, bitSize int) (float64, error) {
+	var f float64
+	var exact bool
+
+	switch sv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = roundFloat(float64(sv.Int()), bitSize)
+		exact = f >= math.MinInt64 && f < math.MaxInt64 && int64(f) == sv.Int()
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f = roundFloat(float64(sv.Uint()), bitSize)
+		exact = f < math.MaxUint64 && uint64(f) == sv.Uint()
+
+	case reflect.Float32, reflect.Float64:
+		return sv.Float(), nil
+
+	default:
+		return 0, fmt.Errorf("%s is not a number", sv.Type())
+	}
+
+	if !exact {
+		return 0, fmt.Errorf("%v %w as a %d-bit float", sv.Interface(), errNotExact, bitSize)
+	}
+
+	return f, nil
+}
+
+// roundFloat rounds f to the precision of a float of bitSize bits.
+func roundFloat(f float64, bitSize int) float64 {
+	if bitSize == 32 {
+		return float64(float32(f))
+	}
+
+	return f
+}
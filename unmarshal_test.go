@@ -0,0 +1,256 @@
+package gguf
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	cases := []struct {
+		tag      string
+		expected fieldTag
+		err      string
+	}{
+		{tag: "a.b", expected: fieldTag{key: "a.b"}},
+		{tag: "{arch}.b,required", expected: fieldTag{key: "{arch}.b", required: true}},
+		{tag: "a,omitempty,type=uint32", expected: fieldTag{key: "a", omitempty: true, typ: Uint32, hasTyp: true}},
+		{tag: "a,type=float64,required", expected: fieldTag{key: "a", required: true, typ: Float64, hasTyp: true}},
+		{tag: "a,type=uint8", expected: fieldTag{key: "a", typ: Uint8, hasTyp: true}},
+		{tag: "a,type=array", err: "unknown scalar type"},
+		{tag: "a,type=int", err: "unknown scalar type"},
+		{tag: "a,optional", err: "unknown gguf tag option"},
+		{tag: "a,", err: "unknown gguf tag option"},
+		{tag: "a,required,omitempty", err: "both required and omitempty"},
+	}
+
+	for _, c := range cases {
+		tag, err := parseTag(c.tag)
+
+		if c.err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("%q: expected an error containing %q, got %v", c.tag, c.err, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%q: %s", c.tag, err)
+
+			continue
+		}
+
+		if tag != c.expected {
+			t.Errorf("%q: expected %+v, got %+v", c.tag, c.expected, tag)
+		}
+	}
+}
+
+type unmarshalEmbedded struct {
+	ContextLength uint64 `gguf:"{arch}.context_length"`
+}
+
+type unmarshalTest struct {
+	unmarshalEmbedded
+
+	Arch       string        `gguf:"general.architecture,required"`
+	BlockCount int           `gguf:"{arch}.block_count,required"`
+	FreqBase   float64       `gguf:"{arch}.rope.freq_base,omitempty"`
+	HeadCount  []int16       `gguf:"{arch}.attention.head_count,type=uint32"`
+	Pair       [2]uint8      `gguf:"test.pair"`
+	Nested     [][]int       `gguf:"test.nested"`
+	Tokens     []string      `gguf:"tokenizer.ggml.tokens"`
+	Flag       *bool         `gguf:"test.flag"`
+	Scale      *float32      `gguf:"{arch}.rope.scale"`
+	Any        interface{}   `gguf:"test.any"`
+	Missing    uint32        `gguf:"test.missing"`
+	Ignored    string        `gguf:"-"`
+	Untagged   string        // Not tagged, so never set.
+	Nested3    [][][]float32 `gguf:"test.nested3"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	m := Metadata{
+		"general.architecture":       "llama",
+		"llama.context_length":       uint32(4096),
+		"llama.block_count":          uint32(32),
+		"llama.rope.freq_base":       float32(10000),
+		"llama.attention.head_count": []uint32{32, 32, 8},
+		"test.pair":                  []int64{1, 255},
+		"test.nested": []MetaArray{
+			{Type: Int32, Value: []int32{1, -2}},
+			{Type: Uint8, Value: []uint8{}},
+			{Type: Float64, Value: []float64{3}},
+		},
+		"tokenizer.ggml.tokens": []string{"<s>", " the"},
+		"test.flag":             false,
+		"test.any":              uint64(1 << 63),
+		"test.nested3": []MetaArray{
+			{Type: Array, Value: []MetaArray{{Type: Float32, Value: []float32{0.5}}}},
+			{Type: Array, Value: []MetaArray{}},
+		},
+		"Ignored":  "x",
+		"Untagged": "x",
+	}
+
+	// Fields of missing keys are left unchanged.
+	v := unmarshalTest{Missing: 7}
+
+	err := m.Unmarshal(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flag := false
+
+	expected := unmarshalTest{
+		unmarshalEmbedded: unmarshalEmbedded{ContextLength: 4096},
+
+		Arch:       "llama",
+		BlockCount: 32,
+		FreqBase:   10000,
+		HeadCount:  []int16{32, 32, 8},
+		Pair:       [2]uint8{1, 255},
+		Nested:     [][]int{{1, -2}, {}, {3}},
+		Tokens:     []string{"<s>", " the"},
+		Flag:       &flag,
+		Any:        uint64(1 << 63),
+		Missing:    7,
+		Nested3:    [][][]float32{{{0.5}}, {}},
+	}
+
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("expected %+v, got %+v", expected, v)
+	}
+
+	// Pointers tell missing keys from zero values.
+	if v.Flag == nil || v.Scale != nil {
+		t.Errorf("expected Flag to be set and Scale to be nil, got %v and %v", v.Flag, v.Scale)
+	}
+}
+
+func TestUnmarshalMissing(t *testing.T) {
+	m := Metadata{
+		"general.architecture": "llama",
+		"llama.rope.freq_base": float32(10000),
+	}
+
+	var v unmarshalTest
+
+	err := m.Unmarshal(&v)
+
+	var missing *MissingKeysError
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected *MissingKeysError, got %v", err)
+	}
+
+	// omitempty doesn't make a key required.
+	expected := &MissingKeysError{Architecture: "llama", Keys: []string{"llama.block_count"}}
+	if !reflect.DeepEqual(missing, expected) {
+		t.Errorf("expected %+v, got %+v", expected, missing)
+	}
+
+	// Keys found are still stored.
+	if v.FreqBase != 10000 {
+		t.Errorf("expected FreqBase to be set, got %v", v.FreqBase)
+	}
+
+	// Every missing key is reported, {arch} can't be expanded
+	// without general.architecture.
+	err = Metadata{}.Unmarshal(&struct {
+		A uint32 `gguf:"a,required"`
+		B uint32 `gguf:"b,required"`
+	}{})
+	if !errors.As(err, &missing) || !reflect.DeepEqual(missing.Keys, []string{"a", "b"}) {
+		t.Errorf("expected a and b to be missing, got %v", err)
+	}
+
+	err = Metadata{}.Unmarshal(&v)
+	if err == nil || !strings.Contains(err.Error(), "needs general.architecture") {
+		t.Errorf("expected an error for {arch}, got %v", err)
+	}
+}
+
+func TestUnmarshalConversion(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    interface{}
+		field    interface{}
+		notExact bool
+		err      string
+	}{
+		{name: "negative to unsigned", value: int32(-1), field: new(uint32), notExact: true},
+		{name: "negative to uint8", value: int8(-128), field: new(uint8), notExact: true},
+		{name: "fraction to int", value: float32(3.5), field: new(int), notExact: true},
+		{name: "NaN to int", value: math.NaN(), field: new(int64), notExact: true},
+		{name: "float beyond int64", value: float64(1 << 63), field: new(int64), notExact: true},
+		{name: "uint64 beyond int64", value: uint64(math.MaxUint64), field: new(int64), notExact: true},
+		{name: "integer to float32", value: int64(1<<24 + 1), field: new(float32), notExact: true},
+		{name: "integer to float64", value: uint64(1<<53 + 1), field: new(float64), notExact: true},
+		{name: "uint32 overflow", value: uint64(1 << 32), field: new(uint32), err: "overflows uint32"},
+		{name: "int8 overflow", value: int16(128), field: new(int8), err: "overflows int8"},
+		{name: "float32 overflow", value: math.MaxFloat64, field: new(float32), err: "overflows float32"},
+		{name: "string to int", value: "1", field: new(int), err: "string is not a number"},
+		{name: "number to string", value: uint32(1), field: new(string), err: "cannot be stored in string"},
+		{name: "bool to int", value: true, field: new(int), err: "bool is not a number"},
+		{name: "scalar to slice", value: uint32(1), field: new([]uint32), err: "is not an array"},
+		{name: "array to scalar", value: []uint32{1}, field: new(uint32), err: "is not a number"},
+		{name: "array element", value: []int32{1, -1}, field: new([]uint32), notExact: true, err: "element 1"},
+		{name: "nested element", value: []MetaArray{{Type: Float32, Value: []float32{0.5}}}, field: new([][]int), notExact: true, err: "element 0: element 0"},
+		{name: "array length", value: []uint8{1, 2, 3}, field: new([2]uint8), err: "3 elements cannot be stored in [2]uint8"},
+		{name: "interface", value: uint32(1), field: new(error), err: "cannot be stored in error"},
+		{name: "unsupported", value: map[string]int{}, field: new(map[string]int), err: "unsupported field type"},
+	}
+
+	for _, c := range cases {
+		// A struct with the single field A of the type c.field
+		// points to.
+		typ := reflect.StructOf([]reflect.StructField{{
+			Name: "A",
+			Type: reflect.TypeOf(c.field).Elem(),
+			Tag:  `gguf:"test.a"`,
+		}})
+
+		err := Metadata{"test.a": c.value}.Unmarshal(reflect.New(typ).Interface())
+		if err == nil {
+			t.Errorf("%s: expected an error", c.name)
+
+			continue
+		}
+
+		if c.notExact && !errors.Is(err, errNotExact) {
+			t.Errorf("%s: expected errNotExact, got %v", c.name, err)
+		}
+
+		if !strings.Contains(err.Error(), c.err) || !strings.Contains(err.Error(), `metadata value "test.a": field A`) {
+			t.Errorf("%s: expected an error about test.a containing %q, got %v", c.name, c.err, err)
+		}
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	m := Metadata{"a": uint32(1)}
+
+	invalid := []interface{}{
+		nil,
+		unmarshalTest{},
+		(*unmarshalTest)(nil),
+		new(int),
+		&struct {
+			a uint32 `gguf:"a"`
+		}{},
+		&struct {
+			A uint32 `gguf:"a,bogus"`
+		}{},
+	}
+
+	for _, v := range invalid {
+		err := m.Unmarshal(v)
+		if err == nil {
+			t.Errorf("%T: expected an error", v)
+		}
+	}
+}
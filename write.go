@@ -0,0 +1,23 @@
+package gguf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// write writes a value of type T to a binary stream.
+func write[T readables](w io.Writer, byteorder binary.ByteOrder, v T) error {
+	return binary.Write(w, byteorder, v)
+}
+
+// writeCast casts v to type C and writes it to a binary stream. An
+// error is returned if v cannot be represented as C.
+func writeCast[T, C readables](w io.Writer, byteorder binary.ByteOrder, v T) error {
+	c := C(v)
+	if T(c) != v || (c < 0) != (v < 0) {
+		return fmt.Errorf("%v overflows %T", v, c)
+	}
+
+	return binary.Write(w, byteorder, c)
+}